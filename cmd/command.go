@@ -1,44 +1,164 @@
 package cmd
 
 import (
+	"context"
 	"fmt"
-	"log"
 	"os"
+	"strconv"
+	"strings"
+	"text/tabwriter"
 
 	"github.com/Shabrinashsf/go-gin-gorm-boilerplate/database"
+	dbseed "github.com/Shabrinashsf/go-gin-gorm-boilerplate/database/seed"
+	"github.com/Shabrinashsf/go-gin-gorm-boilerplate/utils/logger"
 	"gorm.io/gorm"
 )
 
 func Command(db *gorm.DB) {
-	migrate := false
+	migrateUp := false
+	migrateDown := 0
+	migrateStatus := false
+	migrateCreate := ""
 	seed := false
+	seedNames := []string{}
+	seedOpts := dbseed.Options{Environment: dbseed.ENV_DEV}
 	help := false
 
-	for _, arg := range os.Args[1:] {
+	args := os.Args[1:]
+	for i := 0; i < len(args); i++ {
+		arg, value, hasValue := strings.Cut(args[i], "=")
+		// flags taking a value accept both "--flag value" and "--flag=value"
+		nextValue := func() string {
+			if hasValue {
+				return value
+			}
+			if i+1 < len(args) && !strings.HasPrefix(args[i+1], "--") {
+				i++
+				return args[i]
+			}
+			return ""
+		}
+
 		switch arg {
-		case "--migrate":
-			migrate = true
+		case "--migrate", "--migrate-up":
+			migrateUp = true
+		case "--migrate-down":
+			raw := nextValue()
+			if raw == "" {
+				raw = "1"
+			}
+			steps, err := strconv.Atoi(raw)
+			if err != nil || steps < 1 {
+				logger.Fatalf("--migrate-down expects a positive number, got %q", raw)
+			}
+			migrateDown = steps
+		case "--migrate-status":
+			migrateStatus = true
+		case "--migrate-create":
+			migrateCreate = nextValue()
+			if migrateCreate == "" {
+				logger.Fatalf("--migrate-create expects a name")
+			}
 		case "--seed":
 			seed = true
+			if hasValue {
+				for _, name := range strings.Split(value, ",") {
+					if name = strings.TrimSpace(name); name != "" {
+						seedNames = append(seedNames, name)
+					}
+				}
+			}
+		case "--seed-env":
+			seedOpts.Environment = nextValue()
+		case "--seed-count":
+			raw := nextValue()
+			count, err := strconv.Atoi(raw)
+			if err != nil || count < 1 {
+				logger.Fatalf("--seed-count expects a positive number, got %q", raw)
+			}
+			seedOpts.Count = count
 		case "--help":
 			help = true
 		}
 	}
 
-	if migrate {
-		log.Println("Running migration...")
-		if err := database.Migrate(db); err != nil {
-			log.Fatalf("Error migration: %v", err)
+	if migrateCreate != "" {
+		paths, err := database.CreateMigration(migrateCreate)
+		if err != nil {
+			logger.Fatalf("Error creating migration: %v", err)
+		}
+		for _, path := range paths {
+			logger.Infof("Created %s", path)
+		}
+	}
+
+	if migrateUp || migrateDown > 0 || migrateStatus {
+		m, err := database.NewMigrator(db)
+		if err != nil {
+			logger.Fatalf("Error loading migrations: %v", err)
+		}
+		ctx := context.Background()
+
+		if migrateUp {
+			logger.Infof("Running migration...")
+			applied, err := m.Up(ctx)
+			for _, migration := range applied {
+				logger.Infof("Applied %06d_%s", migration.Version, migration.Name)
+			}
+			if err != nil {
+				logger.Fatalf("Error migration: %v", err)
+			}
+			logger.Infof("✅ Migration completed successfully.")
+		}
+
+		if migrateDown > 0 {
+			logger.Infof("Rolling back %d migration(s)...", migrateDown)
+			reverted, err := m.Down(ctx, migrateDown)
+			for _, migration := range reverted {
+				logger.Infof("Reverted %06d_%s", migration.Version, migration.Name)
+			}
+			if err != nil {
+				logger.Fatalf("Error rollback: %v", err)
+			}
+			logger.Infof("✅ Rollback completed successfully.")
+		}
+
+		if migrateStatus {
+			statuses, err := m.Status(ctx)
+			if err != nil {
+				logger.Fatalf("Error migration status: %v", err)
+			}
+
+			w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+			fmt.Fprintln(w, "VERSION\tNAME\tSOURCE\tSTATUS\tAPPLIED AT")
+			for _, status := range statuses {
+				state, appliedAt := "pending", "-"
+				if status.Applied {
+					state = "applied"
+					appliedAt = status.AppliedAt.Format("2006-01-02 15:04:05")
+				}
+				if status.Modified {
+					state = "modified"
+				}
+				if status.Missing {
+					state = "missing"
+				}
+				fmt.Fprintf(w, "%06d\t%s\t%s\t%s\t%s\n", status.Version, status.Name, status.Source, state, appliedAt)
+			}
+			w.Flush()
 		}
-		log.Println("✅ Migration completed successfully.")
 	}
 
 	if seed {
-		log.Println("Running seeder...")
-		if err := database.Seeder(db); err != nil {
-			log.Fatalf("Error seeding: %v", err)
+		logger.Infof("Running %s seeders...", seedOpts.Environment)
+		done, err := database.Seeder(db, seedOpts, seedNames...)
+		for _, name := range done {
+			logger.Infof("Seeded %s", name)
+		}
+		if err != nil {
+			logger.Fatalf("Error seeding: %v", err)
 		}
-		log.Println("✅ Seeding completed successfully.")
+		logger.Infof("✅ Seeding completed successfully.")
 	}
 
 	if help {
@@ -49,13 +169,27 @@ func Command(db *gorm.DB) {
 		go run main.go [command]
 
 		Commands:
-			--migrate    Run database migrations
-			--seed       Run database seeders
-			--help       Show this help message
+			--migrate-up             Apply all pending migrations (alias: --migrate)
+			--migrate-down N         Roll back the last N migrations (default 1)
+			--migrate-status         Show applied and pending migrations
+			--migrate-create name    Create a new numbered up/down SQL migration
+			--seed                   Run every seeder of the seed environment
+			--seed=name[,name]       Run the named seeders and their dependencies
+			--seed-env env           Seed environment: dev (default), demo or test
+			--seed-count N           Rows created by fake seeders (default 1000)
+			--mail-preview [name]    Render an email template with sample data, lists templates without a name
+			--mail-locale locale     Locale of the previewed template: id (default) or en
+			--mail-data file.json    JSON object merged over the sample data
+			--help                   Show this help message
 
 		Examples:
-			go run main.go --migrate
+			go run main.go --migrate-up
+			go run main.go --migrate-down 1
+			go run main.go --migrate-create add_products_table
 			go run main.go --seed
+			go run main.go --seed --seed-env demo --seed-count 5000
+			go run main.go --seed=fake_transactions
+			go run main.go --mail-preview verification_email --mail-locale en
 			go run main.go --help
 		`)
 	}
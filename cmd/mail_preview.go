@@ -0,0 +1,99 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/Shabrinashsf/go-gin-gorm-boilerplate/utils/i18n"
+	"github.com/Shabrinashsf/go-gin-gorm-boilerplate/utils/logger"
+	"github.com/Shabrinashsf/go-gin-gorm-boilerplate/utils/mailer"
+)
+
+// MAIL_PREVIEW_DIR is where --mail-preview writes the rendered HTML.
+var MAIL_PREVIEW_DIR = "tmp/mail-preview"
+
+// Standalone runs the commands that need neither the configuration nor the
+// database and reports whether one was found in args.
+func Standalone(args []string) bool {
+	preview := false
+	name := ""
+	locale := i18n.DEFAULT_LOCALE
+	dataFile := ""
+
+	for i := 0; i < len(args); i++ {
+		arg, value, hasValue := strings.Cut(args[i], "=")
+		nextValue := func() string {
+			if hasValue {
+				return value
+			}
+			if i+1 < len(args) && !strings.HasPrefix(args[i+1], "--") {
+				i++
+				return args[i]
+			}
+			return ""
+		}
+
+		switch arg {
+		case "--mail-preview":
+			preview = true
+			name = nextValue()
+		case "--mail-locale":
+			locale = nextValue()
+		case "--mail-data":
+			dataFile = nextValue()
+		}
+	}
+
+	if !preview {
+		return false
+	}
+
+	if name == "" {
+		names, err := mailer.TemplateNames()
+		if err != nil {
+			logger.Fatalf("Error loading email templates: %v", err)
+		}
+		fmt.Println("Email templates:")
+		for _, name := range names {
+			fmt.Println("  " + name)
+		}
+		return true
+	}
+
+	if !i18n.Supported(locale) {
+		logger.Fatalf("--mail-locale must be one of %s, got %q", strings.Join(i18n.LOCALES, "/"), locale)
+	}
+
+	data := map[string]any{}
+	for key, value := range mailer.PREVIEW_DATA[name] {
+		data[key] = value
+	}
+	if dataFile != "" {
+		raw, err := os.ReadFile(dataFile)
+		if err != nil {
+			logger.Fatalf("Error reading --mail-data: %v", err)
+		}
+		if err := json.Unmarshal(raw, &data); err != nil {
+			logger.Fatalf("--mail-data must be a JSON object: %v", err)
+		}
+	}
+
+	email, err := mailer.Render(name, locale, data)
+	if err != nil {
+		logger.Fatalf("Error rendering %s: %v", name, err)
+	}
+
+	path := filepath.Join(MAIL_PREVIEW_DIR, name+"."+locale+".html")
+	if err := os.MkdirAll(MAIL_PREVIEW_DIR, 0o755); err != nil {
+		logger.Fatalf("Error creating %s: %v", MAIL_PREVIEW_DIR, err)
+	}
+	if err := os.WriteFile(path, []byte(email.Body), 0o644); err != nil {
+		logger.Fatalf("Error writing preview: %v", err)
+	}
+
+	fmt.Printf("Subject: %s\nHTML:    %s\n\n%s", email.Subject, path, email.Text)
+	return true
+}
@@ -0,0 +1,381 @@
+package config
+
+import (
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pelletier/go-toml/v2"
+	"gopkg.in/yaml.v3"
+)
+
+// Config holds every setting the application reads at startup. Values are
+// resolved in order: `default` tag, optional CONFIG_FILE (YAML or TOML),
+// environment variables, then <ENV>_FILE secret files.
+type (
+	Config struct {
+		App      AppConfig      `yaml:"app" toml:"app"`
+		Database DatabaseConfig `yaml:"database" toml:"database"`
+		JWT      JWTConfig      `yaml:"jwt" toml:"jwt"`
+		AES      AESConfig      `yaml:"aes" toml:"aes"`
+		Mail     MailConfig     `yaml:"mail" toml:"mail"`
+		SMTP     SMTPConfig     `yaml:"smtp" toml:"smtp"`
+		Outbox   OutboxConfig   `yaml:"outbox" toml:"outbox"`
+		AWS      AWSConfig      `yaml:"aws" toml:"aws"`
+		Tripay   TripayConfig   `yaml:"tripay" toml:"tripay"`
+		Security SecurityConfig `yaml:"security" toml:"security"`
+		Metrics  MetricsConfig  `yaml:"metrics" toml:"metrics"`
+		Tracing  TracingConfig  `yaml:"tracing" toml:"tracing"`
+		Log      LogConfig      `yaml:"log" toml:"log"`
+	}
+
+	AppConfig struct {
+		Env          string `yaml:"env" toml:"env" env:"APP_ENV" default:"localhost"` // localhost/development/production/testing
+		Port         int    `yaml:"port" toml:"port" env:"APP_PORT" default:"8888"`
+		URL          string `yaml:"url" toml:"url" env:"APP_URL"` // frontend, used in email links
+		IsProduction bool   `yaml:"is_production" toml:"is_production" env:"IS_PRODUCTION" default:"true"`
+
+		ShutdownDrainDelay time.Duration `yaml:"shutdown_drain_delay" toml:"shutdown_drain_delay" env:"SHUTDOWN_DRAIN_DELAY" default:"0s"` // time /readyz reports draining before the HTTP server stops
+		TrustedProxies     string        `yaml:"trusted_proxies" toml:"trusted_proxies" env:"TRUSTED_PROXIES"`                             // comma separated IPs/CIDRs allowed to set X-Forwarded-For, empty trusts none
+	}
+
+	DatabaseConfig struct {
+		Host     string `yaml:"host" toml:"host" env:"DB_HOST" default:"localhost"`
+		Port     int    `yaml:"port" toml:"port" env:"DB_PORT" default:"5432"`
+		User     string `yaml:"user" toml:"user" env:"DB_USER"`
+		Password string `yaml:"password" toml:"password" env:"DB_PASS"`
+		Name     string `yaml:"name" toml:"name" env:"DB_NAME"`
+		TimeZone string `yaml:"timezone" toml:"timezone" env:"DB_TIMEZONE" default:"Asia/Jakarta"`
+		SSLMode  string `yaml:"sslmode" toml:"sslmode" env:"DB_SSLMODE" default:"disable"`
+		Options  string `yaml:"options" toml:"options" env:"DB_OPTIONS"` // extra key=value DSN pairs, space separated
+
+		MaxOpenConns    int           `yaml:"max_open_conns" toml:"max_open_conns" env:"DB_MAX_OPEN_CONNS" default:"25"` // 0 means unlimited
+		MaxIdleConns    int           `yaml:"max_idle_conns" toml:"max_idle_conns" env:"DB_MAX_IDLE_CONNS" default:"10"`
+		ConnMaxLifetime time.Duration `yaml:"conn_max_lifetime" toml:"conn_max_lifetime" env:"DB_CONN_MAX_LIFETIME" default:"30m"`
+		ConnMaxIdleTime time.Duration `yaml:"conn_max_idle_time" toml:"conn_max_idle_time" env:"DB_CONN_MAX_IDLE_TIME" default:"5m"`
+
+		ConnectRetries int           `yaml:"connect_retries" toml:"connect_retries" env:"DB_CONNECT_RETRIES" default:"5"`
+		ConnectBackoff time.Duration `yaml:"connect_backoff" toml:"connect_backoff" env:"DB_CONNECT_BACKOFF" default:"1s"` // doubled after every failed attempt
+
+		ReplicaHosts string `yaml:"replica_hosts" toml:"replica_hosts" env:"DB_REPLICA_HOSTS"` // comma separated host or host:port, same credentials as the primary
+	}
+
+	DatabaseAddress struct {
+		Host string
+		Port int
+	}
+
+	JWTConfig struct {
+		Secret    string `yaml:"secret" toml:"secret" env:"JWT_SECRET"`
+		KeysDir   string `yaml:"keys_dir" toml:"keys_dir" env:"JWT_KEYS_DIR"`
+		ActiveKID string `yaml:"active_kid" toml:"active_kid" env:"JWT_ACTIVE_KID"`
+		Issuer    string `yaml:"issuer" toml:"issuer" env:"JWT_ISSUER" default:"Template"`
+
+		// RFC 3339 time until which JWT_SECRET still verifies HS256 tokens after
+		// switching to JWT_KEYS_DIR. Without it the secret is ignored then.
+		LegacyHS256Until string `yaml:"legacy_hs256_until" toml:"legacy_hs256_until" env:"JWT_LEGACY_HS256_UNTIL"`
+	}
+
+	AESConfig struct {
+		Key string `yaml:"key" toml:"key" env:"AES_KEY"` // hex encoded, 16/24/32 bytes
+	}
+
+	// MailConfig picks where email goes. Only "smtp" delivers it, the other
+	// transports keep mail local for development and tests.
+	MailConfig struct {
+		Transport string `yaml:"transport" toml:"transport" env:"MAIL_TRANSPORT" default:"smtp"`  // smtp/file/stdout/memory
+		FileDir   string `yaml:"file_dir" toml:"file_dir" env:"MAIL_FILE_DIR" default:"tmp/mail"` // maildir written by the file transport
+	}
+
+	SMTPConfig struct {
+		Host         string `yaml:"host" toml:"host" env:"SMTP_HOST"`
+		Port         int    `yaml:"port" toml:"port" env:"SMTP_PORT" default:"587"`
+		SenderName   string `yaml:"sender_name" toml:"sender_name" env:"SMTP_SENDER_NAME"`
+		SenderEmail  string `yaml:"sender_email" toml:"sender_email" env:"SMTP_SENDER_EMAIL"`
+		AuthEmail    string `yaml:"auth_email" toml:"auth_email" env:"SMTP_AUTH_EMAIL"`
+		AuthPassword string `yaml:"auth_password" toml:"auth_password" env:"SMTP_AUTH_PASSWORD"`
+	}
+
+	// OutboxConfig drives the background worker that delivers queued email.
+	OutboxConfig struct {
+		PollInterval time.Duration `yaml:"poll_interval" toml:"poll_interval" env:"OUTBOX_POLL_INTERVAL" default:"5s"`
+		BatchSize    int           `yaml:"batch_size" toml:"batch_size" env:"OUTBOX_BATCH_SIZE" default:"20"`
+		MaxAttempts  int           `yaml:"max_attempts" toml:"max_attempts" env:"OUTBOX_MAX_ATTEMPTS" default:"8"`      // dead-lettered after this many failed sends
+		RetryBackoff time.Duration `yaml:"retry_backoff" toml:"retry_backoff" env:"OUTBOX_RETRY_BACKOFF" default:"30s"` // doubled after every failed attempt
+		MaxBackoff   time.Duration `yaml:"max_backoff" toml:"max_backoff" env:"OUTBOX_MAX_BACKOFF" default:"1h"`
+
+		// Dead email still holds its one-time links, it is deleted this long
+		// after giving up. 0 keeps it forever.
+		DeadRetention time.Duration `yaml:"dead_retention" toml:"dead_retention" env:"OUTBOX_DEAD_RETENTION" default:"168h"`
+	}
+
+	AWSConfig struct {
+		AccessKey string `yaml:"access_key" toml:"access_key" env:"AWS_ACCESS_KEY"`
+		SecretKey string `yaml:"secret_key" toml:"secret_key" env:"AWS_SECRET_KEY"`
+		Region    string `yaml:"region" toml:"region" env:"AWS_REGION"`
+		Bucket    string `yaml:"bucket" toml:"bucket" env:"S3_BUCKET"`
+	}
+
+	TripayConfig struct {
+		MerchantCode string `yaml:"merchant_code" toml:"merchant_code" env:"TRIPAY_MERCHANT_CODE"`
+		APIKey       string `yaml:"api_key" toml:"api_key" env:"TRIPAY_API_KEY"`
+		PrivateKey   string `yaml:"private_key" toml:"private_key" env:"TRIPAY_PRIVATE_KEY"`
+		Mode         string `yaml:"mode" toml:"mode" env:"TRIPAY_MODE"` // "development" uses the sandbox, defaults to App.Env
+	}
+
+	SecurityConfig struct {
+		LoginAttemptStore     string `yaml:"login_attempt_store" toml:"login_attempt_store" env:"LOGIN_ATTEMPT_STORE" default:"memory"`
+		TwoFactorEnforceAdmin bool   `yaml:"two_factor_enforce_admin" toml:"two_factor_enforce_admin" env:"TWO_FACTOR_ENFORCE_ADMIN" default:"false"`
+		AuditLogRetentionDays int    `yaml:"audit_log_retention_days" toml:"audit_log_retention_days" env:"AUDIT_LOG_RETENTION_DAYS" default:"90"`
+	}
+
+	MetricsConfig struct {
+		Enabled bool   `yaml:"enabled" toml:"enabled" env:"METRICS_ENABLED" default:"true"`
+		Token   string `yaml:"token" toml:"token" env:"METRICS_TOKEN"` // bearer token required to scrape /metrics, empty leaves it open outside production
+	}
+
+	TracingConfig struct {
+		Exporter    string  `yaml:"exporter" toml:"exporter" env:"TRACING_EXPORTER" default:"none"` // none/stdout/otlp
+		Endpoint    string  `yaml:"endpoint" toml:"endpoint" env:"OTEL_EXPORTER_OTLP_ENDPOINT"`     // host:port of the OTLP/HTTP collector, defaults to localhost:4318
+		Insecure    bool    `yaml:"insecure" toml:"insecure" env:"OTEL_EXPORTER_OTLP_INSECURE" default:"false"`
+		ServiceName string  `yaml:"service_name" toml:"service_name" env:"OTEL_SERVICE_NAME" default:"go-gin-gorm-boilerplate"`
+		SampleRatio float64 `yaml:"sample_ratio" toml:"sample_ratio" env:"TRACING_SAMPLE_RATIO" default:"1"`
+	}
+
+	LogConfig struct {
+		Level  string `yaml:"level" toml:"level" env:"LOG_LEVEL" default:"info"` // debug/info/warn/error
+		Format string `yaml:"format" toml:"format" env:"LOG_FORMAT"`             // json/text, defaults to json when IS_PRODUCTION is true
+	}
+)
+
+// Load builds the configuration and validates it. The returned error lists
+// every problem found, not only the first one.
+func Load() (*Config, error) {
+	cfg := &Config{}
+	var problems []string
+
+	walkFields(reflect.ValueOf(cfg).Elem(), func(field reflect.Value, tag reflect.StructTag) {
+		if def, ok := tag.Lookup("default"); ok {
+			if err := setField(field, def); err != nil {
+				problems = append(problems, fmt.Sprintf("default for %s: %v", tag.Get("env"), err))
+			}
+		}
+	})
+
+	if path := os.Getenv("CONFIG_FILE"); path != "" {
+		if err := loadFile(path, cfg); err != nil {
+			problems = append(problems, err.Error())
+		}
+	}
+
+	walkFields(reflect.ValueOf(cfg).Elem(), func(field reflect.Value, tag reflect.StructTag) {
+		key := tag.Get("env")
+		if key == "" {
+			return
+		}
+
+		value, fromEnv := os.LookupEnv(key)
+		if secretPath, ok := os.LookupEnv(key + "_FILE"); ok {
+			if fromEnv {
+				problems = append(problems, fmt.Sprintf("%s and %s_FILE are both set", key, key))
+				return
+			}
+			content, err := os.ReadFile(secretPath)
+			if err != nil {
+				problems = append(problems, fmt.Sprintf("%s_FILE: %v", key, err))
+				return
+			}
+			value, fromEnv = strings.TrimRight(string(content), "\r\n"), true
+		}
+
+		if !fromEnv {
+			return
+		}
+		if err := setField(field, value); err != nil {
+			problems = append(problems, fmt.Sprintf("%s: %v", key, err))
+		}
+	})
+
+	if cfg.Tripay.Mode == "" {
+		cfg.Tripay.Mode = cfg.App.Env
+	}
+	if cfg.Log.Format == "" {
+		cfg.Log.Format = "text"
+		if cfg.App.IsProduction {
+			cfg.Log.Format = "json"
+		}
+	}
+
+	problems = append(problems, cfg.Validate()...)
+	if len(problems) > 0 {
+		return nil, &Error{Problems: problems}
+	}
+
+	return cfg, nil
+}
+
+// Address returns the listen address, bound to loopback when running on localhost.
+func (c AppConfig) Address() string {
+	if c.Env == "localhost" {
+		return "127.0.0.1:" + strconv.Itoa(c.Port)
+	}
+	return ":" + strconv.Itoa(c.Port)
+}
+
+// TrustedProxyList splits TRUSTED_PROXIES into the list gin expects.
+func (c AppConfig) TrustedProxyList() []string {
+	var proxies []string
+	for _, entry := range strings.Split(c.TrustedProxies, ",") {
+		if entry = strings.TrimSpace(entry); entry != "" {
+			proxies = append(proxies, entry)
+		}
+	}
+	return proxies
+}
+
+// LegacyHS256Deadline parses JWT_LEGACY_HS256_UNTIL, the zero time when unset.
+func (c JWTConfig) LegacyHS256Deadline() (time.Time, error) {
+	if c.LegacyHS256Until == "" {
+		return time.Time{}, nil
+	}
+	return time.Parse(time.RFC3339, c.LegacyHS256Until)
+}
+
+// ReplicaAddresses parses DB_REPLICA_HOSTS. Entries without a port use the
+// primary's port.
+func (c DatabaseConfig) ReplicaAddresses() ([]DatabaseAddress, error) {
+	var addresses []DatabaseAddress
+	for _, entry := range strings.Split(c.ReplicaHosts, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+
+		address := DatabaseAddress{Host: entry, Port: c.Port}
+		if host, port, err := net.SplitHostPort(entry); err == nil {
+			n, err := strconv.Atoi(port)
+			if err != nil {
+				return nil, fmt.Errorf("%q has an invalid port", entry)
+			}
+			address = DatabaseAddress{Host: host, Port: n}
+		}
+		addresses = append(addresses, address)
+	}
+
+	return addresses, nil
+}
+
+func loadFile(path string, cfg *Config) error {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("CONFIG_FILE: %w", err)
+	}
+
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		err = yaml.Unmarshal(content, cfg)
+	case ".toml":
+		// go-toml can't decode "30s" into a time.Duration, so values go
+		// through the same parsing as environment variables
+		var doc map[string]any
+		if err = toml.Unmarshal(content, &doc); err == nil {
+			err = setTable(reflect.ValueOf(cfg).Elem(), doc, "")
+		}
+	default:
+		return fmt.Errorf("CONFIG_FILE: unsupported extension %q, use .yaml, .yml or .toml", filepath.Ext(path))
+	}
+	if err != nil {
+		return fmt.Errorf("CONFIG_FILE %s: %w", path, err)
+	}
+
+	return nil
+}
+
+// setTable copies a decoded TOML table into the struct v, matching fields by
+// their toml tag. Keys without a matching field are ignored.
+func setTable(v reflect.Value, table map[string]any, prefix string) error {
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		name := t.Field(i).Tag.Get("toml")
+		value, ok := table[name]
+		if name == "" || !ok {
+			continue
+		}
+
+		field := v.Field(i)
+		if field.Kind() == reflect.Struct {
+			nested, ok := value.(map[string]any)
+			if !ok {
+				return fmt.Errorf("%s%s must be a table", prefix, name)
+			}
+			if err := setTable(field, nested, prefix+name+"."); err != nil {
+				return err
+			}
+			continue
+		}
+
+		if err := setField(field, fmt.Sprint(value)); err != nil {
+			return fmt.Errorf("%s%s: %w", prefix, name, err)
+		}
+	}
+
+	return nil
+}
+
+// walkFields calls fn for every leaf field of a (nested) config struct.
+func walkFields(v reflect.Value, fn func(field reflect.Value, tag reflect.StructTag)) {
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		field := v.Field(i)
+		if field.Kind() == reflect.Struct {
+			walkFields(field, fn)
+			continue
+		}
+		fn(field, t.Field(i).Tag)
+	}
+}
+
+func setField(field reflect.Value, raw string) error {
+	raw = strings.TrimSpace(raw)
+
+	if field.Type() == reflect.TypeOf(time.Duration(0)) {
+		d, err := time.ParseDuration(raw)
+		if err != nil {
+			return fmt.Errorf("%q is not a duration", raw)
+		}
+		field.SetInt(int64(d))
+		return nil
+	}
+
+	switch field.Kind() {
+	case reflect.String:
+		field.SetString(raw)
+	case reflect.Int:
+		n, err := strconv.Atoi(raw)
+		if err != nil {
+			return fmt.Errorf("%q is not a number", raw)
+		}
+		field.SetInt(int64(n))
+	case reflect.Bool:
+		b, err := strconv.ParseBool(raw)
+		if err != nil {
+			return fmt.Errorf("%q is not a boolean", raw)
+		}
+		field.SetBool(b)
+	case reflect.Float64:
+		f, err := strconv.ParseFloat(raw, 64)
+		if err != nil {
+			return fmt.Errorf("%q is not a number", raw)
+		}
+		field.SetFloat(f)
+	default:
+		return fmt.Errorf("unsupported config type %s", field.Type())
+	}
+
+	return nil
+}
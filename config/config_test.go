@@ -0,0 +1,117 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func writeConfigFile(t *testing.T, name string, content string) string {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatalf("write %s: %v", name, err)
+	}
+	return path
+}
+
+func TestLoadFile(t *testing.T) {
+	tests := []struct {
+		name    string
+		file    string
+		content string
+	}{
+		{
+			name: "yaml",
+			file: "config.yaml",
+			content: `
+app:
+  port: 9000
+  is_production: false
+  shutdown_drain_delay: 5s
+database:
+  conn_max_lifetime: 1h30m
+outbox:
+  poll_interval: 30s
+  batch_size: 50
+tracing:
+  sample_ratio: 0.25
+`,
+		},
+		{
+			name: "toml",
+			file: "config.toml",
+			content: `
+[app]
+port = 9000
+is_production = false
+shutdown_drain_delay = "5s"
+
+[database]
+conn_max_lifetime = "1h30m"
+
+[outbox]
+poll_interval = "30s"
+batch_size = 50
+
+[tracing]
+sample_ratio = 0.25
+`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &Config{}
+			cfg.App.Env = "localhost"
+
+			if err := loadFile(writeConfigFile(t, tt.file, tt.content), cfg); err != nil {
+				t.Fatalf("loadFile() error = %v", err)
+			}
+
+			if cfg.App.Port != 9000 || cfg.App.IsProduction || cfg.App.ShutdownDrainDelay != 5*time.Second {
+				t.Errorf("app = %+v", cfg.App)
+			}
+			if cfg.Database.ConnMaxLifetime != 90*time.Minute {
+				t.Errorf("database.conn_max_lifetime = %s, want 1h30m", cfg.Database.ConnMaxLifetime)
+			}
+			if cfg.Outbox.PollInterval != 30*time.Second || cfg.Outbox.BatchSize != 50 {
+				t.Errorf("outbox = %+v", cfg.Outbox)
+			}
+			if cfg.Tracing.SampleRatio != 0.25 {
+				t.Errorf("tracing.sample_ratio = %v, want 0.25", cfg.Tracing.SampleRatio)
+			}
+			// keys missing from the file keep what was there before
+			if cfg.App.Env != "localhost" {
+				t.Errorf("app.env = %q, want it untouched", cfg.App.Env)
+			}
+		})
+	}
+}
+
+func TestLoadFileErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		file    string
+		content string
+		wantErr string
+	}{
+		{name: "toml duration without unit", file: "config.toml", content: "[outbox]\npoll_interval = 30\n", wantErr: "outbox.poll_interval"},
+		{name: "toml bad duration", file: "config.toml", content: "[outbox]\npoll_interval = \"soon\"\n", wantErr: `"soon" is not a duration`},
+		{name: "toml value instead of table", file: "config.toml", content: "outbox = 5\n", wantErr: "outbox must be a table"},
+		{name: "toml syntax", file: "config.toml", content: "[outbox\n", wantErr: "config.toml"},
+		{name: "unsupported extension", file: "config.json", content: "{}", wantErr: "unsupported extension"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := loadFile(writeConfigFile(t, tt.file, tt.content), &Config{})
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("loadFile() error = %v, want one containing %q", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,237 @@
+package config
+
+import (
+	"encoding/hex"
+	"fmt"
+	"net"
+	"sort"
+	"strings"
+)
+
+// Error reports every configuration problem at once so a broken deployment
+// can be fixed in a single pass.
+type Error struct {
+	Problems []string
+}
+
+func (e *Error) Error() string {
+	return "invalid configuration:\n  - " + strings.Join(e.Problems, "\n  - ")
+}
+
+var (
+	APP_ENVS            = []string{"localhost", "development", "production", "testing"}
+	LOGIN_ATTEMPT_STORE = []string{"memory", "postgres"}
+	MAIL_TRANSPORTS     = []string{"smtp", "file", "stdout", "memory"}
+	DB_SSLMODES         = []string{"disable", "allow", "prefer", "require", "verify-ca", "verify-full"}
+	TRACING_EXPORTERS   = []string{"none", "stdout", "otlp"}
+	LOG_LEVELS          = []string{"debug", "info", "warn", "error"}
+	LOG_FORMATS         = []string{"json", "text"}
+)
+
+// Validate returns a description of every invalid setting.
+func (c *Config) Validate() []string {
+	var problems []string
+	add := func(format string, args ...any) {
+		problems = append(problems, fmt.Sprintf(format, args...))
+	}
+
+	if !oneOf(c.App.Env, APP_ENVS) {
+		add("APP_ENV must be one of %s, got %q", strings.Join(APP_ENVS, "/"), c.App.Env)
+	}
+	if !validPort(c.App.Port) {
+		add("APP_PORT must be between 1 and 65535, got %d", c.App.Port)
+	}
+	if c.App.IsProduction && c.App.URL == "" {
+		add("APP_URL is required in production, it is used for links in emails")
+	}
+	if c.App.ShutdownDrainDelay < 0 {
+		add("SHUTDOWN_DRAIN_DELAY must not be negative")
+	}
+	for _, proxy := range c.App.TrustedProxyList() {
+		if net.ParseIP(proxy) == nil {
+			if _, _, err := net.ParseCIDR(proxy); err != nil {
+				add("TRUSTED_PROXIES: %q is not an IP address or CIDR", proxy)
+			}
+		}
+	}
+
+	if c.Database.Host == "" {
+		add("DB_HOST is required")
+	}
+	if !validPort(c.Database.Port) {
+		add("DB_PORT must be between 1 and 65535, got %d", c.Database.Port)
+	}
+	if c.Database.User == "" {
+		add("DB_USER is required")
+	}
+	if c.Database.Name == "" {
+		add("DB_NAME is required")
+	}
+	if !oneOf(c.Database.SSLMode, DB_SSLMODES) {
+		add("DB_SSLMODE must be one of %s, got %q", strings.Join(DB_SSLMODES, "/"), c.Database.SSLMode)
+	}
+	for _, option := range strings.Fields(c.Database.Options) {
+		if !strings.Contains(option, "=") {
+			add("DB_OPTIONS must be space separated key=value pairs, got %q", option)
+		}
+	}
+	if c.Database.MaxOpenConns < 0 {
+		add("DB_MAX_OPEN_CONNS must not be negative")
+	}
+	if c.Database.MaxIdleConns < 0 {
+		add("DB_MAX_IDLE_CONNS must not be negative")
+	} else if c.Database.MaxOpenConns > 0 && c.Database.MaxIdleConns > c.Database.MaxOpenConns {
+		add("DB_MAX_IDLE_CONNS (%d) must not exceed DB_MAX_OPEN_CONNS (%d)", c.Database.MaxIdleConns, c.Database.MaxOpenConns)
+	}
+	if c.Database.ConnMaxLifetime < 0 {
+		add("DB_CONN_MAX_LIFETIME must not be negative")
+	}
+	if c.Database.ConnMaxIdleTime < 0 {
+		add("DB_CONN_MAX_IDLE_TIME must not be negative")
+	}
+	if c.Database.ConnectRetries < 0 {
+		add("DB_CONNECT_RETRIES must not be negative")
+	}
+	if c.Database.ConnectRetries > 0 && c.Database.ConnectBackoff <= 0 {
+		add("DB_CONNECT_BACKOFF must be positive when DB_CONNECT_RETRIES is set")
+	}
+	if replicas, err := c.Database.ReplicaAddresses(); err != nil {
+		add("DB_REPLICA_HOSTS: %v", err)
+	} else {
+		for _, replica := range replicas {
+			if !validPort(replica.Port) {
+				add("DB_REPLICA_HOSTS port must be between 1 and 65535, got %d", replica.Port)
+			}
+		}
+	}
+
+	if c.JWT.KeysDir != "" && c.JWT.ActiveKID == "" {
+		add("JWT_ACTIVE_KID is required when JWT_KEYS_DIR is set")
+	}
+	if c.App.IsProduction && c.JWT.KeysDir == "" && c.JWT.Secret == "" {
+		add("JWT_SECRET or JWT_KEYS_DIR is required in production")
+	}
+	if _, err := c.JWT.LegacyHS256Deadline(); err != nil {
+		add("JWT_LEGACY_HS256_UNTIL must be an RFC 3339 time, e.g. 2025-01-31T00:00:00Z")
+	} else if c.JWT.LegacyHS256Until != "" && (c.JWT.KeysDir == "" || c.JWT.Secret == "") {
+		add("JWT_LEGACY_HS256_UNTIL needs both JWT_KEYS_DIR and JWT_SECRET")
+	}
+
+	if c.AES.Key == "" {
+		add("AES_KEY is required")
+	} else if key, err := hex.DecodeString(c.AES.Key); err != nil {
+		add("AES_KEY must be hex encoded")
+	} else if n := len(key); n != 16 && n != 24 && n != 32 {
+		add("AES_KEY must decode to 16, 24 or 32 bytes, got %d", n)
+	}
+
+	if !oneOf(c.Mail.Transport, MAIL_TRANSPORTS) {
+		add("MAIL_TRANSPORT must be one of %s, got %q", strings.Join(MAIL_TRANSPORTS, "/"), c.Mail.Transport)
+	}
+	if c.Mail.Transport == "file" && c.Mail.FileDir == "" {
+		add("MAIL_FILE_DIR is required when MAIL_TRANSPORT is file")
+	}
+	if c.App.IsProduction && c.Mail.Transport != "smtp" {
+		add("MAIL_TRANSPORT must be smtp in production, got %q", c.Mail.Transport)
+	}
+
+	if !validPort(c.SMTP.Port) {
+		add("SMTP_PORT must be between 1 and 65535, got %d", c.SMTP.Port)
+	}
+	if c.App.IsProduction {
+		if c.SMTP.Host == "" {
+			add("SMTP_HOST is required in production")
+		}
+		if c.SMTP.AuthEmail == "" {
+			add("SMTP_AUTH_EMAIL is required in production")
+		}
+	}
+
+	if c.Outbox.PollInterval <= 0 {
+		add("OUTBOX_POLL_INTERVAL must be positive")
+	}
+	if c.Outbox.BatchSize < 1 {
+		add("OUTBOX_BATCH_SIZE must be at least 1")
+	}
+	if c.Outbox.MaxAttempts < 1 {
+		add("OUTBOX_MAX_ATTEMPTS must be at least 1")
+	}
+	if c.Outbox.RetryBackoff <= 0 || c.Outbox.MaxBackoff < c.Outbox.RetryBackoff {
+		add("OUTBOX_RETRY_BACKOFF must be positive and not above OUTBOX_MAX_BACKOFF")
+	}
+	if c.Outbox.DeadRetention < 0 {
+		add("OUTBOX_DEAD_RETENTION must not be negative")
+	}
+
+	if missing := partiallySet(map[string]string{
+		"AWS_ACCESS_KEY": c.AWS.AccessKey,
+		"AWS_SECRET_KEY": c.AWS.SecretKey,
+		"AWS_REGION":     c.AWS.Region,
+		"S3_BUCKET":      c.AWS.Bucket,
+	}); len(missing) > 0 {
+		add("S3 is partially configured, missing %s", strings.Join(missing, ", "))
+	}
+
+	if missing := partiallySet(map[string]string{
+		"TRIPAY_MERCHANT_CODE": c.Tripay.MerchantCode,
+		"TRIPAY_API_KEY":       c.Tripay.APIKey,
+		"TRIPAY_PRIVATE_KEY":   c.Tripay.PrivateKey,
+	}); len(missing) > 0 {
+		add("Tripay is partially configured, missing %s", strings.Join(missing, ", "))
+	}
+
+	if !oneOf(c.Security.LoginAttemptStore, LOGIN_ATTEMPT_STORE) {
+		add("LOGIN_ATTEMPT_STORE must be one of %s, got %q", strings.Join(LOGIN_ATTEMPT_STORE, "/"), c.Security.LoginAttemptStore)
+	}
+	if c.Security.AuditLogRetentionDays < 0 {
+		add("AUDIT_LOG_RETENTION_DAYS must not be negative")
+	}
+
+	if c.App.IsProduction && c.Metrics.Enabled && c.Metrics.Token == "" {
+		add("METRICS_TOKEN is required in production while METRICS_ENABLED is true")
+	}
+
+	if !oneOf(c.Tracing.Exporter, TRACING_EXPORTERS) {
+		add("TRACING_EXPORTER must be one of %s, got %q", strings.Join(TRACING_EXPORTERS, "/"), c.Tracing.Exporter)
+	}
+	if c.Tracing.SampleRatio < 0 || c.Tracing.SampleRatio > 1 {
+		add("TRACING_SAMPLE_RATIO must be between 0 and 1, got %v", c.Tracing.SampleRatio)
+	}
+
+	if !oneOf(c.Log.Level, LOG_LEVELS) {
+		add("LOG_LEVEL must be one of %s, got %q", strings.Join(LOG_LEVELS, "/"), c.Log.Level)
+	}
+	if !oneOf(c.Log.Format, LOG_FORMATS) {
+		add("LOG_FORMAT must be one of %s, got %q", strings.Join(LOG_FORMATS, "/"), c.Log.Format)
+	}
+
+	return problems
+}
+
+func validPort(port int) bool {
+	return port > 0 && port <= 65535
+}
+
+func oneOf(value string, allowed []string) bool {
+	for _, a := range allowed {
+		if value == a {
+			return true
+		}
+	}
+	return false
+}
+
+// partiallySet returns the empty keys when some but not all values are set.
+func partiallySet(values map[string]string) []string {
+	var missing []string
+	for key, value := range values {
+		if value == "" {
+			missing = append(missing, key)
+		}
+	}
+	if len(missing) == len(values) {
+		return nil
+	}
+	sort.Strings(missing)
+	return missing
+}
@@ -1,8 +1,11 @@
 package constants
 
 const (
-	CTX_ID_PARAM      = "id"
-	CTX_KEY_ROLE_NAME = "role"
+	CTX_ID_PARAM       = "id"
+	CTX_KEY_ROLE_NAME  = "role"
+	CTX_KEY_SESSION    = "session_id"
+	CTX_KEY_PERMS      = "permissions"
+	CTX_KEY_REQUEST_ID = "request_id"
 
 	ENUM_ROLE_ADMIN = "admin"
 	ENUM_ROLE_USER  = "user"
@@ -13,6 +16,9 @@ const (
 	ENUM_PAGINATION_LIMIT = 10
 	ENUM_PAGINATION_PAGE  = 1
 
+	// DB_RESOLVER_REPLICA names the dbresolver used by repository.ReadOnly
+	DB_RESOLVER_REPLICA = "replica"
+
 	// PAYMENT METHOD
 	ENUM_TRIPAY_PAYMENT_METHOD_QRIS = "QRIS"
 )
@@ -0,0 +1,198 @@
+package controller
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/Shabrinashsf/go-gin-gorm-boilerplate/constants"
+	"github.com/Shabrinashsf/go-gin-gorm-boilerplate/dto"
+	"github.com/Shabrinashsf/go-gin-gorm-boilerplate/service"
+	"github.com/Shabrinashsf/go-gin-gorm-boilerplate/utils/pagination"
+	"github.com/Shabrinashsf/go-gin-gorm-boilerplate/utils/response"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type (
+	AdminUserController interface {
+		GetAllUsers(ctx *gin.Context)
+		GetUserByID(ctx *gin.Context)
+		ChangeRole(ctx *gin.Context)
+		VerifyUser(ctx *gin.Context)
+		SuspendUser(ctx *gin.Context)
+		UnsuspendUser(ctx *gin.Context)
+		DeleteUser(ctx *gin.Context)
+		RestoreUser(ctx *gin.Context)
+	}
+
+	adminUserController struct {
+		adminUserService service.AdminUserService
+	}
+)
+
+func NewAdminUserController(aus service.AdminUserService) AdminUserController {
+	return &adminUserController{
+		adminUserService: aus,
+	}
+}
+
+// paramUserID parses the :id path parameter and aborts the request when it is not a UUID.
+func paramUserID(ctx *gin.Context) (uuid.UUID, bool) {
+	id, err := uuid.Parse(ctx.Param(constants.CTX_ID_PARAM))
+	if err != nil {
+		response.Fail(ctx, dto.MESSAGE_FAILED_PROSES_REQUEST, dto.ErrInvalidUserID)
+		return uuid.Nil, false
+	}
+	return id, true
+}
+
+func (c *adminUserController) GetAllUsers(ctx *gin.Context) {
+	reqCtx, cancel := context.WithTimeout(ctx.Request.Context(), 20*time.Second)
+	defer cancel()
+
+	var filter dto.AdminUserFilter
+	if err := ctx.ShouldBindQuery(&filter); err != nil {
+		response.Fail(ctx, dto.MESSAGE_FAILED_GET_DATA_FROM_BODY, dto.NewValidationError(err))
+		return
+	}
+
+	result, err := c.adminUserService.GetAllUsers(reqCtx, filter, pagination.New(ctx))
+	if err != nil {
+		response.Fail(ctx, dto.MESSAGE_FAILED_GET_LIST_USER, err)
+		return
+	}
+
+	res := response.BuildResponseSuccess(dto.MESSAGE_SUCCESS_GET_LIST_USER, result.Data)
+	res.Meta = result.Meta
+	response.JSON(ctx, http.StatusOK, res)
+}
+
+func (c *adminUserController) GetUserByID(ctx *gin.Context) {
+	userId, ok := paramUserID(ctx)
+	if !ok {
+		return
+	}
+
+	result, err := c.adminUserService.GetUserByID(ctx.Request.Context(), userId)
+	if err != nil {
+		response.Fail(ctx, dto.MESSAGE_FAILED_GET_USER, err)
+		return
+	}
+
+	res := response.BuildResponseSuccess(dto.MESSAGE_SUCCESS_GET_USER, result)
+	response.JSON(ctx, http.StatusOK, res)
+}
+
+func (c *adminUserController) ChangeRole(ctx *gin.Context) {
+	userId, ok := paramUserID(ctx)
+	if !ok {
+		return
+	}
+
+	actorId := ctx.MustGet("user_id").(string)
+	var req dto.ChangeRoleRequest
+
+	if err := ctx.ShouldBind(&req); err != nil {
+		response.Fail(ctx, dto.MESSAGE_FAILED_GET_DATA_FROM_BODY, dto.NewValidationError(err))
+		return
+	}
+
+	result, err := c.adminUserService.ChangeRole(ctx.Request.Context(), uuid.MustParse(actorId), userId, req)
+	if err != nil {
+		response.Fail(ctx, dto.MESSAGE_FAILED_CHANGE_ROLE, err)
+		return
+	}
+
+	res := response.BuildResponseSuccess(dto.MESSAGE_SUCCESS_CHANGE_ROLE, result)
+	response.JSON(ctx, http.StatusOK, res)
+}
+
+func (c *adminUserController) VerifyUser(ctx *gin.Context) {
+	userId, ok := paramUserID(ctx)
+	if !ok {
+		return
+	}
+
+	actorId := ctx.MustGet("user_id").(string)
+
+	result, err := c.adminUserService.VerifyUser(ctx.Request.Context(), uuid.MustParse(actorId), userId)
+	if err != nil {
+		response.Fail(ctx, dto.MESSAGE_FAILED_VERIFY_EMAIL, err)
+		return
+	}
+
+	res := response.BuildResponseSuccess(dto.MESSAGE_SUCCESS_VERIFY_EMAIL, result)
+	response.JSON(ctx, http.StatusOK, res)
+}
+
+func (c *adminUserController) SuspendUser(ctx *gin.Context) {
+	userId, ok := paramUserID(ctx)
+	if !ok {
+		return
+	}
+
+	actorId := ctx.MustGet("user_id").(string)
+
+	result, err := c.adminUserService.SuspendUser(ctx.Request.Context(), uuid.MustParse(actorId), userId)
+	if err != nil {
+		response.Fail(ctx, dto.MESSAGE_FAILED_SUSPEND_USER, err)
+		return
+	}
+
+	res := response.BuildResponseSuccess(dto.MESSAGE_SUCCESS_SUSPEND_USER, result)
+	response.JSON(ctx, http.StatusOK, res)
+}
+
+func (c *adminUserController) UnsuspendUser(ctx *gin.Context) {
+	userId, ok := paramUserID(ctx)
+	if !ok {
+		return
+	}
+
+	actorId := ctx.MustGet("user_id").(string)
+
+	result, err := c.adminUserService.UnsuspendUser(ctx.Request.Context(), uuid.MustParse(actorId), userId)
+	if err != nil {
+		response.Fail(ctx, dto.MESSAGE_FAILED_UNSUSPEND_USER, err)
+		return
+	}
+
+	res := response.BuildResponseSuccess(dto.MESSAGE_SUCCESS_UNSUSPEND_USER, result)
+	response.JSON(ctx, http.StatusOK, res)
+}
+
+func (c *adminUserController) DeleteUser(ctx *gin.Context) {
+	userId, ok := paramUserID(ctx)
+	if !ok {
+		return
+	}
+
+	actorId := ctx.MustGet("user_id").(string)
+
+	if err := c.adminUserService.DeleteUser(ctx.Request.Context(), uuid.MustParse(actorId), userId); err != nil {
+		response.Fail(ctx, dto.MESSAGE_FAILED_DELETE_USER, err)
+		return
+	}
+
+	res := response.BuildResponseSuccess(dto.MESSAGE_SUCCESS_DELETE_USER, nil)
+	response.JSON(ctx, http.StatusOK, res)
+}
+
+func (c *adminUserController) RestoreUser(ctx *gin.Context) {
+	userId, ok := paramUserID(ctx)
+	if !ok {
+		return
+	}
+
+	actorId := ctx.MustGet("user_id").(string)
+
+	result, err := c.adminUserService.RestoreUser(ctx.Request.Context(), uuid.MustParse(actorId), userId)
+	if err != nil {
+		response.Fail(ctx, dto.MESSAGE_FAILED_RESTORE_USER, err)
+		return
+	}
+
+	res := response.BuildResponseSuccess(dto.MESSAGE_SUCCESS_RESTORE_USER, result)
+	response.JSON(ctx, http.StatusOK, res)
+}
@@ -0,0 +1,50 @@
+package controller
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/Shabrinashsf/go-gin-gorm-boilerplate/dto"
+	"github.com/Shabrinashsf/go-gin-gorm-boilerplate/service"
+	"github.com/Shabrinashsf/go-gin-gorm-boilerplate/utils/pagination"
+	"github.com/Shabrinashsf/go-gin-gorm-boilerplate/utils/response"
+	"github.com/gin-gonic/gin"
+)
+
+type (
+	AuditLogController interface {
+		GetAuditLogs(ctx *gin.Context)
+	}
+
+	auditLogController struct {
+		auditService service.AuditService
+	}
+)
+
+func NewAuditLogController(as service.AuditService) AuditLogController {
+	return &auditLogController{
+		auditService: as,
+	}
+}
+
+func (c *auditLogController) GetAuditLogs(ctx *gin.Context) {
+	reqCtx, cancel := context.WithTimeout(ctx.Request.Context(), 20*time.Second)
+	defer cancel()
+
+	var filter dto.AuditLogFilter
+	if err := ctx.ShouldBindQuery(&filter); err != nil {
+		response.Fail(ctx, dto.MESSAGE_FAILED_GET_DATA_FROM_BODY, dto.NewValidationError(err))
+		return
+	}
+
+	result, err := c.auditService.GetAuditLogs(reqCtx, filter, pagination.New(ctx))
+	if err != nil {
+		response.Fail(ctx, dto.MESSAGE_FAILED_GET_LIST_AUDIT_LOG, err)
+		return
+	}
+
+	res := response.BuildResponseSuccess(dto.MESSAGE_SUCCESS_GET_LIST_AUDIT_LOG, result.Data)
+	res.Meta = result.Meta
+	response.JSON(ctx, http.StatusOK, res)
+}
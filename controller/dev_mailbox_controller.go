@@ -0,0 +1,113 @@
+package controller
+
+import (
+	"net/http"
+
+	"github.com/Shabrinashsf/go-gin-gorm-boilerplate/constants"
+	"github.com/Shabrinashsf/go-gin-gorm-boilerplate/dto"
+	"github.com/Shabrinashsf/go-gin-gorm-boilerplate/utils/mailer"
+	"github.com/Shabrinashsf/go-gin-gorm-boilerplate/utils/response"
+	"github.com/gin-gonic/gin"
+)
+
+type (
+	// DevMailboxController shows the mail captured by the memory transport.
+	// It is only routed outside production.
+	DevMailboxController interface {
+		GetMessages(ctx *gin.Context)
+		GetMessage(ctx *gin.Context)
+		PreviewMessage(ctx *gin.Context)
+		ClearMessages(ctx *gin.Context)
+	}
+
+	devMailboxController struct {
+		mailbox mailer.Mailbox
+	}
+)
+
+// MAILBOX_PREVIEW_CSP keeps a previewed email from running scripts or
+// loading anything but images and inline styles.
+const MAILBOX_PREVIEW_CSP = "default-src 'none'; img-src * data:; style-src 'unsafe-inline'"
+
+func NewDevMailboxController(mailbox mailer.Mailbox) DevMailboxController {
+	return &devMailboxController{
+		mailbox: mailbox,
+	}
+}
+
+func (c *devMailboxController) GetMessages(ctx *gin.Context) {
+	messages := c.mailbox.Messages()
+
+	data := make([]dto.MailboxMessageResponse, 0, len(messages))
+	for _, msg := range messages {
+		data = append(data, toMailboxMessageResponse(msg, false))
+	}
+
+	res := response.BuildResponseSuccess(dto.MESSAGE_SUCCESS_GET_MAILBOX, data)
+	response.JSON(ctx, http.StatusOK, res)
+}
+
+func (c *devMailboxController) GetMessage(ctx *gin.Context) {
+	msg, ok := c.mailbox.Message(ctx.Param(constants.CTX_ID_PARAM))
+	if !ok {
+		response.Fail(ctx, dto.MESSAGE_FAILED_GET_MAILBOX, dto.ErrMailboxMessageNotFound)
+		return
+	}
+
+	res := response.BuildResponseSuccess(dto.MESSAGE_SUCCESS_GET_MAILBOX, toMailboxMessageResponse(msg, true))
+	response.JSON(ctx, http.StatusOK, res)
+}
+
+// PreviewMessage serves the HTML body as the recipient would see it.
+func (c *devMailboxController) PreviewMessage(ctx *gin.Context) {
+	msg, ok := c.mailbox.Message(ctx.Param(constants.CTX_ID_PARAM))
+	if !ok {
+		response.Fail(ctx, dto.MESSAGE_FAILED_GET_MAILBOX, dto.ErrMailboxMessageNotFound)
+		return
+	}
+
+	ctx.Header("Content-Security-Policy", MAILBOX_PREVIEW_CSP)
+	ctx.Data(http.StatusOK, "text/html; charset=utf-8", []byte(msg.HTML))
+}
+
+func (c *devMailboxController) ClearMessages(ctx *gin.Context) {
+	c.mailbox.Clear()
+
+	res := response.BuildResponseSuccess(dto.MESSAGE_SUCCESS_CLEAR_MAILBOX, nil)
+	response.JSON(ctx, http.StatusOK, res)
+}
+
+func toMailboxMessageResponse(msg mailer.CapturedMessage, withBody bool) dto.MailboxMessageResponse {
+	res := dto.MailboxMessageResponse{
+		ID:         msg.ID,
+		From:       msg.From,
+		To:         msg.To,
+		Cc:         msg.Cc,
+		Bcc:        msg.Bcc,
+		ReplyTo:    msg.ReplyTo,
+		Subject:    msg.Subject,
+		Date:       msg.Date,
+		PreviewURL: "/dev/mailbox/" + msg.ID + "/preview",
+	}
+	for _, attachment := range msg.Attachments {
+		res.Attachments = append(res.Attachments, toMailboxAttachmentResponse(attachment, false))
+	}
+	for _, inline := range msg.Inline {
+		res.Attachments = append(res.Attachments, toMailboxAttachmentResponse(inline, true))
+	}
+	if withBody {
+		res.Headers = msg.Headers
+		res.HTML = msg.HTML
+		res.Text = msg.Text
+	}
+	return res
+}
+
+func toMailboxAttachmentResponse(attachment mailer.Attachment, inline bool) dto.MailboxAttachmentResponse {
+	return dto.MailboxAttachmentResponse{
+		Name:        attachment.Name,
+		ContentType: attachment.ContentType,
+		Size:        len(attachment.Content),
+		Inline:      inline,
+	}
+}
@@ -0,0 +1,96 @@
+package controller
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/Shabrinashsf/go-gin-gorm-boilerplate/constants"
+	"github.com/Shabrinashsf/go-gin-gorm-boilerplate/dto"
+	"github.com/Shabrinashsf/go-gin-gorm-boilerplate/service"
+	"github.com/Shabrinashsf/go-gin-gorm-boilerplate/utils/pagination"
+	"github.com/Shabrinashsf/go-gin-gorm-boilerplate/utils/response"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type (
+	EmailOutboxController interface {
+		GetEmails(ctx *gin.Context)
+		GetEmailByID(ctx *gin.Context)
+		ResendEmail(ctx *gin.Context)
+	}
+
+	emailOutboxController struct {
+		emailOutboxService service.EmailOutboxService
+	}
+)
+
+func NewEmailOutboxController(eos service.EmailOutboxService) EmailOutboxController {
+	return &emailOutboxController{
+		emailOutboxService: eos,
+	}
+}
+
+// paramEmailID parses the :id path parameter and aborts the request when it is not a UUID.
+func paramEmailID(ctx *gin.Context) (uuid.UUID, bool) {
+	id, err := uuid.Parse(ctx.Param(constants.CTX_ID_PARAM))
+	if err != nil {
+		response.Fail(ctx, dto.MESSAGE_FAILED_PROSES_REQUEST, dto.ErrInvalidEmailID)
+		return uuid.Nil, false
+	}
+	return id, true
+}
+
+func (c *emailOutboxController) GetEmails(ctx *gin.Context) {
+	reqCtx, cancel := context.WithTimeout(ctx.Request.Context(), 20*time.Second)
+	defer cancel()
+
+	var filter dto.EmailOutboxFilter
+	if err := ctx.ShouldBindQuery(&filter); err != nil {
+		response.Fail(ctx, dto.MESSAGE_FAILED_GET_DATA_FROM_BODY, dto.NewValidationError(err))
+		return
+	}
+
+	result, err := c.emailOutboxService.GetEmails(reqCtx, filter, pagination.New(ctx))
+	if err != nil {
+		response.Fail(ctx, dto.MESSAGE_FAILED_GET_LIST_EMAIL, err)
+		return
+	}
+
+	res := response.BuildResponseSuccess(dto.MESSAGE_SUCCESS_GET_LIST_EMAIL, result.Data)
+	res.Meta = result.Meta
+	response.JSON(ctx, http.StatusOK, res)
+}
+
+func (c *emailOutboxController) GetEmailByID(ctx *gin.Context) {
+	emailId, ok := paramEmailID(ctx)
+	if !ok {
+		return
+	}
+
+	result, err := c.emailOutboxService.GetEmailByID(ctx.Request.Context(), emailId)
+	if err != nil {
+		response.Fail(ctx, dto.MESSAGE_FAILED_GET_EMAIL, err)
+		return
+	}
+
+	res := response.BuildResponseSuccess(dto.MESSAGE_SUCCESS_GET_EMAIL, result)
+	response.JSON(ctx, http.StatusOK, res)
+}
+
+func (c *emailOutboxController) ResendEmail(ctx *gin.Context) {
+	emailId, ok := paramEmailID(ctx)
+	if !ok {
+		return
+	}
+
+	result, err := c.emailOutboxService.ResendEmail(ctx.Request.Context(), emailId)
+	if err != nil {
+		response.Fail(ctx, dto.MESSAGE_FAILED_RESEND_EMAIL, err)
+		return
+	}
+
+	res := response.BuildResponseSuccess(dto.MESSAGE_SUCCESS_RESEND_EMAIL, result)
+	response.JSON(ctx, http.StatusOK, res)
+}
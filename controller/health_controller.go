@@ -0,0 +1,44 @@
+package controller
+
+import (
+	"net/http"
+
+	"github.com/Shabrinashsf/go-gin-gorm-boilerplate/service"
+	"github.com/gin-gonic/gin"
+)
+
+type (
+	HealthController interface {
+		Liveness(ctx *gin.Context)
+		Readiness(ctx *gin.Context)
+	}
+
+	healthController struct {
+		healthService service.HealthService
+	}
+)
+
+func NewHealthController(hs service.HealthService) HealthController {
+	return &healthController{
+		healthService: hs,
+	}
+}
+
+// Probes are served without the usual response envelope, orchestrators and
+// load balancers only look at the status code.
+func (c *healthController) Liveness(ctx *gin.Context) {
+	ctx.Header("Cache-Control", "no-store")
+	ctx.JSON(http.StatusOK, c.healthService.Liveness())
+}
+
+func (c *healthController) Readiness(ctx *gin.Context) {
+	result, ready := c.healthService.Readiness(ctx.Request.Context())
+
+	status := http.StatusOK
+	if !ready {
+		status = http.StatusServiceUnavailable
+	}
+
+	ctx.Header("Cache-Control", "no-store")
+	ctx.JSON(status, result)
+}
@@ -0,0 +1,169 @@
+package controller
+
+import (
+	"net/http"
+
+	"github.com/Shabrinashsf/go-gin-gorm-boilerplate/constants"
+	"github.com/Shabrinashsf/go-gin-gorm-boilerplate/dto"
+	"github.com/Shabrinashsf/go-gin-gorm-boilerplate/service"
+	"github.com/Shabrinashsf/go-gin-gorm-boilerplate/utils/response"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type (
+	RoleController interface {
+		GetAllRoles(ctx *gin.Context)
+		CreateRole(ctx *gin.Context)
+		UpdateRole(ctx *gin.Context)
+		DeleteRole(ctx *gin.Context)
+		GetAllPermissions(ctx *gin.Context)
+		CreatePermission(ctx *gin.Context)
+		AssignUserRoles(ctx *gin.Context)
+	}
+
+	roleController struct {
+		roleService service.RoleService
+	}
+)
+
+func NewRoleController(rs service.RoleService) RoleController {
+	return &roleController{
+		roleService: rs,
+	}
+}
+
+// paramRoleID parses the :id path parameter and aborts the request when it is not a UUID.
+func paramRoleID(ctx *gin.Context) (uuid.UUID, bool) {
+	id, err := uuid.Parse(ctx.Param(constants.CTX_ID_PARAM))
+	if err != nil {
+		response.Fail(ctx, dto.MESSAGE_FAILED_PROSES_REQUEST, dto.ErrInvalidRoleID)
+		return uuid.Nil, false
+	}
+	return id, true
+}
+
+func (c *roleController) GetAllRoles(ctx *gin.Context) {
+	result, err := c.roleService.GetAllRoles(ctx.Request.Context())
+	if err != nil {
+		response.Fail(ctx, dto.MESSAGE_FAILED_GET_LIST_ROLE, err)
+		return
+	}
+
+	res := response.BuildResponseSuccess(dto.MESSAGE_SUCCESS_GET_LIST_ROLE, result)
+	response.JSON(ctx, http.StatusOK, res)
+}
+
+func (c *roleController) CreateRole(ctx *gin.Context) {
+	var req dto.CreateRoleRequest
+	if err := ctx.ShouldBind(&req); err != nil {
+		response.Fail(ctx, dto.MESSAGE_FAILED_GET_DATA_FROM_BODY, dto.NewValidationError(err))
+		return
+	}
+
+	actorId := ctx.MustGet("user_id").(string)
+
+	result, err := c.roleService.CreateRole(ctx.Request.Context(), uuid.MustParse(actorId), req)
+	if err != nil {
+		response.Fail(ctx, dto.MESSAGE_FAILED_CREATE_ROLE, err)
+		return
+	}
+
+	res := response.BuildResponseSuccess(dto.MESSAGE_SUCCESS_CREATE_ROLE, result)
+	response.JSON(ctx, http.StatusCreated, res)
+}
+
+func (c *roleController) UpdateRole(ctx *gin.Context) {
+	roleId, ok := paramRoleID(ctx)
+	if !ok {
+		return
+	}
+
+	var req dto.UpdateRoleRequest
+	if err := ctx.ShouldBind(&req); err != nil {
+		response.Fail(ctx, dto.MESSAGE_FAILED_GET_DATA_FROM_BODY, dto.NewValidationError(err))
+		return
+	}
+
+	actorId := ctx.MustGet("user_id").(string)
+
+	result, err := c.roleService.UpdateRole(ctx.Request.Context(), uuid.MustParse(actorId), roleId, req)
+	if err != nil {
+		response.Fail(ctx, dto.MESSAGE_FAILED_UPDATE_ROLE, err)
+		return
+	}
+
+	res := response.BuildResponseSuccess(dto.MESSAGE_SUCCESS_UPDATE_ROLE, result)
+	response.JSON(ctx, http.StatusOK, res)
+}
+
+func (c *roleController) DeleteRole(ctx *gin.Context) {
+	roleId, ok := paramRoleID(ctx)
+	if !ok {
+		return
+	}
+
+	actorId := ctx.MustGet("user_id").(string)
+
+	if err := c.roleService.DeleteRole(ctx.Request.Context(), uuid.MustParse(actorId), roleId); err != nil {
+		response.Fail(ctx, dto.MESSAGE_FAILED_DELETE_ROLE, err)
+		return
+	}
+
+	res := response.BuildResponseSuccess(dto.MESSAGE_SUCCESS_DELETE_ROLE, nil)
+	response.JSON(ctx, http.StatusOK, res)
+}
+
+func (c *roleController) GetAllPermissions(ctx *gin.Context) {
+	result, err := c.roleService.GetAllPermissions(ctx.Request.Context())
+	if err != nil {
+		response.Fail(ctx, dto.MESSAGE_FAILED_GET_LIST_PERMISSION, err)
+		return
+	}
+
+	res := response.BuildResponseSuccess(dto.MESSAGE_SUCCESS_GET_LIST_PERMISSION, result)
+	response.JSON(ctx, http.StatusOK, res)
+}
+
+func (c *roleController) CreatePermission(ctx *gin.Context) {
+	var req dto.CreatePermissionRequest
+	if err := ctx.ShouldBind(&req); err != nil {
+		response.Fail(ctx, dto.MESSAGE_FAILED_GET_DATA_FROM_BODY, dto.NewValidationError(err))
+		return
+	}
+
+	actorId := ctx.MustGet("user_id").(string)
+
+	result, err := c.roleService.CreatePermission(ctx.Request.Context(), uuid.MustParse(actorId), req)
+	if err != nil {
+		response.Fail(ctx, dto.MESSAGE_FAILED_CREATE_PERMISSION, err)
+		return
+	}
+
+	res := response.BuildResponseSuccess(dto.MESSAGE_SUCCESS_CREATE_PERMISSION, result)
+	response.JSON(ctx, http.StatusCreated, res)
+}
+
+func (c *roleController) AssignUserRoles(ctx *gin.Context) {
+	userId, ok := paramUserID(ctx)
+	if !ok {
+		return
+	}
+
+	var req dto.AssignRolesRequest
+	if err := ctx.ShouldBind(&req); err != nil {
+		response.Fail(ctx, dto.MESSAGE_FAILED_GET_DATA_FROM_BODY, dto.NewValidationError(err))
+		return
+	}
+
+	actorId := ctx.MustGet("user_id").(string)
+
+	result, err := c.roleService.AssignUserRoles(ctx.Request.Context(), uuid.MustParse(actorId), userId, req)
+	if err != nil {
+		response.Fail(ctx, dto.MESSAGE_FAILED_ASSIGN_ROLE, err)
+		return
+	}
+
+	res := response.BuildResponseSuccess(dto.MESSAGE_SUCCESS_ASSIGN_ROLE, result)
+	response.JSON(ctx, http.StatusOK, res)
+}
@@ -35,16 +35,14 @@ func (c *transactionController) TripayWebhook(ctx *gin.Context) {
 	// 1. Ambil raw body sekali
 	rawBody, err := ctx.GetRawData()
 	if err != nil {
-		res := response.BuildResponseFailed(dto.MESSAGE_FAILED_GET_DATA_FROM_BODY, err.Error(), nil)
-		ctx.AbortWithStatusJSON(http.StatusBadRequest, res)
+		response.Fail(ctx, dto.MESSAGE_FAILED_GET_DATA_FROM_BODY, dto.NewValidationError(err))
 		return
 	}
 
 	// 2. Parse JSON ke struct
 	var req dto.TripayWebhookRequest
 	if err := json.Unmarshal(rawBody, &req); err != nil {
-		res := response.BuildResponseFailed(dto.MESSAGE_FAILED_GET_DATA_FROM_BODY, err.Error(), nil)
-		ctx.AbortWithStatusJSON(http.StatusBadRequest, res)
+		response.Fail(ctx, dto.MESSAGE_FAILED_GET_DATA_FROM_BODY, dto.NewValidationError(err))
 		return
 	}
 
@@ -55,11 +53,10 @@ func (c *transactionController) TripayWebhook(ctx *gin.Context) {
 	// 4. Kirim semuanya ke service
 	_, err = c.transactionService.TripayWebhook(svcCtx, rawBody, req, cbSignature, cbEvent)
 	if err != nil {
-		res := response.BuildResponseFailed(dto.MESSAGE_FAILED_GET_CALLBACK_TRIPAY, err.Error(), nil)
-		ctx.AbortWithStatusJSON(http.StatusBadRequest, res)
+		response.Fail(ctx, dto.MESSAGE_FAILED_GET_CALLBACK_TRIPAY, err)
 		return
 	}
 
 	res := response.BuildResponseSuccess(dto.MESSAGE_SUCCESS_GET_CALLBACK_TRIPAY, nil)
-	ctx.JSON(http.StatusOK, res)
+	response.JSON(ctx, http.StatusOK, res)
 }
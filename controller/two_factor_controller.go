@@ -0,0 +1,150 @@
+package controller
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/Shabrinashsf/go-gin-gorm-boilerplate/dto"
+	"github.com/Shabrinashsf/go-gin-gorm-boilerplate/service"
+	"github.com/Shabrinashsf/go-gin-gorm-boilerplate/utils/response"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type (
+	TwoFactorController interface {
+		Setup(ctx *gin.Context)
+		Confirm(ctx *gin.Context)
+		Disable(ctx *gin.Context)
+		Verify(ctx *gin.Context)
+		EnrollSetup(ctx *gin.Context)
+		EnrollConfirm(ctx *gin.Context)
+	}
+
+	twoFactorController struct {
+		twoFactorService service.TwoFactorService
+	}
+)
+
+func NewTwoFactorController(tfs service.TwoFactorService) TwoFactorController {
+	return &twoFactorController{
+		twoFactorService: tfs,
+	}
+}
+
+func (c *twoFactorController) Setup(ctx *gin.Context) {
+	userId := ctx.MustGet("user_id").(string)
+
+	result, err := c.twoFactorService.Setup(ctx.Request.Context(), uuid.MustParse(userId))
+	if err != nil {
+		response.Fail(ctx, dto.MESSAGE_FAILED_SETUP_TWO_FACTOR, err)
+		return
+	}
+
+	res := response.BuildResponseSuccess(dto.MESSAGE_SUCCESS_SETUP_TWO_FACTOR, result)
+	response.JSON(ctx, http.StatusOK, res)
+}
+
+func (c *twoFactorController) Confirm(ctx *gin.Context) {
+	reqCtx, cancel := context.WithTimeout(ctx.Request.Context(), 20*time.Second)
+	defer cancel()
+
+	userId := ctx.MustGet("user_id").(string)
+	var req dto.TwoFactorConfirmRequest
+
+	if err := ctx.ShouldBind(&req); err != nil {
+		response.Fail(ctx, dto.MESSAGE_FAILED_GET_DATA_FROM_BODY, dto.NewValidationError(err))
+		return
+	}
+
+	result, err := c.twoFactorService.Confirm(reqCtx, uuid.MustParse(userId), req)
+	if err != nil {
+		response.Fail(ctx, dto.MESSAGE_FAILED_CONFIRM_TWO_FACTOR, err)
+		return
+	}
+
+	res := response.BuildResponseSuccess(dto.MESSAGE_SUCCESS_CONFIRM_TWO_FACTOR, result)
+	response.JSON(ctx, http.StatusOK, res)
+}
+
+func (c *twoFactorController) Disable(ctx *gin.Context) {
+	reqCtx, cancel := context.WithTimeout(ctx.Request.Context(), 20*time.Second)
+	defer cancel()
+
+	userId := ctx.MustGet("user_id").(string)
+	var req dto.TwoFactorDisableRequest
+
+	if err := ctx.ShouldBind(&req); err != nil {
+		response.Fail(ctx, dto.MESSAGE_FAILED_GET_DATA_FROM_BODY, dto.NewValidationError(err))
+		return
+	}
+
+	if err := c.twoFactorService.Disable(reqCtx, uuid.MustParse(userId), req); err != nil {
+		response.Fail(ctx, dto.MESSAGE_FAILED_DISABLE_TWO_FACTOR, err)
+		return
+	}
+
+	res := response.BuildResponseSuccess(dto.MESSAGE_SUCCESS_DISABLE_TWO_FACTOR, nil)
+	response.JSON(ctx, http.StatusOK, res)
+}
+
+func (c *twoFactorController) Verify(ctx *gin.Context) {
+	reqCtx, cancel := context.WithTimeout(ctx.Request.Context(), 20*time.Second)
+	defer cancel()
+
+	var req dto.TwoFactorVerifyRequest
+	if err := ctx.ShouldBind(&req); err != nil {
+		response.Fail(ctx, dto.MESSAGE_FAILED_GET_DATA_FROM_BODY, dto.NewValidationError(err))
+		return
+	}
+
+	result, err := c.twoFactorService.Verify(reqCtx, req, ctx.ClientIP())
+	if err != nil {
+		response.Fail(ctx, dto.MESSAGE_FAILED_VERIFY_TWO_FACTOR, err)
+		return
+	}
+
+	res := response.BuildResponseSuccess(dto.MESSAGE_SUCCESS_LOGIN_USER, result)
+	response.JSON(ctx, http.StatusOK, res)
+}
+
+func (c *twoFactorController) EnrollSetup(ctx *gin.Context) {
+	reqCtx, cancel := context.WithTimeout(ctx.Request.Context(), 20*time.Second)
+	defer cancel()
+
+	var req dto.TwoFactorChallengeRequest
+	if err := ctx.ShouldBind(&req); err != nil {
+		response.Fail(ctx, dto.MESSAGE_FAILED_GET_DATA_FROM_BODY, dto.NewValidationError(err))
+		return
+	}
+
+	result, err := c.twoFactorService.EnrollSetup(reqCtx, req)
+	if err != nil {
+		response.Fail(ctx, dto.MESSAGE_FAILED_SETUP_TWO_FACTOR, err)
+		return
+	}
+
+	res := response.BuildResponseSuccess(dto.MESSAGE_SUCCESS_SETUP_TWO_FACTOR, result)
+	response.JSON(ctx, http.StatusOK, res)
+}
+
+func (c *twoFactorController) EnrollConfirm(ctx *gin.Context) {
+	reqCtx, cancel := context.WithTimeout(ctx.Request.Context(), 20*time.Second)
+	defer cancel()
+
+	var req dto.TwoFactorEnrollConfirmRequest
+	if err := ctx.ShouldBind(&req); err != nil {
+		response.Fail(ctx, dto.MESSAGE_FAILED_GET_DATA_FROM_BODY, dto.NewValidationError(err))
+		return
+	}
+
+	result, err := c.twoFactorService.EnrollConfirm(reqCtx, req, ctx.ClientIP())
+	if err != nil {
+		response.Fail(ctx, dto.MESSAGE_FAILED_CONFIRM_TWO_FACTOR, err)
+		return
+	}
+
+	res := response.BuildResponseSuccess(dto.MESSAGE_SUCCESS_CONFIRM_TWO_FACTOR, result)
+	response.JSON(ctx, http.StatusOK, res)
+}
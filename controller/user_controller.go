@@ -5,6 +5,7 @@ import (
 	"net/http"
 	"time"
 
+	"github.com/Shabrinashsf/go-gin-gorm-boilerplate/constants"
 	"github.com/Shabrinashsf/go-gin-gorm-boilerplate/dto"
 	"github.com/Shabrinashsf/go-gin-gorm-boilerplate/service"
 	"github.com/Shabrinashsf/go-gin-gorm-boilerplate/utils/response"
@@ -16,12 +17,18 @@ type (
 	UserController interface {
 		RegisterUser(ctx *gin.Context)
 		Login(ctx *gin.Context)
+		RefreshToken(ctx *gin.Context)
+		UnlockAccount(ctx *gin.Context)
+		Logout(ctx *gin.Context)
 		SendVerificationEmail(ctx *gin.Context)
 		VerifyEmail(ctx *gin.Context)
 		ForgotPassword(ctx *gin.Context)
 		ResetPassword(ctx *gin.Context)
 		MeAuth(ctx *gin.Context)
 		UpdateUser(ctx *gin.Context)
+		ChangePassword(ctx *gin.Context)
+		ChangeEmail(ctx *gin.Context)
+		ConfirmEmailChange(ctx *gin.Context)
 	}
 
 	userController struct {
@@ -41,20 +48,18 @@ func (c *userController) RegisterUser(ctx *gin.Context) {
 
 	var req dto.UserRegistrationRequest
 	if err := ctx.ShouldBind(&req); err != nil {
-		res := response.BuildResponseFailed(dto.MESSAGE_FAILED_GET_DATA_FROM_BODY, err.Error(), nil)
-		ctx.AbortWithStatusJSON(http.StatusBadRequest, res)
+		response.Fail(ctx, dto.MESSAGE_FAILED_GET_DATA_FROM_BODY, dto.NewValidationError(err))
 		return
 	}
 
 	result, err := c.userService.RegisterUser(reqCtx, req)
 	if err != nil {
-		res := response.BuildResponseFailed(dto.MESSAGE_FAILED_REGISTER_USER, err.Error(), nil)
-		ctx.JSON(http.StatusBadRequest, res)
+		response.Fail(ctx, dto.MESSAGE_FAILED_REGISTER_USER, err)
 		return
 	}
 
 	res := response.BuildResponseSuccess(dto.MESSAGE_SUCCESS_REGISTER_USER, result)
-	ctx.JSON(http.StatusCreated, res)
+	response.JSON(ctx, http.StatusCreated, res)
 }
 
 func (c *userController) Login(ctx *gin.Context) {
@@ -63,20 +68,70 @@ func (c *userController) Login(ctx *gin.Context) {
 
 	var req dto.UserLoginRequest
 	if err := ctx.ShouldBind(&req); err != nil {
-		res := response.BuildResponseFailed(dto.MESSAGE_FAILED_GET_DATA_FROM_BODY, err.Error(), nil)
-		ctx.AbortWithStatusJSON(http.StatusBadRequest, res)
+		response.Fail(ctx, dto.MESSAGE_FAILED_GET_DATA_FROM_BODY, dto.NewValidationError(err))
 		return
 	}
 
-	result, err := c.userService.Login(reqCtx, req)
+	result, err := c.userService.Login(reqCtx, req, ctx.ClientIP())
 	if err != nil {
-		res := response.BuildResponseFailed(dto.MESSAGE_FAILED_LOGIN_USER, err.Error(), nil)
-		ctx.JSON(http.StatusBadRequest, res)
+		response.Fail(ctx, dto.MESSAGE_FAILED_LOGIN_USER, err)
 		return
 	}
 
 	res := response.BuildResponseSuccess(dto.MESSAGE_SUCCESS_LOGIN_USER, result)
-	ctx.JSON(http.StatusOK, res)
+	response.JSON(ctx, http.StatusOK, res)
+}
+
+func (c *userController) UnlockAccount(ctx *gin.Context) {
+	reqCtx, cancel := context.WithTimeout(ctx.Request.Context(), 20*time.Second)
+	defer cancel()
+
+	token := ctx.Query("token")
+	if token == "" {
+		response.Fail(ctx, dto.MESSAGE_FAILED_TOKEN_NOT_FOUND, dto.ErrTokenRequired)
+		return
+	}
+
+	if err := c.userService.UnlockAccount(reqCtx, token); err != nil {
+		response.Fail(ctx, dto.MESSAGE_FAILED_UNLOCK_ACCOUNT, err)
+		return
+	}
+
+	res := response.BuildResponseSuccess(dto.MESSAGE_SUCCESS_UNLOCK_ACCOUNT, nil)
+	response.JSON(ctx, http.StatusOK, res)
+}
+
+func (c *userController) RefreshToken(ctx *gin.Context) {
+	reqCtx, cancel := context.WithTimeout(ctx.Request.Context(), 20*time.Second)
+	defer cancel()
+
+	var req dto.RefreshTokenRequest
+	if err := ctx.ShouldBind(&req); err != nil {
+		response.Fail(ctx, dto.MESSAGE_FAILED_GET_DATA_FROM_BODY, dto.NewValidationError(err))
+		return
+	}
+
+	result, err := c.userService.RefreshToken(reqCtx, req)
+	if err != nil {
+		response.Fail(ctx, dto.MESSAGE_FAILED_REFRESH_TOKEN, err)
+		return
+	}
+
+	res := response.BuildResponseSuccess(dto.MESSAGE_SUCCESS_REFRESH_TOKEN, result)
+	response.JSON(ctx, http.StatusOK, res)
+}
+
+func (c *userController) Logout(ctx *gin.Context) {
+	userId := ctx.MustGet("user_id").(string)
+	sessionId := ctx.MustGet(constants.CTX_KEY_SESSION).(string)
+
+	if err := c.userService.Logout(ctx.Request.Context(), uuid.MustParse(userId), uuid.MustParse(sessionId)); err != nil {
+		response.Fail(ctx, dto.MESSAGE_FAILED_LOGOUT_USER, err)
+		return
+	}
+
+	res := response.BuildResponseSuccess(dto.MESSAGE_SUCCESS_LOGOUT_USER, nil)
+	response.JSON(ctx, http.StatusOK, res)
 }
 
 func (c *userController) SendVerificationEmail(ctx *gin.Context) {
@@ -85,20 +140,18 @@ func (c *userController) SendVerificationEmail(ctx *gin.Context) {
 
 	var req dto.SendVerificationEmailRequest
 	if err := ctx.ShouldBind(&req); err != nil {
-		res := response.BuildResponseFailed(dto.MESSAGE_FAILED_GET_DATA_FROM_BODY, err.Error(), nil)
-		ctx.AbortWithStatusJSON(http.StatusBadRequest, res)
+		response.Fail(ctx, dto.MESSAGE_FAILED_GET_DATA_FROM_BODY, dto.NewValidationError(err))
 		return
 	}
 
 	err := c.userService.SendVerificationEmail(reqCtx, req)
 	if err != nil {
-		res := response.BuildResponseFailed(dto.MESSAGE_FAILED_PROSES_REQUEST, err.Error(), nil)
-		ctx.AbortWithStatusJSON(http.StatusBadRequest, res)
+		response.Fail(ctx, dto.MESSAGE_FAILED_PROSES_REQUEST, err)
 		return
 	}
 
 	res := response.BuildResponseSuccess(dto.MESSAGE_SEND_VERIFICATION_EMAIL_SUCCESS, nil)
-	ctx.JSON(http.StatusOK, res)
+	response.JSON(ctx, http.StatusOK, res)
 }
 
 func (c *userController) VerifyEmail(ctx *gin.Context) {
@@ -108,8 +161,7 @@ func (c *userController) VerifyEmail(ctx *gin.Context) {
 	token := ctx.Query("token")
 
 	if token == "" {
-		res := response.BuildResponseFailed(dto.MESSAGE_FAILED_TOKEN_NOT_FOUND, "token not found", nil)
-		ctx.AbortWithStatusJSON(http.StatusBadRequest, res)
+		response.Fail(ctx, dto.MESSAGE_FAILED_TOKEN_NOT_FOUND, dto.ErrTokenRequired)
 		return
 	}
 
@@ -118,20 +170,18 @@ func (c *userController) VerifyEmail(ctx *gin.Context) {
 	}
 
 	if err := ctx.ShouldBind(&req); err != nil {
-		res := response.BuildResponseFailed(dto.MESSAGE_FAILED_GET_DATA_FROM_BODY, err.Error(), nil)
-		ctx.AbortWithStatusJSON(http.StatusBadRequest, res)
+		response.Fail(ctx, dto.MESSAGE_FAILED_GET_DATA_FROM_BODY, dto.NewValidationError(err))
 		return
 	}
 
 	result, err := c.userService.VerifyEmail(reqCtx, req)
 	if err != nil {
-		res := response.BuildResponseFailed(dto.MESSAGE_FAILED_VERIFY_EMAIL, err.Error(), nil)
-		ctx.AbortWithStatusJSON(http.StatusBadRequest, res)
+		response.Fail(ctx, dto.MESSAGE_FAILED_VERIFY_EMAIL, err)
 		return
 	}
 
 	res := response.BuildResponseSuccess(dto.MESSAGE_SUCCESS_VERIFY_EMAIL, result)
-	ctx.JSON(http.StatusOK, res)
+	response.JSON(ctx, http.StatusOK, res)
 }
 
 func (c *userController) ForgotPassword(ctx *gin.Context) {
@@ -140,19 +190,17 @@ func (c *userController) ForgotPassword(ctx *gin.Context) {
 
 	var req dto.ForgotPasswordRequest
 	if err := ctx.ShouldBind(&req); err != nil {
-		res := response.BuildResponseFailed(dto.MESSAGE_FAILED_GET_DATA_FROM_BODY, err.Error(), nil)
-		ctx.AbortWithStatusJSON(http.StatusBadRequest, res)
+		response.Fail(ctx, dto.MESSAGE_FAILED_GET_DATA_FROM_BODY, dto.NewValidationError(err))
 		return
 	}
 
 	if err := c.userService.ForgotPassword(reqCtx, req); err != nil {
-		res := response.BuildResponseFailed(dto.MESSAGE_FAILED_FORGET_PASSWORD, err.Error(), nil)
-		ctx.AbortWithStatusJSON(http.StatusBadRequest, res)
+		response.Fail(ctx, dto.MESSAGE_FAILED_FORGET_PASSWORD, err)
 		return
 	}
 
 	res := response.BuildResponseSuccess(dto.MESSAGE_SUCCESS_FORGET_PASSWORD, nil)
-	ctx.JSON(http.StatusOK, res)
+	response.JSON(ctx, http.StatusOK, res)
 }
 
 func (c *userController) ResetPassword(ctx *gin.Context) {
@@ -163,19 +211,17 @@ func (c *userController) ResetPassword(ctx *gin.Context) {
 	var req dto.ResetPasswordRequest
 
 	if err := ctx.ShouldBind(&req); err != nil {
-		res := response.BuildResponseFailed(dto.MESSAGE_FAILED_GET_DATA_FROM_BODY, err.Error(), nil)
-		ctx.AbortWithStatusJSON(http.StatusBadRequest, res)
+		response.Fail(ctx, dto.MESSAGE_FAILED_GET_DATA_FROM_BODY, dto.NewValidationError(err))
 		return
 	}
 
 	if err := c.userService.ResetPassword(reqCtx, token, req.Password); err != nil {
-		res := response.BuildResponseFailed(dto.MESSAGE_FAILED_RESET_PASSWORD, err.Error(), nil)
-		ctx.AbortWithStatusJSON(http.StatusBadRequest, res)
+		response.Fail(ctx, dto.MESSAGE_FAILED_RESET_PASSWORD, err)
 		return
 	}
 
 	res := response.BuildResponseSuccess(dto.MESSAGE_SUCCESS_RESET_PASSWORD, nil)
-	ctx.JSON(http.StatusOK, res)
+	response.JSON(ctx, http.StatusOK, res)
 }
 
 func (c *userController) MeAuth(ctx *gin.Context) {
@@ -183,13 +229,12 @@ func (c *userController) MeAuth(ctx *gin.Context) {
 
 	result, err := c.userService.GetUserByID(ctx.Request.Context(), uuid.MustParse(userId))
 	if err != nil {
-		res := response.BuildResponseFailed(dto.MESSAGE_FAILED_GET_USER, err.Error(), nil)
-		ctx.AbortWithStatusJSON(http.StatusBadRequest, res)
+		response.Fail(ctx, dto.MESSAGE_FAILED_GET_USER, err)
 		return
 	}
 
 	res := response.BuildResponseSuccess(dto.MESSAGE_SUCCESS_GET_USER, result)
-	ctx.JSON(http.StatusOK, res)
+	response.JSON(ctx, http.StatusOK, res)
 }
 
 func (c *userController) UpdateUser(ctx *gin.Context) {
@@ -200,18 +245,80 @@ func (c *userController) UpdateUser(ctx *gin.Context) {
 	var req dto.UserUpdateRequest
 
 	if err := ctx.ShouldBind(&req); err != nil {
-		res := response.BuildResponseFailed(dto.MESSAGE_FAILED_GET_DATA_FROM_BODY, err.Error(), nil)
-		ctx.AbortWithStatusJSON(http.StatusBadRequest, res)
+		response.Fail(ctx, dto.MESSAGE_FAILED_GET_DATA_FROM_BODY, dto.NewValidationError(err))
 		return
 	}
 
 	result, err := c.userService.UpdateUser(reqCtx, uuid.MustParse(userId), req)
 	if err != nil {
-		res := response.BuildResponseFailed(dto.MESSAGE_FAILED_UPDATE_USER, err.Error(), nil)
-		ctx.AbortWithStatusJSON(http.StatusBadRequest, res)
+		response.Fail(ctx, dto.MESSAGE_FAILED_UPDATE_USER, err)
 		return
 	}
 
 	res := response.BuildResponseSuccess(dto.MESSAGE_SUCCESS_UPDATE_USER, result)
-	ctx.JSON(http.StatusOK, res)
+	response.JSON(ctx, http.StatusOK, res)
+}
+
+func (c *userController) ChangePassword(ctx *gin.Context) {
+	reqCtx, cancel := context.WithTimeout(ctx.Request.Context(), 20*time.Second)
+	defer cancel()
+
+	userId := ctx.MustGet("user_id").(string)
+	sessionId := ctx.MustGet(constants.CTX_KEY_SESSION).(string)
+	var req dto.ChangePasswordRequest
+
+	if err := ctx.ShouldBind(&req); err != nil {
+		response.Fail(ctx, dto.MESSAGE_FAILED_GET_DATA_FROM_BODY, dto.NewValidationError(err))
+		return
+	}
+
+	if err := c.userService.ChangePassword(reqCtx, uuid.MustParse(userId), uuid.MustParse(sessionId), req); err != nil {
+		response.Fail(ctx, dto.MESSAGE_FAILED_CHANGE_PASSWORD, err)
+		return
+	}
+
+	res := response.BuildResponseSuccess(dto.MESSAGE_SUCCESS_CHANGE_PASSWORD, nil)
+	response.JSON(ctx, http.StatusOK, res)
+}
+
+func (c *userController) ChangeEmail(ctx *gin.Context) {
+	reqCtx, cancel := context.WithTimeout(ctx.Request.Context(), 20*time.Second)
+	defer cancel()
+
+	userId := ctx.MustGet("user_id").(string)
+	var req dto.ChangeEmailRequest
+
+	if err := ctx.ShouldBind(&req); err != nil {
+		response.Fail(ctx, dto.MESSAGE_FAILED_GET_DATA_FROM_BODY, dto.NewValidationError(err))
+		return
+	}
+
+	result, err := c.userService.ChangeEmail(reqCtx, uuid.MustParse(userId), req)
+	if err != nil {
+		response.Fail(ctx, dto.MESSAGE_FAILED_CHANGE_EMAIL, err)
+		return
+	}
+
+	res := response.BuildResponseSuccess(dto.MESSAGE_SUCCESS_CHANGE_EMAIL, result)
+	response.JSON(ctx, http.StatusOK, res)
+}
+
+func (c *userController) ConfirmEmailChange(ctx *gin.Context) {
+	reqCtx, cancel := context.WithTimeout(ctx.Request.Context(), 20*time.Second)
+	defer cancel()
+
+	token := ctx.Query("token")
+	if token == "" {
+		response.Fail(ctx, dto.MESSAGE_FAILED_TOKEN_NOT_FOUND, dto.ErrTokenRequired)
+		return
+	}
+
+	result, err := c.userService.ConfirmEmailChange(reqCtx, token)
+	if err != nil {
+		response.Fail(ctx, dto.MESSAGE_FAILED_CONFIRM_EMAIL, err)
+		return
+	}
+
+	res := response.BuildResponseSuccess(dto.MESSAGE_SUCCESS_CONFIRM_EMAIL, result)
+	response.JSON(ctx, http.StatusOK, res)
 }
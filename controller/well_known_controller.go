@@ -0,0 +1,31 @@
+package controller
+
+import (
+	"net/http"
+
+	"github.com/Shabrinashsf/go-gin-gorm-boilerplate/service"
+	"github.com/gin-gonic/gin"
+)
+
+type (
+	WellKnownController interface {
+		JWKS(ctx *gin.Context)
+	}
+
+	wellKnownController struct {
+		jwtService service.JWTService
+	}
+)
+
+func NewWellKnownController(js service.JWTService) WellKnownController {
+	return &wellKnownController{
+		jwtService: js,
+	}
+}
+
+// JWKS is served without the usual response envelope so standard JWT
+// libraries in other services can consume it directly.
+func (c *wellKnownController) JWKS(ctx *gin.Context) {
+	ctx.Header("Cache-Control", "public, max-age=300")
+	ctx.JSON(http.StatusOK, c.jwtService.GetJWKS())
+}
@@ -1,38 +1,177 @@
 package database
 
 import (
+	"context"
+	"database/sql"
 	"fmt"
-	"os"
+	"strings"
+	"time"
 
+	"github.com/Shabrinashsf/go-gin-gorm-boilerplate/config"
+	"github.com/Shabrinashsf/go-gin-gorm-boilerplate/constants"
+	"github.com/Shabrinashsf/go-gin-gorm-boilerplate/utils/logger"
 	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
+	"gorm.io/plugin/dbresolver"
 )
 
-func SetUpDatabaseConnection() *gorm.DB {
-	dbUser := os.Getenv("DB_USER")
-	dbPassword := os.Getenv("DB_PASS")
-	dbName := os.Getenv("DB_NAME")
-	dbHost := os.Getenv("DB_HOST")
-	dbPort := os.Getenv("DB_PORT")
+const MAX_CONNECT_BACKOFF = 30 * time.Second
 
-	connection := fmt.Sprintf("host=%v user=%v password=%v dbname=%v port=%v TimeZone=Asia/Jakarta", dbHost, dbUser, dbPassword, dbName, dbPort)
+// SetUpDatabaseConnection connects to the primary and, when DB_REPLICA_HOSTS
+// is set, registers the replicas for repository.ReadOnly queries. Postgres is
+// often still starting when the app boots, so failed attempts are retried
+// with exponential backoff.
+func SetUpDatabaseConnection(cfg config.DatabaseConfig) (*gorm.DB, error) {
+	backoff := cfg.ConnectBackoff
 
+	for attempt := 0; ; attempt++ {
+		db, err := connect(cfg)
+		if err == nil {
+			return db, nil
+		}
+		if attempt >= cfg.ConnectRetries {
+			return nil, fmt.Errorf("connect to database after %d attempts: %w", attempt+1, err)
+		}
+
+		logger.Warnf("database connection failed (attempt %d/%d), retrying in %s: %v", attempt+1, cfg.ConnectRetries+1, backoff, err)
+		time.Sleep(backoff)
+		backoff = min(backoff*2, MAX_CONNECT_BACKOFF)
+	}
+}
+
+func connect(cfg config.DatabaseConfig) (*gorm.DB, error) {
 	db, err := gorm.Open(postgres.New(postgres.Config{
-		DSN:                  connection,
+		DSN:                  DSN(cfg, cfg.Host, cfg.Port),
 		PreferSimpleProtocol: true,
 	}), &gorm.Config{})
+	if err != nil {
+		return nil, err
+	}
 
+	sqlDB, err := db.DB()
 	if err != nil {
-		panic(err)
+		return nil, err
+	}
+	if err := RegisterMetrics(db); err != nil {
+		sqlDB.Close()
+		return nil, fmt.Errorf("register query metrics: %w", err)
+	}
+	if err := RegisterTracing(db); err != nil {
+		sqlDB.Close()
+		return nil, fmt.Errorf("register query tracing: %w", err)
 	}
+	sqlDB.SetMaxOpenConns(cfg.MaxOpenConns)
+	sqlDB.SetMaxIdleConns(cfg.MaxIdleConns)
+	sqlDB.SetConnMaxLifetime(cfg.ConnMaxLifetime)
+	sqlDB.SetConnMaxIdleTime(cfg.ConnMaxIdleTime)
 
-	return db
+	replicas, err := cfg.ReplicaAddresses()
+	if err != nil {
+		sqlDB.Close()
+		return nil, err
+	}
+	if len(replicas) == 0 {
+		return db, nil
+	}
+
+	dialectors := make([]gorm.Dialector, 0, len(replicas))
+	for _, replica := range replicas {
+		dialectors = append(dialectors, postgres.New(postgres.Config{
+			DSN:                  DSN(cfg, replica.Host, replica.Port),
+			PreferSimpleProtocol: true,
+		}))
+	}
+
+	// Registered under a name instead of globally: only queries that opt in
+	// through repository.ReadOnly go to a replica, everything else keeps
+	// reading its own writes from the primary.
+	resolver := dbresolver.Register(dbresolver.Config{
+		Replicas: dialectors,
+		Policy:   dbresolver.RandomPolicy{},
+	}, constants.DB_RESOLVER_REPLICA)
+	if err := db.Use(resolver); err != nil {
+		sqlDB.Close()
+		return nil, fmt.Errorf("register read replicas: %w", err)
+	}
+	resolver.
+		SetMaxOpenConns(cfg.MaxOpenConns).
+		SetMaxIdleConns(cfg.MaxIdleConns).
+		SetConnMaxLifetime(cfg.ConnMaxLifetime).
+		SetConnMaxIdleTime(cfg.ConnMaxIdleTime)
+
+	logger.Infof("Registered %d read replica(s)", len(replicas))
+	return db, nil
 }
 
+// DSN builds a libpq keyword/value connection string for the given host.
+func DSN(cfg config.DatabaseConfig, host string, port int) string {
+	pairs := []string{
+		"host=" + dsnValue(host),
+		"user=" + dsnValue(cfg.User),
+		"password=" + dsnValue(cfg.Password),
+		"dbname=" + dsnValue(cfg.Name),
+		fmt.Sprintf("port=%d", port),
+		"sslmode=" + dsnValue(cfg.SSLMode),
+		"TimeZone=" + dsnValue(cfg.TimeZone),
+	}
+	if cfg.Options != "" {
+		pairs = append(pairs, cfg.Options)
+	}
+
+	return strings.Join(pairs, " ")
+}
+
+// dsnValue quotes a value when it is empty or contains characters libpq
+// would otherwise split on.
+func dsnValue(value string) string {
+	if value != "" && !strings.ContainsAny(value, ` '\`) {
+		return value
+	}
+	value = strings.ReplaceAll(value, `\`, `\\`)
+	value = strings.ReplaceAll(value, `'`, `\'`)
+	return "'" + value + "'"
+}
+
+// CloseDatabaseConnection closes the primary pool and every replica pool.
 func CloseDatabaseConnection(db *gorm.DB) {
 	dbSQL, err := db.DB()
 	if err != nil {
-		panic(err)
+		logger.Errorf("close database: %v", err)
+		return
+	}
+
+	for _, replica := range replicaPools(db, dbSQL) {
+		replica.Close()
+	}
+
+	if err := dbSQL.Close(); err != nil {
+		logger.Errorf("close database: %v", err)
+	}
+}
+
+// Ping checks that the primary accepts connections.
+func Ping(ctx context.Context, db *gorm.DB) error {
+	dbSQL, err := db.DB()
+	if err != nil {
+		return err
 	}
-	dbSQL.Close()
+	return dbSQL.PingContext(ctx)
+}
+
+// replicaPools returns the connection pools opened by the replica resolver,
+// excluding the primary which dbresolver reuses as the write source.
+func replicaPools(db *gorm.DB, primary *sql.DB) []*sql.DB {
+	resolver, ok := db.Config.Plugins[(&dbresolver.DBResolver{}).Name()].(*dbresolver.DBResolver)
+	if !ok {
+		return nil
+	}
+
+	var pools []*sql.DB
+	resolver.Call(func(connPool gorm.ConnPool) error {
+		if pool, ok := connPool.(*sql.DB); ok && pool != primary {
+			pools = append(pools, pool)
+		}
+		return nil
+	})
+	return pools
 }
@@ -0,0 +1,77 @@
+package database
+
+import (
+	"errors"
+	"time"
+
+	"github.com/Shabrinashsf/go-gin-gorm-boilerplate/utils/metrics"
+	"gorm.io/gorm"
+)
+
+const METRICS_START_KEY = "metrics:start"
+
+// RegisterMetrics times every GORM statement and reports it to the
+// boilerplate_db_query_duration_seconds histogram.
+func RegisterMetrics(db *gorm.DB) error {
+	callbacks := db.Callback()
+
+	if err := callbacks.Create().Before("gorm:create").Register("metrics:before_create", startQueryTimer); err != nil {
+		return err
+	}
+	if err := callbacks.Create().After("gorm:create").Register("metrics:after_create", observeQuery("create")); err != nil {
+		return err
+	}
+	if err := callbacks.Query().Before("gorm:query").Register("metrics:before_query", startQueryTimer); err != nil {
+		return err
+	}
+	if err := callbacks.Query().After("gorm:query").Register("metrics:after_query", observeQuery("query")); err != nil {
+		return err
+	}
+	if err := callbacks.Update().Before("gorm:update").Register("metrics:before_update", startQueryTimer); err != nil {
+		return err
+	}
+	if err := callbacks.Update().After("gorm:update").Register("metrics:after_update", observeQuery("update")); err != nil {
+		return err
+	}
+	if err := callbacks.Delete().Before("gorm:delete").Register("metrics:before_delete", startQueryTimer); err != nil {
+		return err
+	}
+	if err := callbacks.Delete().After("gorm:delete").Register("metrics:after_delete", observeQuery("delete")); err != nil {
+		return err
+	}
+	if err := callbacks.Row().Before("gorm:row").Register("metrics:before_row", startQueryTimer); err != nil {
+		return err
+	}
+	if err := callbacks.Row().After("gorm:row").Register("metrics:after_row", observeQuery("row")); err != nil {
+		return err
+	}
+	if err := callbacks.Raw().Before("gorm:raw").Register("metrics:before_raw", startQueryTimer); err != nil {
+		return err
+	}
+	return callbacks.Raw().After("gorm:raw").Register("metrics:after_raw", observeQuery("raw"))
+}
+
+func startQueryTimer(db *gorm.DB) {
+	db.InstanceSet(METRICS_START_KEY, time.Now())
+}
+
+func observeQuery(operation string) func(db *gorm.DB) {
+	return func(db *gorm.DB) {
+		value, ok := db.InstanceGet(METRICS_START_KEY)
+		if !ok {
+			return
+		}
+		start, ok := value.(time.Time)
+		if !ok {
+			return
+		}
+
+		table := db.Statement.Table
+		if table == "" {
+			table = "unknown"
+		}
+		failed := db.Error != nil && !errors.Is(db.Error, gorm.ErrRecordNotFound)
+
+		metrics.ObserveDBQuery(operation, table, time.Since(start), failed)
+	}
+}
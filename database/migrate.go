@@ -1,18 +1,33 @@
 package database
 
 import (
-	"github.com/Shabrinashsf/go-gin-gorm-boilerplate/entity"
+	"context"
+
+	"github.com/Shabrinashsf/go-gin-gorm-boilerplate/database/migrations"
+	"github.com/Shabrinashsf/go-gin-gorm-boilerplate/database/migrator"
 	"gorm.io/gorm"
 )
 
-func Migrate(db *gorm.DB) error {
-	db.Exec("CREATE EXTENSION IF NOT EXISTS \"uuid-ossp\";")
+// MIGRATIONS_DIR is where --migrate-create writes new files, they are
+// embedded into the binary through the migrations package.
+var MIGRATIONS_DIR = "database/migrations"
+
+func NewMigrator(db *gorm.DB) (*migrator.Migrator, error) {
+	return migrator.New(db, migrations.FS)
+}
 
-	if err := db.AutoMigrate(
-		&entity.User{},
-	); err != nil {
+// Migrate applies every pending migration.
+func Migrate(db *gorm.DB) error {
+	m, err := NewMigrator(db)
+	if err != nil {
 		return err
 	}
 
-	return nil
+	_, err = m.Up(context.Background())
+	return err
+}
+
+// CreateMigration writes an empty up/down SQL pair to MIGRATIONS_DIR.
+func CreateMigration(name string) ([]string, error) {
+	return migrator.Create(MIGRATIONS_DIR, name)
 }
@@ -0,0 +1,237 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/Shabrinashsf/go-gin-gorm-boilerplate/database/migrations"
+	"github.com/Shabrinashsf/go-gin-gorm-boilerplate/database/migrator"
+	"github.com/Shabrinashsf/go-gin-gorm-boilerplate/entity"
+	"github.com/google/uuid"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	gormlogger "gorm.io/gorm/logger"
+)
+
+func TestEmbeddedMigrations(t *testing.T) {
+	loaded, err := migrator.Load(migrations.FS)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if len(loaded) == 0 {
+		t.Fatal("no migrations embedded")
+	}
+
+	for i, m := range loaded {
+		if m.Version != int64(i+1) {
+			t.Errorf("migration %d_%s, want version %d, versions must not have gaps", m.Version, m.Name, i+1)
+		}
+		if m.Down == nil {
+			t.Errorf("migration %d_%s has no down migration", m.Version, m.Name)
+		}
+	}
+}
+
+// baselineUser is entity.User as it was before versioned migrations, when
+// the schema was created with AutoMigrate.
+type baselineUser struct {
+	ID uuid.UUID `gorm:"type:uuid;primary_key;default:uuid_generate_v4()"`
+
+	Name       string
+	Email      string
+	Password   string
+	Instansi   string
+	NoTelp     string
+	Role       string `gorm:"default:user"`
+	IsVerified bool
+
+	CreatedAt time.Time `gorm:"type:timestamp with time zone"`
+	UpdatedAt time.Time `gorm:"type:timestamp with time zone"`
+	DeletedAt gorm.DeletedAt
+}
+
+func (baselineUser) TableName() string {
+	return "users"
+}
+
+// testDatabase connects to TEST_DATABASE_URL, a postgres:// URL, with a
+// fresh schema first in the search path that is dropped after the test.
+func testDatabase(t *testing.T) *gorm.DB {
+	t.Helper()
+
+	dsn := os.Getenv("TEST_DATABASE_URL")
+	if dsn == "" {
+		t.Skip("TEST_DATABASE_URL is not set, skipping tests that need Postgres")
+	}
+
+	config := &gorm.Config{Logger: gormlogger.Default.LogMode(gormlogger.Silent)}
+	admin, err := gorm.Open(postgres.Open(dsn), config)
+	if err != nil {
+		t.Fatalf("connect: %v", err)
+	}
+
+	schema := fmt.Sprintf("migrate_test_%d", time.Now().UnixNano())
+	if err := admin.Exec(`CREATE EXTENSION IF NOT EXISTS "uuid-ossp"`).Error; err != nil {
+		t.Fatalf("create extension: %v", err)
+	}
+	if err := admin.Exec("CREATE SCHEMA " + schema).Error; err != nil {
+		t.Fatalf("create schema: %v", err)
+	}
+
+	u, err := url.Parse(dsn)
+	if err != nil {
+		t.Fatalf("TEST_DATABASE_URL must be a postgres:// URL: %v", err)
+	}
+	query := u.Query()
+	query.Set("search_path", schema+",public")
+	u.RawQuery = query.Encode()
+
+	db, err := gorm.Open(postgres.Open(u.String()), config)
+	if err != nil {
+		t.Fatalf("connect to schema %s: %v", schema, err)
+	}
+
+	t.Cleanup(func() {
+		if sqlDB, err := db.DB(); err == nil {
+			sqlDB.Close()
+		}
+		admin.Exec("DROP SCHEMA " + schema + " CASCADE")
+		if sqlDB, err := admin.DB(); err == nil {
+			sqlDB.Close()
+		}
+	})
+
+	return db
+}
+
+func TestMigrateFreshDatabase(t *testing.T) {
+	db := testDatabase(t)
+	ctx := context.Background()
+
+	m, err := NewMigrator(db)
+	if err != nil {
+		t.Fatalf("NewMigrator() error = %v", err)
+	}
+
+	applied, err := m.Up(ctx)
+	if err != nil {
+		t.Fatalf("Up() error = %v", err)
+	}
+	for i := 1; i < len(applied); i++ {
+		if applied[i].Version <= applied[i-1].Version {
+			t.Fatalf("Up() applied %d after %d", applied[i].Version, applied[i-1].Version)
+		}
+	}
+
+	again, err := m.Up(ctx)
+	if err != nil || len(again) != 0 {
+		t.Fatalf("second Up() = %d migrations, %v, want nothing to do", len(again), err)
+	}
+
+	// roll back the newest migration and apply it again
+	newest := applied[len(applied)-1]
+	if newest.Down == nil {
+		t.Skipf("newest migration %d_%s has no down migration", newest.Version, newest.Name)
+	}
+	reverted, err := m.Down(ctx, 1)
+	if err != nil {
+		t.Fatalf("Down() error = %v", err)
+	}
+	if len(reverted) != 1 || reverted[0].Version != newest.Version {
+		t.Fatalf("Down(1) reverted %v, want only %d", versions(reverted), newest.Version)
+	}
+
+	reapplied, err := m.Up(ctx)
+	if err != nil {
+		t.Fatalf("Up() after Down() error = %v", err)
+	}
+	if len(reapplied) != 1 || reapplied[0].Version != newest.Version {
+		t.Fatalf("Up() after Down(1) applied %v, want only %d", versions(reapplied), newest.Version)
+	}
+}
+
+func TestMigrateUpgradesBaselineSchema(t *testing.T) {
+	db := testDatabase(t)
+	ctx := context.Background()
+
+	if err := db.AutoMigrate(&baselineUser{}); err != nil {
+		t.Fatalf("create baseline schema: %v", err)
+	}
+	legacy := baselineUser{Name: "Legacy", Email: "legacy@example.com", Password: "hash", IsVerified: true}
+	if err := db.Create(&legacy).Error; err != nil {
+		t.Fatalf("insert baseline user: %v", err)
+	}
+
+	if err := Migrate(db); err != nil {
+		t.Fatalf("Migrate() on a baseline schema error = %v", err)
+	}
+
+	for _, column := range []string{"locale", "two_factor_enabled", "two_factor_secret", "two_factor_last_step", "pending_email", "is_suspended"} {
+		if !db.Migrator().HasColumn(&entity.User{}, column) {
+			t.Errorf("users.%s is missing after the upgrade", column)
+		}
+	}
+
+	var user entity.User
+	if err := db.Where("email = ?", legacy.Email).First(&user).Error; err != nil {
+		t.Fatalf("baseline user lost in the upgrade: %v", err)
+	}
+	if user.ID != legacy.ID || user.TwoFactorEnabled || user.IsSuspended {
+		t.Errorf("baseline user after the upgrade = %+v", user)
+	}
+
+	m, err := NewMigrator(db)
+	if err != nil {
+		t.Fatalf("NewMigrator() error = %v", err)
+	}
+	statuses, err := m.Status(ctx)
+	if err != nil {
+		t.Fatalf("Status() error = %v", err)
+	}
+	for _, status := range statuses {
+		if !status.Applied || status.Modified || status.Missing {
+			t.Errorf("migration %d_%s status = %+v, want applied", status.Version, status.Name, status)
+		}
+	}
+}
+
+func TestMigrateRefusesEditedMigration(t *testing.T) {
+	db := testDatabase(t)
+	ctx := context.Background()
+
+	if err := Migrate(db); err != nil {
+		t.Fatalf("Migrate() error = %v", err)
+	}
+	if err := db.Exec("UPDATE schema_migrations SET checksum = 'edited' WHERE version = 1").Error; err != nil {
+		t.Fatalf("edit checksum: %v", err)
+	}
+
+	m, err := NewMigrator(db)
+	if err != nil {
+		t.Fatalf("NewMigrator() error = %v", err)
+	}
+	if _, err := m.Up(ctx); !errors.Is(err, migrator.ErrChecksumMismatch) {
+		t.Fatalf("Up() error = %v, want %v", err, migrator.ErrChecksumMismatch)
+	}
+
+	statuses, err := m.Status(ctx)
+	if err != nil {
+		t.Fatalf("Status() error = %v", err)
+	}
+	if !statuses[0].Modified {
+		t.Errorf("Status() of the edited migration = %+v, want modified", statuses[0])
+	}
+}
+
+func versions(migrations []migrator.Migration) []int64 {
+	out := make([]int64, 0, len(migrations))
+	for _, m := range migrations {
+		out = append(out, m.Version)
+	}
+	return out
+}
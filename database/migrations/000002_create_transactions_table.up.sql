@@ -0,0 +1,17 @@
+CREATE TABLE IF NOT EXISTS transactions (
+    id          uuid PRIMARY KEY DEFAULT uuid_generate_v4(),
+    user_id     uuid REFERENCES users (id),
+    product_id  uuid,
+    amount_paid bigint,
+    type        text,
+    status      text,
+    invoice_url text,
+    reference   text,
+    created_at  timestamp with time zone,
+    updated_at  timestamp with time zone,
+    deleted_at  timestamp with time zone
+);
+
+CREATE INDEX IF NOT EXISTS idx_transactions_user_id ON transactions (user_id);
+CREATE INDEX IF NOT EXISTS idx_transactions_reference ON transactions (reference);
+CREATE INDEX IF NOT EXISTS idx_transactions_deleted_at ON transactions (deleted_at);
@@ -0,0 +1,51 @@
+CREATE TABLE IF NOT EXISTS refresh_tokens (
+    id          uuid PRIMARY KEY DEFAULT uuid_generate_v4(),
+    user_id     uuid REFERENCES users (id),
+    session_id  uuid,
+    token_hash  text,
+    expires_at  timestamp with time zone,
+    revoked_at  timestamp with time zone,
+    replaced_by uuid,
+    created_at  timestamp with time zone,
+    updated_at  timestamp with time zone,
+    deleted_at  timestamp with time zone
+);
+
+CREATE INDEX IF NOT EXISTS idx_refresh_tokens_user_id ON refresh_tokens (user_id);
+CREATE INDEX IF NOT EXISTS idx_refresh_tokens_session_id ON refresh_tokens (session_id);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_refresh_tokens_token_hash ON refresh_tokens (token_hash);
+
+CREATE TABLE IF NOT EXISTS password_reset_tokens (
+    id          uuid PRIMARY KEY DEFAULT uuid_generate_v4(),
+    user_id     uuid REFERENCES users (id),
+    token_hash  text,
+    expires_at  timestamp with time zone,
+    consumed_at timestamp with time zone,
+    created_at  timestamp with time zone,
+    updated_at  timestamp with time zone,
+    deleted_at  timestamp with time zone
+);
+
+CREATE INDEX IF NOT EXISTS idx_password_reset_tokens_user_id ON password_reset_tokens (user_id);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_password_reset_tokens_token_hash ON password_reset_tokens (token_hash);
+
+CREATE TABLE IF NOT EXISTS recovery_codes (
+    id         uuid PRIMARY KEY DEFAULT uuid_generate_v4(),
+    user_id    uuid REFERENCES users (id),
+    code_hash  text,
+    used_at    timestamp with time zone,
+    created_at timestamp with time zone,
+    updated_at timestamp with time zone,
+    deleted_at timestamp with time zone
+);
+
+CREATE INDEX IF NOT EXISTS idx_recovery_codes_user_id ON recovery_codes (user_id);
+CREATE INDEX IF NOT EXISTS idx_recovery_codes_code_hash ON recovery_codes (code_hash);
+
+CREATE TABLE IF NOT EXISTS login_attempts (
+    key             text PRIMARY KEY,
+    failures        bigint,
+    last_failure_at timestamp with time zone,
+    locked_until    timestamp with time zone,
+    updated_at      timestamp with time zone
+);
@@ -0,0 +1,33 @@
+CREATE TABLE IF NOT EXISTS permissions (
+    id          uuid PRIMARY KEY DEFAULT uuid_generate_v4(),
+    name        text,
+    description text,
+    created_at  timestamp with time zone,
+    updated_at  timestamp with time zone,
+    deleted_at  timestamp with time zone
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_permissions_name ON permissions (name);
+
+CREATE TABLE IF NOT EXISTS roles (
+    id          uuid PRIMARY KEY DEFAULT uuid_generate_v4(),
+    name        text,
+    description text,
+    created_at  timestamp with time zone,
+    updated_at  timestamp with time zone,
+    deleted_at  timestamp with time zone
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_roles_name ON roles (name);
+
+CREATE TABLE IF NOT EXISTS role_permissions (
+    role_id       uuid REFERENCES roles (id) ON DELETE CASCADE,
+    permission_id uuid REFERENCES permissions (id) ON DELETE CASCADE,
+    PRIMARY KEY (role_id, permission_id)
+);
+
+CREATE TABLE IF NOT EXISTS user_roles (
+    user_id uuid REFERENCES users (id) ON DELETE CASCADE,
+    role_id uuid REFERENCES roles (id) ON DELETE CASCADE,
+    PRIMARY KEY (user_id, role_id)
+);
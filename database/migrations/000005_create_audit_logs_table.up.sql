@@ -0,0 +1,17 @@
+CREATE TABLE IF NOT EXISTS audit_logs (
+    id          uuid PRIMARY KEY DEFAULT uuid_generate_v4(),
+    actor_id    uuid,
+    action      text,
+    target_type text,
+    target_id   text,
+    success     boolean,
+    ip_address  text,
+    user_agent  text,
+    changes     text,
+    created_at  timestamp with time zone
+);
+
+CREATE INDEX IF NOT EXISTS idx_audit_logs_actor_id ON audit_logs (actor_id);
+CREATE INDEX IF NOT EXISTS idx_audit_logs_action ON audit_logs (action);
+CREATE INDEX IF NOT EXISTS idx_audit_logs_target_id ON audit_logs (target_id);
+CREATE INDEX IF NOT EXISTS idx_audit_logs_created_at ON audit_logs (created_at);
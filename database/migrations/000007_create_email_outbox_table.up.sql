@@ -0,0 +1,17 @@
+CREATE TABLE IF NOT EXISTS email_outboxes (
+    id              uuid PRIMARY KEY DEFAULT uuid_generate_v4(),
+    to_email        text NOT NULL,
+    subject         text NOT NULL,
+    body            text NOT NULL,
+    status          text NOT NULL DEFAULT 'pending',
+    attempts        integer NOT NULL DEFAULT 0,
+    next_attempt_at timestamp with time zone NOT NULL DEFAULT now(),
+    last_error      text NOT NULL DEFAULT '',
+    sent_at         timestamp with time zone,
+    created_at      timestamp with time zone,
+    updated_at      timestamp with time zone
+);
+
+CREATE INDEX IF NOT EXISTS idx_email_outboxes_to_email ON email_outboxes (to_email);
+CREATE INDEX IF NOT EXISTS idx_email_outboxes_status ON email_outboxes (status);
+CREATE INDEX IF NOT EXISTS idx_email_outboxes_due ON email_outboxes (next_attempt_at) WHERE status = 'pending';
@@ -0,0 +1 @@
+ALTER TABLE email_outboxes DROP COLUMN IF EXISTS text_body;
@@ -0,0 +1 @@
+ALTER TABLE email_outboxes ADD COLUMN IF NOT EXISTS text_body text NOT NULL DEFAULT '';
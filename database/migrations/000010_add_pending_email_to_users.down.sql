@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS idx_users_pending_email_token_hash;
+
+ALTER TABLE users DROP COLUMN IF EXISTS pending_email_expires_at;
+ALTER TABLE users DROP COLUMN IF EXISTS pending_email_token_hash;
+ALTER TABLE users DROP COLUMN IF EXISTS pending_email;
@@ -0,0 +1,5 @@
+ALTER TABLE users ADD COLUMN IF NOT EXISTS pending_email text;
+ALTER TABLE users ADD COLUMN IF NOT EXISTS pending_email_token_hash text;
+ALTER TABLE users ADD COLUMN IF NOT EXISTS pending_email_expires_at timestamp with time zone;
+
+CREATE INDEX IF NOT EXISTS idx_users_pending_email_token_hash ON users (pending_email_token_hash);
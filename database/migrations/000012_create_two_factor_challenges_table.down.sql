@@ -0,0 +1,3 @@
+ALTER TABLE users DROP COLUMN IF EXISTS two_factor_last_step;
+
+DROP TABLE IF EXISTS two_factor_challenges;
@@ -0,0 +1,15 @@
+CREATE TABLE IF NOT EXISTS two_factor_challenges (
+    id          uuid PRIMARY KEY DEFAULT uuid_generate_v4(),
+    user_id     uuid REFERENCES users (id),
+    purpose     text,
+    failures    bigint DEFAULT 0,
+    expires_at  timestamp with time zone,
+    consumed_at timestamp with time zone,
+    created_at  timestamp with time zone,
+    updated_at  timestamp with time zone,
+    deleted_at  timestamp with time zone
+);
+
+CREATE INDEX IF NOT EXISTS idx_two_factor_challenges_user_id ON two_factor_challenges (user_id);
+
+ALTER TABLE users ADD COLUMN IF NOT EXISTS two_factor_last_step bigint DEFAULT 0;
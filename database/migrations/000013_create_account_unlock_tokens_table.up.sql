@@ -0,0 +1,13 @@
+CREATE TABLE IF NOT EXISTS account_unlock_tokens (
+    id          uuid PRIMARY KEY DEFAULT uuid_generate_v4(),
+    user_id     uuid REFERENCES users (id),
+    token_hash  text,
+    expires_at  timestamp with time zone,
+    consumed_at timestamp with time zone,
+    created_at  timestamp with time zone,
+    updated_at  timestamp with time zone,
+    deleted_at  timestamp with time zone
+);
+
+CREATE INDEX IF NOT EXISTS idx_account_unlock_tokens_user_id ON account_unlock_tokens (user_id);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_account_unlock_tokens_token_hash ON account_unlock_tokens (token_hash);
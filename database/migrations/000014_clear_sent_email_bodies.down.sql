@@ -0,0 +1,3 @@
+-- The bodies cleared from sent email are gone and cannot be restored, there
+-- is nothing to roll back
+SELECT 1;
@@ -0,0 +1,2 @@
+-- Sent email no longer keeps its body, clear what was stored before
+UPDATE email_outboxes SET body = '', text_body = '' WHERE status = 'sent';
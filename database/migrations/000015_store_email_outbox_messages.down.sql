@@ -0,0 +1,6 @@
+ALTER TABLE email_outboxes ADD COLUMN IF NOT EXISTS body text NOT NULL DEFAULT '';
+ALTER TABLE email_outboxes ADD COLUMN IF NOT EXISTS text_body text NOT NULL DEFAULT '';
+
+UPDATE email_outboxes SET body = COALESCE(message->>'HTML', ''), text_body = COALESCE(message->>'Text', '');
+
+ALTER TABLE email_outboxes DROP COLUMN IF EXISTS message;
@@ -0,0 +1,10 @@
+-- The outbox keeps the whole message so Cc, Bcc, Reply-To, headers and
+-- attachments survive until delivery
+ALTER TABLE email_outboxes ADD COLUMN IF NOT EXISTS message jsonb NOT NULL DEFAULT '{}';
+
+UPDATE email_outboxes
+SET message = jsonb_build_object('To', jsonb_build_array(to_email), 'Subject', subject, 'HTML', body, 'Text', text_body)
+WHERE status <> 'sent';
+
+ALTER TABLE email_outboxes DROP COLUMN IF EXISTS body;
+ALTER TABLE email_outboxes DROP COLUMN IF EXISTS text_body;
@@ -0,0 +1,4 @@
+-- One active account per email, soft deleted users keep theirs and can only
+-- be restored while the address is free. Resolve existing duplicates first or
+-- this migration fails.
+CREATE UNIQUE INDEX IF NOT EXISTS idx_users_email_active ON users (email) WHERE deleted_at IS NULL;
@@ -0,0 +1,9 @@
+// Package migrations holds the versioned schema. Add files with
+// `go run main.go --migrate-create <name>`; Go migrations can be registered
+// from an init function in this package with migrator.Register.
+package migrations
+
+import "embed"
+
+//go:embed *.sql
+var FS embed.FS
@@ -0,0 +1,145 @@
+package migrator
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io/fs"
+	"path"
+	"regexp"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type (
+	// Migration is one schema version. SQL migrations are loaded from
+	// <version>_<name>.up.sql / .down.sql files, Go migrations are added with Register.
+	Migration struct {
+		Version  int64
+		Name     string
+		Source   string // "sql" or "go"
+		Checksum string
+		Up       func(tx *gorm.DB) error
+		Down     func(tx *gorm.DB) error
+	}
+
+	// SchemaMigration is a row of the schema_migrations table.
+	SchemaMigration struct {
+		Version   int64     `gorm:"primaryKey;autoIncrement:false"`
+		Name      string    `gorm:"not null"`
+		Checksum  string    `gorm:"not null"`
+		AppliedAt time.Time `gorm:"type:timestamp with time zone;not null"`
+	}
+)
+
+func (SchemaMigration) TableName() string {
+	return "schema_migrations"
+}
+
+var (
+	mu           sync.Mutex
+	goMigrations []Migration
+
+	fileNamePattern = regexp.MustCompile(`^(\d+)_([a-z0-9_]+)\.(up|down)\.sql$`)
+)
+
+// Register adds a Go migration, usually from an init function next to the SQL
+// files. The checksum is derived from the version and name only, so edits to
+// the Go code are not detected.
+func Register(version int64, name string, up func(tx *gorm.DB) error, down func(tx *gorm.DB) error) {
+	mu.Lock()
+	defer mu.Unlock()
+
+	goMigrations = append(goMigrations, Migration{
+		Version:  version,
+		Name:     name,
+		Source:   "go",
+		Checksum: checksum([]byte(fmt.Sprintf("go:%d_%s", version, name))),
+		Up:       up,
+		Down:     down,
+	})
+}
+
+// Load collects SQL migrations from fsys and merges them with the registered
+// Go migrations, sorted by version.
+func Load(fsys fs.FS) ([]Migration, error) {
+	byVersion := map[int64]*Migration{}
+
+	entries, err := fs.ReadDir(fsys, ".")
+	if err != nil {
+		return nil, err
+	}
+
+	for _, entry := range entries {
+		if entry.IsDir() || path.Ext(entry.Name()) != ".sql" {
+			continue
+		}
+
+		match := fileNamePattern.FindStringSubmatch(entry.Name())
+		if match == nil {
+			return nil, fmt.Errorf("invalid migration file name %q, expected <version>_<name>.up.sql or .down.sql", entry.Name())
+		}
+
+		version, _ := strconv.ParseInt(match[1], 10, 64)
+		content, err := fs.ReadFile(fsys, entry.Name())
+		if err != nil {
+			return nil, err
+		}
+
+		m, ok := byVersion[version]
+		if !ok {
+			m = &Migration{Version: version, Name: match[2], Source: "sql"}
+			byVersion[version] = m
+		} else if m.Name != match[2] {
+			return nil, fmt.Errorf("migration %d has two names: %q and %q", version, m.Name, match[2])
+		}
+
+		switch match[3] {
+		case "up":
+			m.Checksum = checksum(content)
+			m.Up = execSQL(string(content))
+		case "down":
+			m.Down = execSQL(string(content))
+		}
+	}
+
+	mu.Lock()
+	for i := range goMigrations {
+		g := goMigrations[i]
+		if _, ok := byVersion[g.Version]; ok {
+			mu.Unlock()
+			return nil, fmt.Errorf("migration version %d is defined more than once", g.Version)
+		}
+		byVersion[g.Version] = &g
+	}
+	mu.Unlock()
+
+	migrations := make([]Migration, 0, len(byVersion))
+	for _, m := range byVersion {
+		if m.Up == nil {
+			return nil, fmt.Errorf("migration %d_%s has no up migration", m.Version, m.Name)
+		}
+		migrations = append(migrations, *m)
+	}
+
+	sort.Slice(migrations, func(i, j int) bool {
+		return migrations[i].Version < migrations[j].Version
+	})
+
+	return migrations, nil
+}
+
+func execSQL(query string) func(tx *gorm.DB) error {
+	return func(tx *gorm.DB) error {
+		return tx.Exec(query).Error
+	}
+}
+
+func checksum(content []byte) string {
+	sum := sha256.Sum256(content)
+	return hex.EncodeToString(sum[:])
+}
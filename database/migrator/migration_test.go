@@ -0,0 +1,211 @@
+package migrator
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	"gorm.io/gorm"
+)
+
+// withGoMigrations replaces the registered Go migrations for one test.
+func withGoMigrations(t *testing.T, migrations []Migration) {
+	t.Helper()
+
+	mu.Lock()
+	saved := goMigrations
+	goMigrations = migrations
+	mu.Unlock()
+
+	t.Cleanup(func() {
+		mu.Lock()
+		goMigrations = saved
+		mu.Unlock()
+	})
+}
+
+func sha256Hex(content string) string {
+	sum := sha256.Sum256([]byte(content))
+	return hex.EncodeToString(sum[:])
+}
+
+func TestLoad(t *testing.T) {
+	withGoMigrations(t, nil)
+
+	fsys := fstest.MapFS{
+		"000010_add_index.up.sql":       {Data: []byte("CREATE INDEX a ON b (c);")},
+		"000002_create_users.up.sql":    {Data: []byte("CREATE TABLE users ();")},
+		"000002_create_users.down.sql":  {Data: []byte("DROP TABLE users;")},
+		"000001_init.up.sql":            {Data: []byte("CREATE EXTENSION x;")},
+		"000001_init.down.sql":          {Data: []byte("DROP EXTENSION x;")},
+		"README.md":                     {Data: []byte("not a migration")},
+		"archive/000003_old.up.sql":     {Data: []byte("ignored, directories are not read")},
+		"000011_data_only_fix.up.sql":   {Data: []byte("UPDATE users SET a = b;")},
+		"000012_trailing_name_9.up.sql": {Data: []byte("SELECT 1;")},
+	}
+
+	migrations, err := Load(fsys)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+
+	want := []struct {
+		version int64
+		name    string
+		up      string
+		hasDown bool
+	}{
+		{version: 1, name: "init", up: "CREATE EXTENSION x;", hasDown: true},
+		{version: 2, name: "create_users", up: "CREATE TABLE users ();", hasDown: true},
+		{version: 10, name: "add_index", up: "CREATE INDEX a ON b (c);"},
+		{version: 11, name: "data_only_fix", up: "UPDATE users SET a = b;"},
+		{version: 12, name: "trailing_name_9", up: "SELECT 1;"},
+	}
+	if len(migrations) != len(want) {
+		t.Fatalf("Load() returned %d migrations, want %d", len(migrations), len(want))
+	}
+
+	for i, w := range want {
+		m := migrations[i]
+		if m.Version != w.version || m.Name != w.name {
+			t.Errorf("migration %d = %d_%s, want %d_%s", i, m.Version, m.Name, w.version, w.name)
+		}
+		if m.Source != "sql" {
+			t.Errorf("migration %d source = %q, want sql", m.Version, m.Source)
+		}
+		if m.Checksum != sha256Hex(w.up) {
+			t.Errorf("migration %d checksum = %s, want the SHA-256 of its up file", m.Version, m.Checksum)
+		}
+		if m.Up == nil {
+			t.Errorf("migration %d has no up function", m.Version)
+		}
+		if (m.Down != nil) != w.hasDown {
+			t.Errorf("migration %d has down = %v, want %v", m.Version, m.Down != nil, w.hasDown)
+		}
+	}
+}
+
+func TestLoadChecksumIgnoresDownFile(t *testing.T) {
+	withGoMigrations(t, nil)
+
+	withDown, err := Load(fstest.MapFS{
+		"000001_init.up.sql":   {Data: []byte("CREATE TABLE a ();")},
+		"000001_init.down.sql": {Data: []byte("DROP TABLE a;")},
+	})
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+
+	editedDown, err := Load(fstest.MapFS{
+		"000001_init.up.sql":   {Data: []byte("CREATE TABLE a ();")},
+		"000001_init.down.sql": {Data: []byte("DROP TABLE IF EXISTS a;")},
+	})
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+
+	editedUp, err := Load(fstest.MapFS{
+		"000001_init.up.sql": {Data: []byte("CREATE TABLE a (id int);")},
+	})
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+
+	if withDown[0].Checksum != editedDown[0].Checksum {
+		t.Error("editing the down file changed the checksum")
+	}
+	if withDown[0].Checksum == editedUp[0].Checksum {
+		t.Error("editing the up file kept the checksum")
+	}
+}
+
+func TestLoadErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		fsys    fstest.MapFS
+		wantErr string
+	}{
+		{
+			name:    "missing version",
+			fsys:    fstest.MapFS{"create_users.up.sql": {Data: []byte("SELECT 1;")}},
+			wantErr: "invalid migration file name",
+		},
+		{
+			name:    "missing direction",
+			fsys:    fstest.MapFS{"000001_create_users.sql": {Data: []byte("SELECT 1;")}},
+			wantErr: "invalid migration file name",
+		},
+		{
+			name:    "upper case name",
+			fsys:    fstest.MapFS{"000001_CreateUsers.up.sql": {Data: []byte("SELECT 1;")}},
+			wantErr: "invalid migration file name",
+		},
+		{
+			name: "two names for one version",
+			fsys: fstest.MapFS{
+				"000001_create_users.up.sql": {Data: []byte("SELECT 1;")},
+				"000001_create_roles.up.sql": {Data: []byte("SELECT 1;")},
+			},
+			wantErr: "has two names",
+		},
+		{
+			name:    "down without up",
+			fsys:    fstest.MapFS{"000001_create_users.down.sql": {Data: []byte("SELECT 1;")}},
+			wantErr: "has no up migration",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			withGoMigrations(t, nil)
+
+			_, err := Load(tt.fsys)
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("Load() error = %v, want one containing %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestLoadMergesGoMigrations(t *testing.T) {
+	withGoMigrations(t, nil)
+
+	noop := func(*gorm.DB) error { return nil }
+	Register(3, "backfill_roles", noop, nil)
+	Register(1, "seed_extension", noop, noop)
+
+	migrations, err := Load(fstest.MapFS{
+		"000002_create_users.up.sql": {Data: []byte("CREATE TABLE users ();")},
+	})
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+
+	var got []string
+	for _, m := range migrations {
+		got = append(got, m.Source+":"+m.Name)
+	}
+	if want := "go:seed_extension sql:create_users go:backfill_roles"; strings.Join(got, " ") != want {
+		t.Errorf("Load() order = %s, want %s", strings.Join(got, " "), want)
+	}
+
+	// the checksum of a Go migration only depends on its version and name
+	if migrations[0].Checksum != sha256Hex("go:1_seed_extension") {
+		t.Errorf("Go migration checksum = %s", migrations[0].Checksum)
+	}
+}
+
+func TestLoadRejectsDuplicateGoMigration(t *testing.T) {
+	withGoMigrations(t, nil)
+
+	Register(1, "init", func(*gorm.DB) error { return nil }, nil)
+
+	_, err := Load(fstest.MapFS{
+		"000001_init.up.sql": {Data: []byte("SELECT 1;")},
+	})
+	if err == nil || !strings.Contains(err.Error(), "defined more than once") {
+		t.Fatalf("Load() error = %v, want a duplicate version error", err)
+	}
+}
@@ -0,0 +1,96 @@
+package migrator
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+)
+
+func TestVerify(t *testing.T) {
+	m := &Migrator{migrations: []Migration{
+		{Version: 1, Name: "init", Checksum: "aaa"},
+		{Version: 2, Name: "create_users", Checksum: "bbb"},
+		{Version: 3, Name: "add_index", Checksum: "ccc"},
+	}}
+
+	tests := []struct {
+		name     string
+		done     map[int64]SchemaMigration
+		wantErr  error
+		modified string
+	}{
+		{name: "nothing applied", done: map[int64]SchemaMigration{}},
+		{
+			name: "applied unchanged",
+			done: map[int64]SchemaMigration{1: {Version: 1, Checksum: "aaa"}, 2: {Version: 2, Checksum: "bbb"}},
+		},
+		{
+			// a migration applied by a newer build is not this build's business
+			name: "applied but no longer defined",
+			done: map[int64]SchemaMigration{1: {Version: 1, Checksum: "aaa"}, 9: {Version: 9, Checksum: "zzz"}},
+		},
+		{
+			name:     "applied then edited",
+			done:     map[int64]SchemaMigration{1: {Version: 1, Checksum: "aaa"}, 2: {Version: 2, Checksum: "old"}},
+			wantErr:  ErrChecksumMismatch,
+			modified: "2_create_users",
+		},
+		{
+			name:     "several edited",
+			done:     map[int64]SchemaMigration{1: {Version: 1, Checksum: "old"}, 3: {Version: 3, Checksum: "old"}},
+			wantErr:  ErrChecksumMismatch,
+			modified: "1_init, 3_add_index",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := m.verify(tt.done)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("verify() error = %v, want %v", err, tt.wantErr)
+			}
+			if tt.modified != "" && !strings.HasSuffix(err.Error(), tt.modified) {
+				t.Errorf("verify() error = %q, want it to list %s", err, tt.modified)
+			}
+		})
+	}
+}
+
+func TestCreate(t *testing.T) {
+	withGoMigrations(t, nil)
+
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "000007_existing.up.sql"), []byte("SELECT 1;"), 0o644); err != nil {
+		t.Fatalf("write migration: %v", err)
+	}
+
+	paths, err := Create(dir, "Add Users' Locale!")
+	if err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+
+	var names []string
+	for _, path := range paths {
+		names = append(names, filepath.Base(path))
+	}
+	sort.Strings(names)
+	want := []string{"000008_add_users_locale.down.sql", "000008_add_users_locale.up.sql"}
+	if strings.Join(names, " ") != strings.Join(want, " ") {
+		t.Errorf("Create() wrote %v, want %v", names, want)
+	}
+
+	migrations, err := Load(os.DirFS(dir))
+	if err != nil {
+		t.Fatalf("Load() after Create() error = %v", err)
+	}
+	if last := migrations[len(migrations)-1]; last.Version != 8 || last.Down == nil {
+		t.Errorf("created migration = %d_%s with down %v, want 8 with down", last.Version, last.Name, last.Down != nil)
+	}
+
+	if _, err := Create(dir, "!!!"); err == nil {
+		t.Error("Create() accepted a name without letters or digits")
+	}
+}
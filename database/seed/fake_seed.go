@@ -0,0 +1,163 @@
+package seed
+
+import (
+	"context"
+	"fmt"
+	"hash/fnv"
+	"time"
+
+	"github.com/Shabrinashsf/go-gin-gorm-boilerplate/entity"
+	"github.com/Shabrinashsf/go-gin-gorm-boilerplate/helpers"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+func init() {
+	Register(Seeder{
+		Name:         "fake_users",
+		DependsOn:    []string{"roles"},
+		Environments: []string{ENV_DEMO},
+		Run:          FakeUserSeeder,
+	})
+	Register(Seeder{
+		Name:         "fake_transactions",
+		DependsOn:    []string{"fake_users"},
+		Environments: []string{ENV_DEMO},
+		Run:          FakeTransactionSeeder,
+	})
+}
+
+var (
+	FAKE_SEED             int64 = 20240101
+	FAKE_EMAIL_DOMAIN           = "example.test"
+	FAKE_PASSWORD               = "Password123"
+	FAKE_BATCH_SIZE             = 500
+	FAKE_MAX_TRANSACTIONS       = 5
+)
+
+// FakeUserSeeder creates opts.Count users. Row i is always generated from the
+// same seed, so reruns only add the rows that are still missing.
+func FakeUserSeeder(ctx context.Context, db *gorm.DB, opts Options) error {
+	var existing []string
+	if err := db.Model(&entity.User{}).
+		Where("email LIKE ?", "%@"+FAKE_EMAIL_DOMAIN).
+		Pluck("email", &existing).Error; err != nil {
+		return err
+	}
+
+	seen := make(map[string]struct{}, len(existing))
+	for _, email := range existing {
+		seen[email] = struct{}{}
+	}
+
+	// Hash once, bcrypt per row would dominate the run time
+	password, err := helpers.HashPassword(FAKE_PASSWORD)
+	if err != nil {
+		return err
+	}
+
+	users := make([]entity.User, 0, opts.Count)
+	for i := 1; i <= opts.Count; i++ {
+		f := NewFaker(FAKE_SEED + int64(i))
+		name := f.Name()
+		email := f.Email(name, i)
+		if _, ok := seen[email]; ok {
+			continue
+		}
+
+		createdAt := f.PastTime(365 * 24 * time.Hour)
+		users = append(users, entity.User{
+			Name:       name,
+			Email:      email,
+			Password:   password,
+			Instansi:   f.Instansi(),
+			NoTelp:     f.Phone(),
+			Role:       entity.RoleUser,
+			IsVerified: f.Bool(0.85),
+			Timestamp: entity.Timestamp{
+				CreatedAt: createdAt,
+				UpdatedAt: createdAt,
+			},
+		})
+	}
+
+	if len(users) == 0 {
+		return nil
+	}
+
+	// BeforeCreate would hash the already hashed password again
+	return db.Session(&gorm.Session{SkipHooks: true}).CreateInBatches(&users, FAKE_BATCH_SIZE).Error
+}
+
+// FakeTransactionSeeder gives every fake user up to FAKE_MAX_TRANSACTIONS
+// transactions, keyed by a deterministic FAKE- reference.
+func FakeTransactionSeeder(ctx context.Context, db *gorm.DB, opts Options) error {
+	var users []entity.User
+	if err := db.Select("id", "email", "created_at").
+		Where("email LIKE ?", "%@"+FAKE_EMAIL_DOMAIN).
+		Find(&users).Error; err != nil {
+		return err
+	}
+
+	var existing []string
+	if err := db.Model(&entity.Transaction{}).
+		Unscoped().
+		Where("reference LIKE ?", "FAKE-%").
+		Pluck("reference", &existing).Error; err != nil {
+		return err
+	}
+
+	seen := make(map[string]struct{}, len(existing))
+	for _, reference := range existing {
+		seen[reference] = struct{}{}
+	}
+
+	var transactions []entity.Transaction
+	for _, user := range users {
+		f := NewFaker(FAKE_SEED + emailSeed(user.Email))
+		count := f.IntBetween(0, FAKE_MAX_TRANSACTIONS)
+
+		for n := 1; n <= count; n++ {
+			reference := fmt.Sprintf("FAKE-%s-%02d", user.ID.String()[:8], n)
+			transactionType := f.TransactionType()
+			status := f.TransactionStatus()
+			amount := f.Amount()
+			productId := uuid.NewSHA1(uuid.NameSpaceOID, []byte(transactionType))
+			createdAt := user.CreatedAt.Add(time.Since(user.CreatedAt) / time.Duration(count+1) * time.Duration(n))
+
+			if _, ok := seen[reference]; ok {
+				continue
+			}
+
+			transaction := entity.Transaction{
+				UserID:     user.ID,
+				ProductID:  productId,
+				Type:       transactionType,
+				Status:     status,
+				InvoiceURL: "https://tripay.co.id/checkout/" + reference,
+				Reference:  reference,
+				Timestamp: entity.Timestamp{
+					CreatedAt: createdAt,
+					UpdatedAt: createdAt,
+				},
+			}
+			if status == "PAID" || status == "REFUND" {
+				transaction.AmountPaid = amount
+			}
+
+			transactions = append(transactions, transaction)
+		}
+	}
+
+	if len(transactions) == 0 {
+		return nil
+	}
+
+	return db.CreateInBatches(&transactions, FAKE_BATCH_SIZE).Error
+}
+
+func emailSeed(email string) int64 {
+	h := fnv.New64a()
+	h.Write([]byte(email))
+	return int64(h.Sum64() >> 1)
+}
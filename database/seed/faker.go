@@ -0,0 +1,85 @@
+package seed
+
+import (
+	"fmt"
+	"math/rand"
+	"strings"
+	"time"
+)
+
+var (
+	fakeFirstNames = []string{
+		"Agus", "Budi", "Citra", "Dewi", "Eka", "Fajar", "Gita", "Hendra", "Indah", "Joko",
+		"Kartika", "Lestari", "Made", "Nur", "Putri", "Rizky", "Sari", "Teguh", "Wahyu", "Yuni",
+	}
+	fakeLastNames = []string{
+		"Pratama", "Saputra", "Wijaya", "Hidayat", "Santoso", "Kusuma", "Nugroho", "Siregar",
+		"Lubis", "Wibowo", "Setiawan", "Halim", "Gunawan", "Rahmawati", "Permata", "Susanto",
+	}
+	fakeInstansi = []string{
+		"Institut Teknologi Sepuluh Nopember", "Universitas Indonesia", "Universitas Gadjah Mada",
+		"Institut Teknologi Bandung", "Universitas Airlangga", "Universitas Brawijaya",
+		"PT Maju Bersama", "CV Sinar Jaya", "PT Nusantara Digital", "Universitas Diponegoro",
+	}
+	fakeTransactionTypes    = []string{"REGISTRATION", "MERCHANDISE", "DONATION"}
+	fakeTransactionStatuses = []string{"UNPAID", "PAID", "PAID", "PAID", "FAILED", "EXPIRED", "REFUND"}
+	fakePhonePrefixes       = []string{"0811", "0812", "0813", "0821", "0852", "0857", "0878", "0896"}
+)
+
+// Faker produces deterministic, realistic looking data. The same seed always
+// yields the same rows, which keeps fake seeders idempotent across runs.
+type Faker struct {
+	rand *rand.Rand
+}
+
+func NewFaker(seed int64) *Faker {
+	return &Faker{rand: rand.New(rand.NewSource(seed))}
+}
+
+func (f *Faker) pick(values []string) string {
+	return values[f.rand.Intn(len(values))]
+}
+
+func (f *Faker) Name() string {
+	return f.pick(fakeFirstNames) + " " + f.pick(fakeLastNames)
+}
+
+// Email is unique per index so reruns find the rows they created before.
+func (f *Faker) Email(name string, index int) string {
+	local := strings.ToLower(strings.ReplaceAll(name, " ", "."))
+	return fmt.Sprintf("%s.%05d@%s", local, index, FAKE_EMAIL_DOMAIN)
+}
+
+func (f *Faker) Phone() string {
+	return fmt.Sprintf("%s%08d", f.pick(fakePhonePrefixes), f.rand.Intn(100_000_000))
+}
+
+func (f *Faker) Instansi() string {
+	return f.pick(fakeInstansi)
+}
+
+func (f *Faker) Bool(probability float64) bool {
+	return f.rand.Float64() < probability
+}
+
+func (f *Faker) IntBetween(min int, max int) int {
+	return min + f.rand.Intn(max-min+1)
+}
+
+// Amount returns a price in rupiah rounded to a thousand.
+func (f *Faker) Amount() int {
+	return f.IntBetween(10, 2_000) * 1_000
+}
+
+func (f *Faker) TransactionType() string {
+	return f.pick(fakeTransactionTypes)
+}
+
+func (f *Faker) TransactionStatus() string {
+	return f.pick(fakeTransactionStatuses)
+}
+
+// PastTime returns a moment within the last maxAge.
+func (f *Faker) PastTime(maxAge time.Duration) time.Time {
+	return time.Now().Add(-time.Duration(f.rand.Int63n(int64(maxAge))))
+}
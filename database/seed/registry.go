@@ -0,0 +1,149 @@
+package seed
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"gorm.io/gorm"
+)
+
+const (
+	ENV_DEV  = "dev"
+	ENV_DEMO = "demo"
+	ENV_TEST = "test"
+)
+
+type (
+	// Options are shared by every seeder of a run.
+	Options struct {
+		Environment string
+		Count       int // rows produced by fake-data seeders
+	}
+
+	// Seeder fills one part of the database. Run must be idempotent so a seed
+	// set can be applied repeatedly. Environments limits when the seeder is part
+	// of the default set; it always runs when requested by name or as a dependency.
+	Seeder struct {
+		Name         string
+		DependsOn    []string
+		Environments []string
+		Run          func(ctx context.Context, db *gorm.DB, opts Options) error
+	}
+)
+
+var (
+	ENVIRONMENTS       = []string{ENV_DEV, ENV_DEMO, ENV_TEST}
+	DEFAULT_FAKE_COUNT = 1000
+
+	registry = map[string]Seeder{}
+)
+
+// Register adds a seeder to the registry, usually from an init function.
+func Register(s Seeder) {
+	if _, ok := registry[s.Name]; ok {
+		panic(fmt.Sprintf("seeder %q registered twice", s.Name))
+	}
+	registry[s.Name] = s
+}
+
+// Names lists every registered seeder.
+func Names() []string {
+	names := make([]string, 0, len(registry))
+	for name := range registry {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Plan resolves which seeders run and in what order. Without names every
+// seeder of opts.Environment is selected. Dependencies always come first.
+func Plan(opts Options, names ...string) ([]Seeder, error) {
+	if !contains(ENVIRONMENTS, opts.Environment) {
+		return nil, fmt.Errorf("unknown seed environment %q, expected one of %s", opts.Environment, strings.Join(ENVIRONMENTS, ", "))
+	}
+
+	if len(names) == 0 {
+		for _, name := range Names() {
+			s := registry[name]
+			if len(s.Environments) == 0 || contains(s.Environments, opts.Environment) {
+				names = append(names, name)
+			}
+		}
+	}
+
+	var (
+		ordered []Seeder
+		state   = map[string]int{} // 1 visiting, 2 done
+		visit   func(name string, path []string) error
+	)
+
+	visit = func(name string, path []string) error {
+		s, ok := registry[name]
+		if !ok {
+			if len(path) > 0 {
+				return fmt.Errorf("seeder %q depends on unknown seeder %q", path[len(path)-1], name)
+			}
+			return fmt.Errorf("unknown seeder %q, available: %s", name, strings.Join(Names(), ", "))
+		}
+
+		switch state[name] {
+		case 1:
+			return fmt.Errorf("seeder dependency cycle: %s -> %s", strings.Join(path, " -> "), name)
+		case 2:
+			return nil
+		}
+
+		state[name] = 1
+		for _, dep := range s.DependsOn {
+			if err := visit(dep, append(path, name)); err != nil {
+				return err
+			}
+		}
+		state[name] = 2
+
+		ordered = append(ordered, s)
+		return nil
+	}
+
+	for _, name := range names {
+		if err := visit(name, nil); err != nil {
+			return nil, err
+		}
+	}
+
+	return ordered, nil
+}
+
+// Run executes the planned seeders and returns the names that completed.
+func Run(ctx context.Context, db *gorm.DB, opts Options, names ...string) ([]string, error) {
+	if opts.Count <= 0 {
+		opts.Count = DEFAULT_FAKE_COUNT
+	}
+
+	seeders, err := Plan(opts, names...)
+	if err != nil {
+		return nil, err
+	}
+
+	var done []string
+	for _, s := range seeders {
+		if err := s.Run(ctx, db.WithContext(ctx), opts); err != nil {
+			return done, fmt.Errorf("seeder %s: %w", s.Name, err)
+		}
+		done = append(done, s.Name)
+	}
+
+	return done, nil
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
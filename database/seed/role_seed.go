@@ -0,0 +1,65 @@
+package seed
+
+import (
+	"context"
+
+	"github.com/Shabrinashsf/go-gin-gorm-boilerplate/entity"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+func init() {
+	Register(Seeder{
+		Name: "roles",
+		Run:  ListRoleSeeder,
+	})
+}
+
+var (
+	defaultPermissions = []entity.Permission{
+		{Name: entity.PermissionAll, Description: "Full access to every resource"},
+		{Name: "roles:manage", Description: "Create, update and delete roles and permissions"},
+		{Name: "roles:assign", Description: "Assign roles to users"},
+		{Name: "users:read", Description: "Read user accounts"},
+		{Name: "audit:read", Description: "Read the security audit log"},
+		{Name: "emails:manage", Description: "Inspect the email outbox and resend failed email"},
+		{Name: "transactions:read", Description: "Read transactions"},
+		{Name: "transactions:refund", Description: "Refund transactions"},
+	}
+
+	// defaultRoles mirror entity.UserRole so existing accounts keep working
+	defaultRoles = map[string][]string{
+		string(entity.RoleAdmin): {entity.PermissionAll},
+		string(entity.RoleUser):  {},
+	}
+)
+
+func ListRoleSeeder(ctx context.Context, db *gorm.DB, opts Options) error {
+	for _, data := range defaultPermissions {
+		if err := db.Clauses(clause.OnConflict{DoNothing: true}).Create(&data).Error; err != nil {
+			return err
+		}
+	}
+
+	for name, permissionNames := range defaultRoles {
+		var role entity.Role
+		if err := db.Where(entity.Role{Name: name}).FirstOrCreate(&role).Error; err != nil {
+			return err
+		}
+
+		if len(permissionNames) == 0 {
+			continue
+		}
+
+		var permissions []entity.Permission
+		if err := db.Where("name IN ?", permissionNames).Find(&permissions).Error; err != nil {
+			return err
+		}
+
+		if err := db.Model(&role).Association("Permissions").Append(&permissions); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
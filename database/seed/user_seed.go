@@ -1,47 +1,61 @@
 package seed
 
 import (
+	"context"
 	"encoding/json"
-	"errors"
-	"io"
 	"os"
 
 	"github.com/Shabrinashsf/go-gin-gorm-boilerplate/entity"
 	"gorm.io/gorm"
 )
 
-func ListUserSeeder(db *gorm.DB) error {
-	jsonFile, err := os.Open("./database/json/users.json")
+func init() {
+	Register(Seeder{
+		Name:         "users",
+		DependsOn:    []string{"roles"},
+		Environments: []string{ENV_DEV, ENV_DEMO, ENV_TEST},
+		Run:          ListUserSeeder,
+	})
+}
+
+var USERS_SEED_FILE = "./database/json/users.json"
+
+// ListUserSeeder upserts the accounts from USERS_SEED_FILE by email. Passwords
+// of existing accounts are left untouched.
+func ListUserSeeder(ctx context.Context, db *gorm.DB, opts Options) error {
+	jsonData, err := os.ReadFile(USERS_SEED_FILE)
 	if err != nil {
 		return err
 	}
 
-	jsonData, _ := io.ReadAll(jsonFile)
-
 	var listUser []entity.User
 	if err := json.Unmarshal(jsonData, &listUser); err != nil {
 		return err
 	}
 
-	hasTable := db.Migrator().HasTable(&entity.User{})
-	if !hasTable {
-		if err := db.Migrator().CreateTable(&entity.User{}); err != nil {
-			return err
-		}
-	}
-
 	for _, data := range listUser {
 		var user entity.User
-		err := db.Where(&entity.User{Email: data.Email}).First(&user).Error
-		if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
-			return err
+		result := db.Where("email = ?", data.Email).Limit(1).Find(&user)
+		if result.Error != nil {
+			return result.Error
 		}
 
-		isData := db.Find(&user, "email = ?", data.Email).RowsAffected
-		if isData == 0 {
+		if result.RowsAffected == 0 {
 			if err := db.Create(&data).Error; err != nil {
 				return err
 			}
+			continue
+		}
+
+		updates := map[string]interface{}{}
+		updates["name"] = data.Name
+		updates["instansi"] = data.Instansi
+		updates["no_telp"] = data.NoTelp
+		updates["role"] = data.Role
+		updates["is_verified"] = data.IsVerified
+
+		if err := db.Model(&entity.User{}).Where("id = ?", user.ID).Updates(updates).Error; err != nil {
+			return err
 		}
 	}
 
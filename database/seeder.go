@@ -1,14 +1,14 @@
 package database
 
 import (
+	"context"
+
 	"github.com/Shabrinashsf/go-gin-gorm-boilerplate/database/seed"
 	"gorm.io/gorm"
 )
 
-func Seeder(db *gorm.DB) error {
-	if err := seed.ListUserSeeder(db); err != nil {
-		return err
-	}
-
-	return nil
+// Seeder runs the named seeders, or every seeder of opts.Environment when no
+// name is given, and returns the seeders that completed in order.
+func Seeder(db *gorm.DB, opts seed.Options, names ...string) ([]string, error) {
+	return seed.Run(context.Background(), db, opts, names...)
 }
@@ -0,0 +1,53 @@
+package database
+
+import (
+	"database/sql"
+	"fmt"
+
+	"gorm.io/gorm"
+)
+
+// PoolStats is the JSON form of sql.DBStats for one connection pool.
+type PoolStats struct {
+	Name               string `json:"name"`
+	MaxOpenConnections int    `json:"max_open_connections"`
+	OpenConnections    int    `json:"open_connections"`
+	InUse              int    `json:"in_use"`
+	Idle               int    `json:"idle"`
+	WaitCount          int64  `json:"wait_count"`
+	WaitDuration       string `json:"wait_duration"`
+	MaxIdleClosed      int64  `json:"max_idle_closed"`
+	MaxIdleTimeClosed  int64  `json:"max_idle_time_closed"`
+	MaxLifetimeClosed  int64  `json:"max_lifetime_closed"`
+}
+
+// Stats reports the primary pool followed by every read replica pool.
+func Stats(db *gorm.DB) ([]PoolStats, error) {
+	dbSQL, err := db.DB()
+	if err != nil {
+		return nil, err
+	}
+
+	stats := []PoolStats{toPoolStats("primary", dbSQL.Stats())}
+
+	for i, replica := range replicaPools(db, dbSQL) {
+		stats = append(stats, toPoolStats(fmt.Sprintf("replica-%d", i+1), replica.Stats()))
+	}
+
+	return stats, nil
+}
+
+func toPoolStats(name string, s sql.DBStats) PoolStats {
+	return PoolStats{
+		Name:               name,
+		MaxOpenConnections: s.MaxOpenConnections,
+		OpenConnections:    s.OpenConnections,
+		InUse:              s.InUse,
+		Idle:               s.Idle,
+		WaitCount:          s.WaitCount,
+		WaitDuration:       s.WaitDuration.String(),
+		MaxIdleClosed:      s.MaxIdleClosed,
+		MaxIdleTimeClosed:  s.MaxIdleTimeClosed,
+		MaxLifetimeClosed:  s.MaxLifetimeClosed,
+	}
+}
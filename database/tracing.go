@@ -0,0 +1,93 @@
+package database
+
+import (
+	"errors"
+
+	"github.com/Shabrinashsf/go-gin-gorm-boilerplate/utils/tracing"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+	"gorm.io/gorm"
+)
+
+const TRACING_SPAN_KEY = "tracing:span"
+
+// RegisterTracing opens a span for every GORM operation as a child of the
+// span carried by the statement context. Create, update and delete spans
+// include the model hooks, so time spent hashing passwords in BeforeCreate
+// shows up under the query that triggered it.
+func RegisterTracing(db *gorm.DB) error {
+	callbacks := db.Callback()
+
+	if err := callbacks.Create().Before("gorm:begin_transaction").Register("tracing:before_create", startSpan("gorm.create")); err != nil {
+		return err
+	}
+	if err := callbacks.Create().After("gorm:commit_or_rollback_transaction").Register("tracing:after_create", endSpan); err != nil {
+		return err
+	}
+	if err := callbacks.Query().Before("gorm:query").Register("tracing:before_query", startSpan("gorm.query")); err != nil {
+		return err
+	}
+	if err := callbacks.Query().After("gorm:after_query").Register("tracing:after_query", endSpan); err != nil {
+		return err
+	}
+	if err := callbacks.Update().Before("gorm:begin_transaction").Register("tracing:before_update", startSpan("gorm.update")); err != nil {
+		return err
+	}
+	if err := callbacks.Update().After("gorm:commit_or_rollback_transaction").Register("tracing:after_update", endSpan); err != nil {
+		return err
+	}
+	if err := callbacks.Delete().Before("gorm:begin_transaction").Register("tracing:before_delete", startSpan("gorm.delete")); err != nil {
+		return err
+	}
+	if err := callbacks.Delete().After("gorm:commit_or_rollback_transaction").Register("tracing:after_delete", endSpan); err != nil {
+		return err
+	}
+	if err := callbacks.Row().Before("gorm:row").Register("tracing:before_row", startSpan("gorm.row")); err != nil {
+		return err
+	}
+	if err := callbacks.Row().After("gorm:row").Register("tracing:after_row", endSpan); err != nil {
+		return err
+	}
+	if err := callbacks.Raw().Before("gorm:raw").Register("tracing:before_raw", startSpan("gorm.raw")); err != nil {
+		return err
+	}
+	return callbacks.Raw().After("gorm:raw").Register("tracing:after_raw", endSpan)
+}
+
+func startSpan(name string) func(db *gorm.DB) {
+	return func(db *gorm.DB) {
+		if db.Statement.Context == nil {
+			return
+		}
+
+		ctx, span := tracing.Start(db.Statement.Context, name,
+			attribute.String("db.system", "postgresql"),
+		)
+		db.Statement.Context = ctx
+		db.InstanceSet(TRACING_SPAN_KEY, span)
+	}
+}
+
+func endSpan(db *gorm.DB) {
+	value, ok := db.InstanceGet(TRACING_SPAN_KEY)
+	if !ok {
+		return
+	}
+	span, ok := value.(trace.Span)
+	if !ok {
+		return
+	}
+
+	// the SQL keeps its placeholders, bound values are never recorded
+	span.SetAttributes(
+		attribute.String("db.sql.table", db.Statement.Table),
+		attribute.String("db.statement", db.Statement.SQL.String()),
+		attribute.Int64("db.rows_affected", db.Statement.RowsAffected),
+	)
+
+	err := db.Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		err = nil
+	}
+	tracing.End(span, err)
+}
@@ -0,0 +1,72 @@
+package dto
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/Shabrinashsf/go-gin-gorm-boilerplate/utils/pagination"
+)
+
+const (
+	// Failed
+	MESSAGE_FAILED_GET_LIST_USER  = "message.failed_get_list_user"
+	MESSAGE_FAILED_CHANGE_ROLE    = "message.failed_change_role"
+	MESSAGE_FAILED_SUSPEND_USER   = "message.failed_suspend_user"
+	MESSAGE_FAILED_UNSUSPEND_USER = "message.failed_unsuspend_user"
+	MESSAGE_FAILED_DELETE_USER    = "message.failed_delete_user"
+	MESSAGE_FAILED_RESTORE_USER   = "message.failed_restore_user"
+
+	// Success
+	MESSAGE_SUCCESS_GET_LIST_USER  = "message.success_get_list_user"
+	MESSAGE_SUCCESS_CHANGE_ROLE    = "message.success_change_role"
+	MESSAGE_SUCCESS_SUSPEND_USER   = "message.success_suspend_user"
+	MESSAGE_SUCCESS_UNSUSPEND_USER = "message.success_unsuspend_user"
+	MESSAGE_SUCCESS_DELETE_USER    = "message.success_delete_user"
+	MESSAGE_SUCCESS_RESTORE_USER   = "message.success_restore_user"
+)
+
+var (
+	ErrInvalidRole         = NewAppError(http.StatusBadRequest, "INVALID_ROLE", "role tidak valid")
+	ErrCannotModifySelf    = NewAppError(http.StatusForbidden, "CANNOT_MODIFY_SELF", "tidak dapat mengubah akun sendiri")
+	ErrUserNotDeleted      = NewAppError(http.StatusConflict, "USER_NOT_DELETED", "user tidak dalam keadaan terhapus")
+	ErrAccountSuspended    = NewAppError(http.StatusForbidden, "ACCOUNT_SUSPENDED", "akun sedang ditangguhkan")
+	ErrAccountNotSuspended = NewAppError(http.StatusConflict, "ACCOUNT_NOT_SUSPENDED", "akun tidak sedang ditangguhkan")
+	ErrInvalidUserID       = NewAppError(http.StatusBadRequest, "INVALID_USER_ID", "id user tidak valid")
+)
+
+type (
+	// AdminUserFilter narrows the admin user list on top of the paging and
+	// sorting parameters handled by pagination.Meta.
+	AdminUserFilter struct {
+		Search     string `form:"search"`
+		Role       string `form:"role"`
+		IsVerified *bool  `form:"is_verified"`
+		Suspended  *bool  `form:"is_suspended"`
+		Instansi   string `form:"instansi"`
+		Deleted    bool   `form:"deleted"`
+	}
+
+	AdminUserResponse struct {
+		ID               string     `json:"id"`
+		Name             string     `json:"name"`
+		Email            string     `json:"email"`
+		Instansi         string     `json:"instansi"`
+		NoTelp           string     `json:"no_telp"`
+		Role             string     `json:"role"`
+		IsVerified       bool       `json:"is_verified"`
+		IsSuspended      bool       `json:"is_suspended"`
+		TwoFactorEnabled bool       `json:"two_factor_enabled"`
+		CreatedAt        time.Time  `json:"created_at"`
+		UpdatedAt        time.Time  `json:"updated_at"`
+		DeletedAt        *time.Time `json:"deleted_at,omitempty"`
+	}
+
+	AdminUserPaginationResponse struct {
+		Data []AdminUserResponse `json:"data"`
+		Meta pagination.Meta     `json:"meta"`
+	}
+
+	ChangeRoleRequest struct {
+		Role string `json:"role" form:"role" binding:"required"`
+	}
+)
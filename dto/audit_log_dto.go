@@ -0,0 +1,54 @@
+package dto
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/Shabrinashsf/go-gin-gorm-boilerplate/utils/pagination"
+)
+
+const (
+	// Failed
+	MESSAGE_FAILED_GET_LIST_AUDIT_LOG = "message.failed_get_list_audit_log"
+
+	// Success
+	MESSAGE_SUCCESS_GET_LIST_AUDIT_LOG = "message.success_get_list_audit_log"
+)
+
+type (
+	// AuditLogFilter narrows the audit log list. Action accepts a trailing
+	// "*" to match a whole group, e.g. "auth.*". From and To are inclusive dates.
+	AuditLogFilter struct {
+		ActorID    string    `form:"actor_id" binding:"omitempty,uuid"`
+		Action     string    `form:"action"`
+		TargetType string    `form:"target_type"`
+		TargetID   string    `form:"target_id"`
+		IPAddress  string    `form:"ip_address"`
+		Success    *bool     `form:"success"`
+		From       time.Time `form:"from" time_format:"2006-01-02"`
+		To         time.Time `form:"to" time_format:"2006-01-02"`
+	}
+
+	AuditChange struct {
+		From any `json:"from"`
+		To   any `json:"to"`
+	}
+
+	AuditLogResponse struct {
+		ID         string          `json:"id"`
+		ActorID    *string         `json:"actor_id"`
+		Action     string          `json:"action"`
+		TargetType string          `json:"target_type"`
+		TargetID   string          `json:"target_id"`
+		Success    bool            `json:"success"`
+		IPAddress  string          `json:"ip_address"`
+		UserAgent  string          `json:"user_agent"`
+		Changes    json.RawMessage `json:"changes,omitempty"`
+		CreatedAt  time.Time       `json:"created_at"`
+	}
+
+	AuditLogPaginationResponse struct {
+		Data []AuditLogResponse `json:"data"`
+		Meta pagination.Meta    `json:"meta"`
+	}
+)
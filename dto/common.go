@@ -1,24 +1,29 @@
 package dto
 
-import "errors"
+import "net/http"
 
 const (
 	// Failed Messages
-	MESSAGE_FAILED_PROSES_REQUEST      = "failed to process request"
-	MESSAGE_FAILED_TOKEN_NOT_FOUND     = "token not found"
-	MESSAGE_FAILED_TOKEN_NOT_VALID     = "token not valid"
-	MESSAGE_FAILED_DENIED_ACCESS       = "denied access"
-	MESSAGE_FAILED_PARSE_TIME          = "failed to parse time"
-	MESSAGE_FAILED_GET_DATA_FROM_BODY  = "failed to get data from body"
-	MESSAGE_FAILED_GET_CALLBACK_TRIPAY = "failed to get callback from tripay"
+	MESSAGE_FAILED_PROSES_REQUEST      = "message.failed_proses_request"
+	MESSAGE_FAILED_TOKEN_NOT_FOUND     = "message.failed_token_not_found"
+	MESSAGE_FAILED_TOKEN_NOT_VALID     = "message.failed_token_not_valid"
+	MESSAGE_FAILED_DENIED_ACCESS       = "message.failed_denied_access"
+	MESSAGE_FAILED_PARSE_TIME          = "message.failed_parse_time"
+	MESSAGE_FAILED_GET_DATA_FROM_BODY  = "message.failed_get_data_from_body"
+	MESSAGE_FAILED_GET_CALLBACK_TRIPAY = "message.failed_get_callback_tripay"
 
 	// Success Messages
-	MESSAGE_SUCCESS_GET_CALLBACK_TRIPAY = "success get callback from tripay"
+	MESSAGE_SUCCESS_GET_CALLBACK_TRIPAY = "message.success_get_callback_tripay"
 
 	// General Messages
-	PESAN_DILUAR_MASA_REGISTRASI = "request made outside of allowed time frame"
+	PESAN_DILUAR_MASA_REGISTRASI = "message.outside_time_frame"
 )
 
 var (
-	ErrRoleNotAllowed = errors.New("role not allowed")
+	ErrTokenNotFound    = NewAppError(http.StatusUnauthorized, "TOKEN_NOT_FOUND", "token not found")
+	ErrTokenNotValid    = NewAppError(http.StatusUnauthorized, "TOKEN_NOT_VALID", "token not valid")
+	ErrDeniedAccess     = NewAppError(http.StatusUnauthorized, "DENIED_ACCESS", "denied access")
+	ErrTokenRequired    = NewAppError(http.StatusBadRequest, "TOKEN_REQUIRED", "token not found")
+	ErrOutsideTimeFrame = NewAppError(http.StatusBadRequest, "OUTSIDE_TIME_FRAME", "request made outside of allowed time frame")
+	ErrRoleNotAllowed   = NewAppError(http.StatusForbidden, "ROLE_NOT_ALLOWED", "role not allowed")
 )
@@ -0,0 +1,44 @@
+package dto
+
+import (
+	"net/http"
+	"time"
+)
+
+const (
+	// Failed
+	MESSAGE_FAILED_GET_MAILBOX = "message.failed_get_mailbox"
+
+	// Success
+	MESSAGE_SUCCESS_GET_MAILBOX   = "message.success_get_mailbox"
+	MESSAGE_SUCCESS_CLEAR_MAILBOX = "message.success_clear_mailbox"
+)
+
+var (
+	ErrMailboxMessageNotFound = NewAppError(http.StatusNotFound, "MAILBOX_MESSAGE_NOT_FOUND", "pesan tidak ditemukan di mailbox")
+)
+
+type (
+	MailboxMessageResponse struct {
+		ID          string                      `json:"id"`
+		From        string                      `json:"from"`
+		To          []string                    `json:"to"`
+		Cc          []string                    `json:"cc,omitempty"`
+		Bcc         []string                    `json:"bcc,omitempty"`
+		ReplyTo     string                      `json:"reply_to,omitempty"`
+		Subject     string                      `json:"subject"`
+		Date        time.Time                   `json:"date"`
+		PreviewURL  string                      `json:"preview_url"`
+		Headers     map[string][]string         `json:"headers,omitempty"`
+		Attachments []MailboxAttachmentResponse `json:"attachments,omitempty"`
+		HTML        string                      `json:"html,omitempty"`
+		Text        string                      `json:"text,omitempty"`
+	}
+
+	MailboxAttachmentResponse struct {
+		Name        string `json:"name"`
+		ContentType string `json:"content_type"`
+		Size        int    `json:"size"`
+		Inline      bool   `json:"inline"`
+	}
+)
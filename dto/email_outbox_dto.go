@@ -0,0 +1,61 @@
+package dto
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/Shabrinashsf/go-gin-gorm-boilerplate/utils/pagination"
+)
+
+const (
+	// Failed
+	MESSAGE_FAILED_GET_LIST_EMAIL = "message.failed_get_list_email"
+	MESSAGE_FAILED_GET_EMAIL      = "message.failed_get_email"
+	MESSAGE_FAILED_RESEND_EMAIL   = "message.failed_resend_email"
+
+	// Success
+	MESSAGE_SUCCESS_GET_LIST_EMAIL = "message.success_get_list_email"
+	MESSAGE_SUCCESS_GET_EMAIL      = "message.success_get_email"
+	MESSAGE_SUCCESS_RESEND_EMAIL   = "message.success_resend_email"
+)
+
+var (
+	ErrInvalidEmailID      = NewAppError(http.StatusBadRequest, "INVALID_EMAIL_ID", "id email tidak valid")
+	ErrEmailNotFailed      = NewAppError(http.StatusConflict, "EMAIL_NOT_FAILED", "hanya email yang gagal terkirim yang dapat dikirim ulang")
+	ErrEnqueueMail         = NewAppError(http.StatusInternalServerError, "EMAIL_ENQUEUE_FAILED", "gagal menjadwalkan pengiriman email")
+	ErrOutboxEmailNotFound = NewAppError(http.StatusNotFound, "OUTBOX_EMAIL_NOT_FOUND", "email tidak ditemukan di outbox")
+)
+
+type (
+	// EmailOutboxFilter narrows the outbox list, Status is pending, sent or dead.
+	EmailOutboxFilter struct {
+		Status  string `form:"status" binding:"omitempty,oneof=pending sent dead"`
+		ToEmail string `form:"to_email"`
+	}
+
+	// EmailOutboxResponse leaves out the message, it can carry one-time links.
+	EmailOutboxResponse struct {
+		ID            string     `json:"id"`
+		ToEmail       string     `json:"to_email"`
+		Subject       string     `json:"subject"`
+		Status        string     `json:"status"`
+		Attempts      int        `json:"attempts"`
+		NextAttemptAt time.Time  `json:"next_attempt_at"`
+		LastError     string     `json:"last_error,omitempty"`
+		SentAt        *time.Time `json:"sent_at"`
+		CreatedAt     time.Time  `json:"created_at"`
+		UpdatedAt     time.Time  `json:"updated_at"`
+	}
+
+	// EmailOutboxStats is the backlog reported by the outbox readiness check.
+	EmailOutboxStats struct {
+		Pending       int64 `json:"pending"`
+		Dead          int64 `json:"dead"`
+		WorkerRunning bool  `json:"worker_running"`
+	}
+
+	EmailOutboxPaginationResponse struct {
+		Data []EmailOutboxResponse `json:"data"`
+		Meta pagination.Meta       `json:"meta"`
+	}
+)
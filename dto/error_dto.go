@@ -0,0 +1,97 @@
+package dto
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+
+	"gorm.io/gorm"
+)
+
+// AppError is an error the API knows how to present: the HTTP status, a
+// stable code clients can switch on and a message that is safe to show.
+// The wrapped cause is logged by middleware.ErrorHandler, never sent.
+type AppError struct {
+	Status  int
+	Code    string
+	Message string
+	Err     error
+}
+
+func NewAppError(status int, code string, message string) *AppError {
+	return &AppError{
+		Status:  status,
+		Code:    code,
+		Message: message,
+	}
+}
+
+func (e *AppError) Error() string {
+	return e.Message
+}
+
+func (e *AppError) Unwrap() error {
+	return e.Err
+}
+
+// Is matches on the code, so a wrapped copy of a sentinel still satisfies
+// errors.Is(err, dto.ErrUserNotFound).
+func (e *AppError) Is(target error) bool {
+	t, ok := target.(*AppError)
+	return ok && t.Code == e.Code
+}
+
+// MessageID is the key of the message in the i18n catalog, Message is the
+// text used when the catalog has no entry and in logs.
+func (e *AppError) MessageID() string {
+	return "error." + strings.ToLower(e.Code)
+}
+
+// Wrap returns a copy of the sentinel with err attached as the cause.
+func (e *AppError) Wrap(err error) *AppError {
+	wrapped := *e
+	wrapped.Err = err
+	return &wrapped
+}
+
+// FieldError is one failed rule on a request field. It is returned in the
+// error field of VALIDATION_FAILED responses, Field uses the JSON name.
+type FieldError struct {
+	Field   string `json:"field"`
+	Rule    string `json:"rule"`
+	Message string `json:"message"`
+}
+
+var (
+	ErrInternal      = NewAppError(http.StatusInternalServerError, "INTERNAL_ERROR", "terjadi kesalahan pada server")
+	ErrNotFound      = NewAppError(http.StatusNotFound, "NOT_FOUND", "data tidak ditemukan")
+	ErrTimeout       = NewAppError(http.StatusGatewayTimeout, "TIMEOUT", "permintaan melebihi batas waktu")
+	ErrValidation    = NewAppError(http.StatusBadRequest, "VALIDATION_FAILED", "data pada request tidak valid")
+	ErrRouteNotFound = NewAppError(http.StatusNotFound, "ROUTE_NOT_FOUND", "route not found")
+)
+
+// NewValidationError reports a request body or query that failed to bind.
+// middleware.ErrorHandler renders the cause as translated field errors.
+func NewValidationError(err error) *AppError {
+	return ErrValidation.Wrap(err)
+}
+
+// ToAppError resolves the AppError to render for err. Record-not-found and
+// deadline errors get their own status, anything else is an internal error
+// whose details stay in the logs.
+func ToAppError(err error) *AppError {
+	var appErr *AppError
+	if errors.As(err, &appErr) {
+		return appErr
+	}
+
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		return ErrNotFound.Wrap(err)
+	case errors.Is(err, context.DeadlineExceeded):
+		return ErrTimeout.Wrap(err)
+	default:
+		return ErrInternal.Wrap(err)
+	}
+}
@@ -0,0 +1,33 @@
+package dto
+
+const (
+	HEALTH_STATUS_OK        = "ok"
+	HEALTH_STATUS_UP        = "up"
+	HEALTH_STATUS_DOWN      = "down"
+	HEALTH_STATUS_READY     = "ready"
+	HEALTH_STATUS_NOT_READY = "not_ready"
+	HEALTH_STATUS_DRAINING  = "draining"
+)
+
+type (
+	LivenessResponse struct {
+		Status string `json:"status"`
+		Uptime string `json:"uptime"`
+	}
+
+	// HealthComponent is the result of one readiness check. Optional
+	// components are reported but never make the instance not ready.
+	HealthComponent struct {
+		Name      string  `json:"name"`
+		Status    string  `json:"status"`
+		Required  bool    `json:"required"`
+		LatencyMS float64 `json:"latency_ms"`
+		Error     string  `json:"error,omitempty"`
+		Details   any     `json:"details,omitempty"`
+	}
+
+	ReadinessResponse struct {
+		Status     string            `json:"status"`
+		Components []HealthComponent `json:"components"`
+	}
+)
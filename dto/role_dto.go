@@ -0,0 +1,77 @@
+package dto
+
+import "net/http"
+
+const (
+	// Failed
+	MESSAGE_FAILED_GET_LIST_ROLE       = "message.failed_get_list_role"
+	MESSAGE_FAILED_CREATE_ROLE         = "message.failed_create_role"
+	MESSAGE_FAILED_UPDATE_ROLE         = "message.failed_update_role"
+	MESSAGE_FAILED_DELETE_ROLE         = "message.failed_delete_role"
+	MESSAGE_FAILED_GET_LIST_PERMISSION = "message.failed_get_list_permission"
+	MESSAGE_FAILED_CREATE_PERMISSION   = "message.failed_create_permission"
+	MESSAGE_FAILED_ASSIGN_ROLE         = "message.failed_assign_role"
+
+	// Success
+	MESSAGE_SUCCESS_GET_LIST_ROLE       = "message.success_get_list_role"
+	MESSAGE_SUCCESS_CREATE_ROLE         = "message.success_create_role"
+	MESSAGE_SUCCESS_UPDATE_ROLE         = "message.success_update_role"
+	MESSAGE_SUCCESS_DELETE_ROLE         = "message.success_delete_role"
+	MESSAGE_SUCCESS_GET_LIST_PERMISSION = "message.success_get_list_permission"
+	MESSAGE_SUCCESS_CREATE_PERMISSION   = "message.success_create_permission"
+	MESSAGE_SUCCESS_ASSIGN_ROLE         = "message.success_assign_role"
+)
+
+var (
+	ErrPermissionDenied     = NewAppError(http.StatusForbidden, "PERMISSION_DENIED", "anda tidak memiliki izin untuk mengakses resource ini")
+	ErrRoleNotFound         = NewAppError(http.StatusNotFound, "ROLE_NOT_FOUND", "role tidak ditemukan")
+	ErrRoleAlreadyExists    = NewAppError(http.StatusConflict, "ROLE_ALREADY_EXISTS", "role sudah ada")
+	ErrBuiltInRole          = NewAppError(http.StatusForbidden, "BUILT_IN_ROLE", "role bawaan tidak dapat dihapus")
+	ErrPermissionNotFound   = NewAppError(http.StatusNotFound, "PERMISSION_NOT_FOUND", "permission tidak ditemukan")
+	ErrPermissionExists     = NewAppError(http.StatusConflict, "PERMISSION_ALREADY_EXISTS", "permission sudah ada")
+	ErrInvalidPermission    = NewAppError(http.StatusBadRequest, "INVALID_PERMISSION", "format permission harus <resource>:<action>")
+	ErrInvalidRoleID        = NewAppError(http.StatusBadRequest, "INVALID_ROLE_ID", "id role tidak valid")
+	ErrUnknownRoleAssigned  = NewAppError(http.StatusBadRequest, "UNKNOWN_ROLE", "terdapat role yang tidak ditemukan")
+	ErrUnknownPermissionSet = NewAppError(http.StatusBadRequest, "UNKNOWN_PERMISSION", "terdapat permission yang tidak ditemukan")
+)
+
+type (
+	RoleResponse struct {
+		ID          string   `json:"id"`
+		Name        string   `json:"name"`
+		Description string   `json:"description"`
+		Permissions []string `json:"permissions"`
+	}
+
+	PermissionResponse struct {
+		ID          string `json:"id"`
+		Name        string `json:"name"`
+		Description string `json:"description"`
+	}
+
+	CreateRoleRequest struct {
+		Name        string   `json:"name" form:"name" binding:"required"`
+		Description string   `json:"description" form:"description"`
+		Permissions []string `json:"permissions" form:"permissions"`
+	}
+
+	UpdateRoleRequest struct {
+		Description string   `json:"description" form:"description"`
+		Permissions []string `json:"permissions" form:"permissions"`
+	}
+
+	CreatePermissionRequest struct {
+		Name        string `json:"name" form:"name" binding:"required"`
+		Description string `json:"description" form:"description"`
+	}
+
+	AssignRolesRequest struct {
+		Roles []string `json:"roles" form:"roles"`
+	}
+
+	UserRolesResponse struct {
+		UserID      string   `json:"user_id"`
+		Roles       []string `json:"roles"`
+		Permissions []string `json:"permissions"`
+	}
+)
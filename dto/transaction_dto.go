@@ -1,15 +1,15 @@
 package dto
 
-import "errors"
+import "net/http"
 
 var (
-	ErrTransactionNotFound           = errors.New("transaction not found")
-	ErrUnrecognizedCallbackEvent     = errors.New("unrecognized callback event")
-	ErrInvalidSignature              = errors.New("invalid signature")
-	ErrOnlyClosedPaymentSupported    = errors.New("only closed payment supported")
-	ErrFailedToUpdateStatus          = errors.New("failed to update transaction status")
-	ErrFailedToSoftDeleteTransaction = errors.New("failed to soft delete transaction")
-	ErrUnknownStatus                 = errors.New("unknown transaction status")
+	ErrTransactionNotFound           = NewAppError(http.StatusNotFound, "TRANSACTION_NOT_FOUND", "transaction not found")
+	ErrUnrecognizedCallbackEvent     = NewAppError(http.StatusBadRequest, "UNRECOGNIZED_CALLBACK_EVENT", "unrecognized callback event")
+	ErrInvalidSignature              = NewAppError(http.StatusUnauthorized, "INVALID_SIGNATURE", "invalid signature")
+	ErrOnlyClosedPaymentSupported    = NewAppError(http.StatusBadRequest, "CLOSED_PAYMENT_ONLY", "only closed payment supported")
+	ErrFailedToUpdateStatus          = NewAppError(http.StatusInternalServerError, "TRANSACTION_UPDATE_FAILED", "failed to update transaction status")
+	ErrFailedToSoftDeleteTransaction = NewAppError(http.StatusInternalServerError, "TRANSACTION_DELETE_FAILED", "failed to soft delete transaction")
+	ErrUnknownStatus                 = NewAppError(http.StatusBadRequest, "UNKNOWN_TRANSACTION_STATUS", "unknown transaction status")
 )
 
 type (
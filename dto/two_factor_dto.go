@@ -0,0 +1,67 @@
+package dto
+
+import "net/http"
+
+const (
+	// Failed
+	MESSAGE_FAILED_SETUP_TWO_FACTOR   = "message.failed_setup_two_factor"
+	MESSAGE_FAILED_CONFIRM_TWO_FACTOR = "message.failed_confirm_two_factor"
+	MESSAGE_FAILED_DISABLE_TWO_FACTOR = "message.failed_disable_two_factor"
+	MESSAGE_FAILED_VERIFY_TWO_FACTOR  = "message.failed_verify_two_factor"
+
+	// Success
+	MESSAGE_SUCCESS_SETUP_TWO_FACTOR   = "message.success_setup_two_factor"
+	MESSAGE_SUCCESS_CONFIRM_TWO_FACTOR = "message.success_confirm_two_factor"
+	MESSAGE_SUCCESS_DISABLE_TWO_FACTOR = "message.success_disable_two_factor"
+	MESSAGE_SUCCESS_VERIFY_TWO_FACTOR  = "message.success_verify_two_factor"
+)
+
+var (
+	ErrTwoFactorAlreadyEnabled = NewAppError(http.StatusConflict, "TWO_FACTOR_ALREADY_ENABLED", "autentikasi dua faktor sudah aktif")
+	ErrTwoFactorNotEnabled     = NewAppError(http.StatusConflict, "TWO_FACTOR_NOT_ENABLED", "autentikasi dua faktor belum aktif")
+	ErrTwoFactorNotSetup       = NewAppError(http.StatusConflict, "TWO_FACTOR_NOT_SETUP", "autentikasi dua faktor belum disiapkan")
+	ErrTwoFactorCodeInvalid    = NewAppError(http.StatusBadRequest, "TWO_FACTOR_CODE_INVALID", "kode autentikasi dua faktor tidak valid")
+	ErrTwoFactorEnforced       = NewAppError(http.StatusForbidden, "TWO_FACTOR_ENFORCED", "autentikasi dua faktor wajib untuk akun ini")
+	ErrChallengeTokenInvalid   = NewAppError(http.StatusUnauthorized, "CHALLENGE_TOKEN_INVALID", "challenge token tidak valid atau kadaluarsa")
+)
+
+type (
+	TwoFactorSetupResponse struct {
+		Secret     string `json:"secret"`
+		OtpauthURL string `json:"otpauth_url"`
+		QRCode     string `json:"qr_code"` // data:image/png;base64,...
+	}
+
+	TwoFactorConfirmRequest struct {
+		Code string `json:"code" form:"code" binding:"required"`
+	}
+
+	TwoFactorConfirmResponse struct {
+		RecoveryCodes []string `json:"recovery_codes"`
+	}
+
+	TwoFactorDisableRequest struct {
+		Password string `json:"password" form:"password" binding:"required"`
+		Code     string `json:"code" form:"code" binding:"required"`
+	}
+
+	TwoFactorChallengeRequest struct {
+		ChallengeToken string `json:"challenge_token" form:"challenge_token" binding:"required"`
+	}
+
+	TwoFactorVerifyRequest struct {
+		ChallengeToken string `json:"challenge_token" form:"challenge_token" binding:"required"`
+		Code           string `json:"code" form:"code"`
+		RecoveryCode   string `json:"recovery_code" form:"recovery_code"`
+	}
+
+	TwoFactorEnrollConfirmRequest struct {
+		ChallengeToken string `json:"challenge_token" form:"challenge_token" binding:"required"`
+		Code           string `json:"code" form:"code" binding:"required"`
+	}
+
+	TwoFactorEnrollConfirmResponse struct {
+		UserLoginResponse
+		RecoveryCodes []string `json:"recovery_codes"`
+	}
+)
@@ -1,50 +1,75 @@
 package dto
 
-import "errors"
+import (
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+)
 
 const (
 	// Failed
-	MESSAGE_FAILED_REGISTER_USER   = "gagal melakukan registrasi user"
-	MESSAGE_FAILED_LOGIN_USER      = "gagal melakukan login user"
-	MESSAGE_FAILED_VERIFY_EMAIL    = "gagal memverifikasi email"
-	MESSAGE_FAILED_FORGET_PASSWORD = "gagal memproses permintaan lupa password"
-	MESSAGE_FAILED_RESET_PASSWORD  = "gagal mereset password"
-	MESSAGE_FAILED_GET_USER        = "gagal mendapatkan data user"
-	MESSAGE_FAILED_UPDATE_USER     = "gagal memperbarui data user"
+	MESSAGE_FAILED_REGISTER_USER   = "message.failed_register_user"
+	MESSAGE_FAILED_LOGIN_USER      = "message.failed_login_user"
+	MESSAGE_FAILED_VERIFY_EMAIL    = "message.failed_verify_email"
+	MESSAGE_FAILED_FORGET_PASSWORD = "message.failed_forget_password"
+	MESSAGE_FAILED_RESET_PASSWORD  = "message.failed_reset_password"
+	MESSAGE_FAILED_GET_USER        = "message.failed_get_user"
+	MESSAGE_FAILED_UPDATE_USER     = "message.failed_update_user"
+	MESSAGE_FAILED_REFRESH_TOKEN   = "message.failed_refresh_token"
+	MESSAGE_FAILED_LOGOUT_USER     = "message.failed_logout_user"
+	MESSAGE_FAILED_UNLOCK_ACCOUNT  = "message.failed_unlock_account"
+	MESSAGE_FAILED_CHANGE_PASSWORD = "message.failed_change_password"
+	MESSAGE_FAILED_CHANGE_EMAIL    = "message.failed_change_email"
+	MESSAGE_FAILED_CONFIRM_EMAIL   = "message.failed_confirm_email"
 
 	// Success
-	MESSAGE_SUCCESS_REGISTER_USER           = "berhasil melakukan registrasi user"
-	MESSAGE_SUCCESS_LOGIN_USER              = "berhasil melakukan login user"
-	MESSAGE_SEND_VERIFICATION_EMAIL_SUCCESS = "berhasil mengirim email verifikasi"
-	MESSAGE_SUCCESS_VERIFY_EMAIL            = "berhasil memverifikasi email"
-	MESSAGE_SUCCESS_FORGET_PASSWORD         = "berhasil memproses permintaan lupa password"
-	MESSAGE_SUCCESS_RESET_PASSWORD          = "berhasil mereset password"
-	MESSAGE_SUCCESS_GET_USER                = "berhasil mendapatkan data user"
-	MESSAGE_SUCCESS_UPDATE_USER             = "berhasil memperbarui data user"
+	MESSAGE_SUCCESS_REGISTER_USER           = "message.success_register_user"
+	MESSAGE_SUCCESS_LOGIN_USER              = "message.success_login_user"
+	MESSAGE_SEND_VERIFICATION_EMAIL_SUCCESS = "message.send_verification_email_success"
+	MESSAGE_SUCCESS_VERIFY_EMAIL            = "message.success_verify_email"
+	MESSAGE_SUCCESS_FORGET_PASSWORD         = "message.success_forget_password"
+	MESSAGE_SUCCESS_RESET_PASSWORD          = "message.success_reset_password"
+	MESSAGE_SUCCESS_GET_USER                = "message.success_get_user"
+	MESSAGE_SUCCESS_UPDATE_USER             = "message.success_update_user"
+	MESSAGE_SUCCESS_REFRESH_TOKEN           = "message.success_refresh_token"
+	MESSAGE_SUCCESS_LOGOUT_USER             = "message.success_logout_user"
+	MESSAGE_SUCCESS_UNLOCK_ACCOUNT          = "message.success_unlock_account"
+	MESSAGE_SUCCESS_CHANGE_PASSWORD         = "message.success_change_password"
+	MESSAGE_SUCCESS_CHANGE_EMAIL            = "message.success_change_email"
+	MESSAGE_SUCCESS_CONFIRM_EMAIL           = "message.success_confirm_email"
 )
 
 var (
-	ErrorEmailAlreadyExists   = errors.New("email sudah terdaftar")
-	ErrMakeMail               = errors.New("gagal membuat email")
-	ErrSendMail               = errors.New("gagal mengirim email")
-	ErrTokenInvalid           = errors.New("token tidak valid atau kadaluarsa")
-	ErrTokenExpired           = errors.New("token telah kadaluarsa")
-	ErrUserNotFound           = errors.New("user tidak ditemukan")
-	ErrAccountAlreadyVerified = errors.New("akun sudah terverifikasi")
-	ErrUpdateUser             = errors.New("gagal memperbarui data user")
-	ErrEmailNotFound          = errors.New("email tidak ditemukan")
-	ErrHashPasswordFailed     = errors.New("gagal melakukan hash password")
-	ErrNoChanges              = errors.New("tidak ada perubahan pada data user")
-	ErrInvalidCredentials     = errors.New("kredensial tidak valid")
+	ErrorEmailAlreadyExists   = NewAppError(http.StatusConflict, "EMAIL_ALREADY_EXISTS", "email sudah terdaftar")
+	ErrMakeMail               = NewAppError(http.StatusInternalServerError, "EMAIL_BUILD_FAILED", "gagal membuat email")
+	ErrTokenInvalid           = NewAppError(http.StatusBadRequest, "TOKEN_INVALID", "token tidak valid atau kadaluarsa")
+	ErrTokenExpired           = NewAppError(http.StatusBadRequest, "TOKEN_EXPIRED", "token telah kadaluarsa")
+	ErrUserNotFound           = NewAppError(http.StatusNotFound, "USER_NOT_FOUND", "user tidak ditemukan")
+	ErrAccountAlreadyVerified = NewAppError(http.StatusConflict, "ACCOUNT_ALREADY_VERIFIED", "akun sudah terverifikasi")
+	ErrUpdateUser             = NewAppError(http.StatusInternalServerError, "USER_UPDATE_FAILED", "gagal memperbarui data user")
+	ErrEmailNotFound          = NewAppError(http.StatusNotFound, "EMAIL_NOT_FOUND", "email tidak ditemukan")
+	ErrHashPasswordFailed     = NewAppError(http.StatusInternalServerError, "PASSWORD_HASH_FAILED", "gagal melakukan hash password")
+	ErrNoChanges              = NewAppError(http.StatusBadRequest, "NO_CHANGES", "tidak ada perubahan pada data user")
+	ErrInvalidCredentials     = NewAppError(http.StatusUnauthorized, "INVALID_CREDENTIALS", "kredensial tidak valid")
+	ErrRefreshTokenInvalid    = NewAppError(http.StatusUnauthorized, "REFRESH_TOKEN_INVALID", "refresh token tidak valid")
+	ErrRefreshTokenExpired    = NewAppError(http.StatusUnauthorized, "REFRESH_TOKEN_EXPIRED", "refresh token telah kadaluarsa")
+	ErrRefreshTokenReused     = NewAppError(http.StatusUnauthorized, "REFRESH_TOKEN_REUSED", "refresh token sudah pernah digunakan, sesi dicabut")
+	ErrSessionRevoked         = NewAppError(http.StatusUnauthorized, "SESSION_REVOKED", "sesi sudah tidak berlaku")
+	ErrAccountLocked          = NewAppError(http.StatusTooManyRequests, "ACCOUNT_LOCKED", "akun dikunci sementara karena terlalu banyak percobaan login")
+	ErrTooManyLoginAttempts   = NewAppError(http.StatusTooManyRequests, "TOO_MANY_LOGIN_ATTEMPTS", "terlalu banyak percobaan login, coba lagi nanti")
+	ErrWrongPassword          = NewAppError(http.StatusBadRequest, "WRONG_PASSWORD", "password saat ini salah")
+	ErrSamePassword           = NewAppError(http.StatusBadRequest, "SAME_PASSWORD", "password baru tidak boleh sama dengan password lama")
+	ErrSameEmail              = NewAppError(http.StatusBadRequest, "SAME_EMAIL", "email baru sama dengan email saat ini")
 )
 
 type (
 	UserRegistrationRequest struct {
 		Name     string `json:"name" form:"name" binding:"required"`
 		Email    string `json:"email" form:"email" binding:"required,email"`
-		Password string `json:"password" form:"password" binding:"required"`
+		Password string `json:"password" form:"password" binding:"required,strong_password"`
 		Instansi string `json:"instansi" form:"instansi" binding:"required"`
-		NoTelp   string `json:"no_telp" form:"no_telp" binding:"required"`
+		NoTelp   string `json:"no_telp" form:"no_telp" binding:"required,no_telp"`
 	}
 
 	UserResponse struct {
@@ -55,6 +80,7 @@ type (
 		NoTelp     string `json:"no_telp"`
 		Role       string `json:"role"`
 		IsVerified bool   `json:"is_verified"`
+		Locale     string `json:"locale,omitempty"`
 	}
 
 	UserLoginRequest struct {
@@ -63,8 +89,27 @@ type (
 	}
 
 	UserLoginResponse struct {
-		Token string `json:"token"`
-		Role  string `json:"role"`
+		Token            string     `json:"token,omitempty"`
+		RefreshToken     string     `json:"refresh_token,omitempty"`
+		RefreshExpiresAt *time.Time `json:"refresh_expires_at,omitempty"`
+		Role             string     `json:"role"`
+
+		// Set instead of the tokens above when a second factor is needed
+		TwoFactorRequired       bool   `json:"two_factor_required,omitempty"`
+		TwoFactorSetupRequired  bool   `json:"two_factor_setup_required,omitempty"`
+		TwoFactorChallengeToken string `json:"challenge_token,omitempty"`
+	}
+
+	RefreshTokenRequest struct {
+		RefreshToken string `json:"refresh_token" form:"refresh_token" binding:"required"`
+	}
+
+	SessionToken struct {
+		TokenID      uuid.UUID
+		SessionID    uuid.UUID
+		UserID       uuid.UUID
+		RefreshToken string
+		ExpiresAt    time.Time
 	}
 
 	SendVerificationEmailRequest struct {
@@ -85,16 +130,31 @@ type (
 	}
 
 	ResetPasswordRequest struct {
-		Password string `json:"password" form:"password" binding:"required"`
+		Password string `json:"password" form:"password" binding:"required,strong_password"`
 	}
 
 	ResetPasswordResponse struct {
 		Email string `json:"email"`
 	}
 
+	ChangePasswordRequest struct {
+		CurrentPassword string `json:"current_password" form:"current_password" binding:"required"`
+		NewPassword     string `json:"new_password" form:"new_password" binding:"required,strong_password"`
+	}
+
+	ChangeEmailRequest struct {
+		NewEmail string `json:"new_email" form:"new_email" binding:"required,email"`
+		Password string `json:"password" form:"password" binding:"required"`
+	}
+
+	ChangeEmailResponse struct {
+		PendingEmail string `json:"pending_email"`
+	}
+
 	UserUpdateRequest struct {
 		Name     string `json:"name" form:"name"`
 		Instansi string `json:"instansi" form:"instansi"`
-		NoTelp   string `json:"no_telp" form:"no_telp"`
+		NoTelp   string `json:"no_telp" form:"no_telp" binding:"omitempty,no_telp"`
+		Locale   string `json:"locale" form:"locale" binding:"omitempty,oneof=id en"`
 	}
 )
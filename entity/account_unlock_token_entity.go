@@ -0,0 +1,22 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// AccountUnlockToken stores the hash of an unlock link sent when an account
+// gets locked. Like a password reset token it can be used once.
+type AccountUnlockToken struct {
+	ID     uuid.UUID `gorm:"type:uuid;primary_key;default:uuid_generate_v4()" json:"id"`
+	UserID uuid.UUID `gorm:"type:uuid;index" json:"user_id"`
+
+	TokenHash  string     `gorm:"uniqueIndex" json:"-"`
+	ExpiresAt  time.Time  `gorm:"type:timestamp with time zone" json:"expires_at"`
+	ConsumedAt *time.Time `gorm:"type:timestamp with time zone" json:"consumed_at"`
+
+	User *User `gorm:"foreignKey:UserID"`
+
+	Timestamp
+}
@@ -0,0 +1,58 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type AuditAction string
+
+const (
+	AuditActionRegister           AuditAction = "user.register"
+	AuditActionLogin              AuditAction = "auth.login"
+	AuditActionLoginFailed        AuditAction = "auth.login_failed"
+	AuditActionTwoFactorChallenge AuditAction = "auth.2fa_challenge"
+	AuditActionLogout             AuditAction = "auth.logout"
+	AuditActionTokenRefresh       AuditAction = "auth.token_refresh"
+	AuditActionAccountUnlock      AuditAction = "auth.account_unlock"
+	AuditActionVerifyEmail        AuditAction = "user.verify_email"
+	AuditActionForgotPassword     AuditAction = "user.forgot_password"
+	AuditActionResetPassword      AuditAction = "user.reset_password"
+	AuditActionChangePassword     AuditAction = "user.change_password"
+	AuditActionChangeEmail        AuditAction = "user.change_email"
+	AuditActionConfirmEmailChange AuditAction = "user.confirm_email_change"
+	AuditActionUpdateProfile      AuditAction = "user.update_profile"
+	AuditActionTwoFactorEnable    AuditAction = "user.2fa_enable"
+	AuditActionTwoFactorDisable   AuditAction = "user.2fa_disable"
+	AuditActionAdminChangeRole    AuditAction = "admin.change_role"
+	AuditActionAdminVerifyUser    AuditAction = "admin.verify_user"
+	AuditActionAdminSuspendUser   AuditAction = "admin.suspend_user"
+	AuditActionAdminUnsuspendUser AuditAction = "admin.unsuspend_user"
+	AuditActionAdminDeleteUser    AuditAction = "admin.delete_user"
+	AuditActionAdminRestoreUser   AuditAction = "admin.restore_user"
+	AuditActionRoleCreate         AuditAction = "role.create"
+	AuditActionRoleUpdate         AuditAction = "role.update"
+	AuditActionRoleDelete         AuditAction = "role.delete"
+	AuditActionRoleAssign         AuditAction = "role.assign"
+	AuditActionPermissionCreate   AuditAction = "permission.create"
+	AuditActionTransactionStatus  AuditAction = "transaction.status_change"
+	AuditActionTransactionDelete  AuditAction = "transaction.delete"
+)
+
+// AuditLog is append-only, so it carries no UpdatedAt or soft delete.
+// Rows are removed only by the retention job.
+type AuditLog struct {
+	ID uuid.UUID `gorm:"type:uuid;primary_key;default:uuid_generate_v4()" json:"id"`
+
+	ActorID    *uuid.UUID  `gorm:"type:uuid;index" json:"actor_id"`
+	Action     AuditAction `gorm:"index" json:"action"`
+	TargetType string      `json:"target_type"`
+	TargetID   string      `gorm:"index" json:"target_id"`
+	Success    bool        `json:"success"`
+	IPAddress  string      `json:"ip_address"`
+	UserAgent  string      `json:"user_agent"`
+	Changes    string      `gorm:"type:text" json:"changes,omitempty"` // JSON encoded {"field": {"from": .., "to": ..}}
+
+	CreatedAt time.Time `gorm:"type:timestamp with time zone;index" json:"created_at"`
+}
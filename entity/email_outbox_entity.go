@@ -0,0 +1,36 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type EmailStatus string
+
+const (
+	EmailStatusPending EmailStatus = "pending"
+	EmailStatusSent    EmailStatus = "sent"
+	EmailStatusDead    EmailStatus = "dead" // gave up after OUTBOX_MAX_ATTEMPTS, waits for an admin resend
+)
+
+// EmailOutbox is an email queued for delivery. Rows are written in the same
+// transaction as the change that triggers them and sent by the outbox
+// worker, so a committed change never loses its email and a rolled back
+// one never sends it.
+type EmailOutbox struct {
+	ID uuid.UUID `gorm:"type:uuid;primary_key;default:uuid_generate_v4()" json:"id"`
+
+	ToEmail string      `gorm:"index" json:"to_email"`
+	Subject string      `json:"subject"`
+	Message string      `gorm:"type:jsonb" json:"-"` // the mailer.Message as JSON, may hold one-time links
+	Status  EmailStatus `gorm:"index" json:"status"`
+
+	Attempts      int        `json:"attempts"`
+	NextAttemptAt time.Time  `gorm:"type:timestamp with time zone;index" json:"next_attempt_at"`
+	LastError     string     `gorm:"type:text" json:"last_error"`
+	SentAt        *time.Time `gorm:"type:timestamp with time zone" json:"sent_at"`
+
+	CreatedAt time.Time `gorm:"type:timestamp with time zone" json:"created_at"`
+	UpdatedAt time.Time `gorm:"type:timestamp with time zone" json:"updated_at"`
+}
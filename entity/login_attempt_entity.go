@@ -0,0 +1,13 @@
+package entity
+
+import "time"
+
+// LoginAttempt holds the failed login counter for one key, either an account
+// ("email:<address>") or a client ("ip:<address>").
+type LoginAttempt struct {
+	Key           string     `gorm:"primary_key" json:"key"`
+	Failures      int        `json:"failures"`
+	LastFailureAt time.Time  `gorm:"type:timestamp with time zone" json:"last_failure_at"`
+	LockedUntil   *time.Time `gorm:"type:timestamp with time zone" json:"locked_until"`
+	UpdatedAt     time.Time  `gorm:"type:timestamp with time zone" json:"updated_at"`
+}
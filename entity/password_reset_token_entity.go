@@ -0,0 +1,22 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// PasswordResetToken stores the hash of a reset link sent by email. A token is
+// consumed once it has been used or a newer reset request has been made.
+type PasswordResetToken struct {
+	ID     uuid.UUID `gorm:"type:uuid;primary_key;default:uuid_generate_v4()" json:"id"`
+	UserID uuid.UUID `gorm:"type:uuid;index" json:"user_id"`
+
+	TokenHash  string     `gorm:"uniqueIndex" json:"-"`
+	ExpiresAt  time.Time  `gorm:"type:timestamp with time zone" json:"expires_at"`
+	ConsumedAt *time.Time `gorm:"type:timestamp with time zone" json:"consumed_at"`
+
+	User *User `gorm:"foreignKey:UserID"`
+
+	Timestamp
+}
@@ -0,0 +1,21 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// RecoveryCode is a one-time code that can replace a TOTP code when the
+// authenticator device is lost. Only the hash is stored.
+type RecoveryCode struct {
+	ID     uuid.UUID `gorm:"type:uuid;primary_key;default:uuid_generate_v4()" json:"id"`
+	UserID uuid.UUID `gorm:"type:uuid;index" json:"user_id"`
+
+	CodeHash string     `gorm:"index" json:"-"`
+	UsedAt   *time.Time `gorm:"type:timestamp with time zone" json:"used_at"`
+
+	User *User `gorm:"foreignKey:UserID"`
+
+	Timestamp
+}
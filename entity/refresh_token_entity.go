@@ -0,0 +1,25 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// RefreshToken is one link in a refresh token rotation chain. Every token
+// issued for the same login shares a SessionID, which is also embedded in the
+// access token so a revoked session can be rejected before the JWT expires.
+type RefreshToken struct {
+	ID        uuid.UUID `gorm:"type:uuid;primary_key;default:uuid_generate_v4()" json:"id"`
+	UserID    uuid.UUID `gorm:"type:uuid;index" json:"user_id"`
+	SessionID uuid.UUID `gorm:"type:uuid;index" json:"session_id"`
+
+	TokenHash  string     `gorm:"uniqueIndex" json:"-"`
+	ExpiresAt  time.Time  `gorm:"type:timestamp with time zone" json:"expires_at"`
+	RevokedAt  *time.Time `gorm:"type:timestamp with time zone" json:"revoked_at"`
+	ReplacedBy *uuid.UUID `gorm:"type:uuid" json:"replaced_by"`
+
+	User *User `gorm:"foreignKey:UserID"`
+
+	Timestamp
+}
@@ -0,0 +1,25 @@
+package entity
+
+import "github.com/google/uuid"
+
+// PermissionAll grants every permission. Wildcards are also accepted per
+// resource, e.g. "transactions:*".
+const PermissionAll = "*"
+
+type Role struct {
+	ID          uuid.UUID `gorm:"type:uuid;primary_key;default:uuid_generate_v4()" json:"id"`
+	Name        string    `gorm:"uniqueIndex" json:"name"`
+	Description string    `json:"description"`
+
+	Permissions []Permission `gorm:"many2many:role_permissions" json:"permissions"`
+
+	Timestamp
+}
+
+type Permission struct {
+	ID          uuid.UUID `gorm:"type:uuid;primary_key;default:uuid_generate_v4()" json:"id"`
+	Name        string    `gorm:"uniqueIndex" json:"name"` // "<resource>:<action>", e.g. "transactions:refund"
+	Description string    `json:"description"`
+
+	Timestamp
+}
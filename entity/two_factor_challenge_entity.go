@@ -0,0 +1,24 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// TwoFactorChallenge backs a challenge token issued after a correct password,
+// its ID is the token's jti. A challenge is consumed once it leads to a login
+// or after too many wrong codes, so a token can't be reused for guessing.
+type TwoFactorChallenge struct {
+	ID     uuid.UUID `gorm:"type:uuid;primary_key;default:uuid_generate_v4()" json:"id"`
+	UserID uuid.UUID `gorm:"type:uuid;index" json:"user_id"`
+
+	Purpose    string     `json:"purpose"`
+	Failures   int        `json:"failures"`
+	ExpiresAt  time.Time  `gorm:"type:timestamp with time zone" json:"expires_at"`
+	ConsumedAt *time.Time `gorm:"type:timestamp with time zone" json:"consumed_at"`
+
+	User *User `gorm:"foreignKey:UserID"`
+
+	Timestamp
+}
@@ -1,6 +1,8 @@
 package entity
 
 import (
+	"time"
+
 	"github.com/Shabrinashsf/go-gin-gorm-boilerplate/helpers"
 	"github.com/google/uuid"
 	"gorm.io/gorm"
@@ -23,6 +25,21 @@ type User struct {
 	NoTelp     string   `json:"no_telp"`
 	Role       UserRole `json:"role" gorm:"default:user"`
 	IsVerified bool     `json:"is_verified"`
+	Locale     string   `json:"locale"` // preferred language for emails, empty follows the request
+
+	// Extra roles on top of Role, which is kept for OnlyAllow and always counts as assigned
+	Roles []Role `gorm:"many2many:user_roles" json:"roles,omitempty"`
+
+	IsSuspended bool `json:"is_suspended"`
+
+	// Email change waiting for confirmation from the new address
+	PendingEmail          string     `json:"pending_email,omitempty"`
+	PendingEmailTokenHash string     `gorm:"index" json:"-"`
+	PendingEmailExpiresAt *time.Time `gorm:"type:timestamp with time zone" json:"-"`
+
+	TwoFactorEnabled  bool   `json:"two_factor_enabled"`
+	TwoFactorSecret   string `json:"-"` // AES encrypted, set during enrollment
+	TwoFactorLastStep int64  `json:"-"` // last accepted TOTP time step, codes at or before it are replays
 
 	Timestamp
 }
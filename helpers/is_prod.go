@@ -1,10 +0,0 @@
-package helpers
-
-import "os"
-
-func GetStage() bool {
-	if os.Getenv("IS_PRODUCTION") == "false" {
-		return false
-	}
-	return true
-}
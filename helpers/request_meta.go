@@ -0,0 +1,21 @@
+package helpers
+
+import "context"
+
+type requestMetaKey struct{}
+
+// RequestMeta describes the client behind the current request.
+type RequestMeta struct {
+	IPAddress string
+	UserAgent string
+}
+
+func WithRequestMeta(ctx context.Context, meta RequestMeta) context.Context {
+	return context.WithValue(ctx, requestMetaKey{}, meta)
+}
+
+// GetRequestMeta returns an empty RequestMeta when ctx did not come from an HTTP request.
+func GetRequestMeta(ctx context.Context) RequestMeta {
+	meta, _ := ctx.Value(requestMetaKey{}).(RequestMeta)
+	return meta
+}
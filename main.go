@@ -2,33 +2,39 @@ package main
 
 import (
 	"context"
-	"fmt"
-	"log"
 	"net/http"
 	"os"
 	"os/signal"
+	"sync"
 	"syscall"
 	"time"
 
 	"github.com/Shabrinashsf/go-gin-gorm-boilerplate/cmd"
+	"github.com/Shabrinashsf/go-gin-gorm-boilerplate/config"
 	"github.com/Shabrinashsf/go-gin-gorm-boilerplate/controller"
 	"github.com/Shabrinashsf/go-gin-gorm-boilerplate/database"
+	"github.com/Shabrinashsf/go-gin-gorm-boilerplate/dto"
 	"github.com/Shabrinashsf/go-gin-gorm-boilerplate/middleware"
 	"github.com/Shabrinashsf/go-gin-gorm-boilerplate/repository"
 	"github.com/Shabrinashsf/go-gin-gorm-boilerplate/routes"
 	"github.com/Shabrinashsf/go-gin-gorm-boilerplate/service"
 	"github.com/Shabrinashsf/go-gin-gorm-boilerplate/utils/logger"
 	"github.com/Shabrinashsf/go-gin-gorm-boilerplate/utils/mailer"
+	"github.com/Shabrinashsf/go-gin-gorm-boilerplate/utils/metrics"
+	"github.com/Shabrinashsf/go-gin-gorm-boilerplate/utils/response"
+	"github.com/Shabrinashsf/go-gin-gorm-boilerplate/utils/storage"
+	"github.com/Shabrinashsf/go-gin-gorm-boilerplate/utils/tracing"
+	"github.com/Shabrinashsf/go-gin-gorm-boilerplate/utils/validation"
 	"github.com/common-nighthawk/go-figure"
 	"github.com/gin-gonic/gin"
 	"github.com/joho/godotenv"
+	"go.opentelemetry.io/contrib/instrumentation/github.com/gin-gonic/gin/otelgin"
 	"gorm.io/gorm"
 )
 
 type Server struct {
 	// Configuration
-	port string
-	env  string
+	cfg *config.Config
 
 	// HTTP Server
 	ginEngine  *gin.Engine
@@ -37,6 +43,7 @@ type Server struct {
 	// Context Management
 	rootCTX    context.Context
 	cancelFunc context.CancelFunc
+	workers    sync.WaitGroup
 
 	// Database
 	db *gorm.DB
@@ -44,62 +51,153 @@ type Server struct {
 	// Dependency injection
 	jwtService service.JWTService
 	mailer     mailer.Mailer
+	storage    storage.AwsS3
 
 	// Repository
-	transactionRepo repository.TransactionRepository
-	userRepo        repository.UserRepository
+	auditLogRepo      repository.AuditLogRepository
+	unlockTokenRepo   repository.AccountUnlockTokenRepository
+	emailOutboxRepo   repository.EmailOutboxRepository
+	loginAttemptRepo  repository.LoginAttemptRepository
+	passwordResetRepo repository.PasswordResetTokenRepository
+	recoveryCodeRepo  repository.RecoveryCodeRepository
+	refreshTokenRepo  repository.RefreshTokenRepository
+	roleRepo          repository.RoleRepository
+	transactionRepo   repository.TransactionRepository
+	twoFactorRepo     repository.TwoFactorChallengeRepository
+	userRepo          repository.UserRepository
 
 	// Service
+	adminUserService   service.AdminUserService
+	auditService       service.AuditService
+	emailOutboxService service.EmailOutboxService
+	healthService      service.HealthService
+	loginGuardService  service.LoginGuardService
+	roleService        service.RoleService
+	sessionService     service.SessionService
 	transactionService service.TransactionService
+	twoFactorService   service.TwoFactorService
 	userService        service.UserService
 
 	// Controller
+	adminUserController   controller.AdminUserController
+	auditLogController    controller.AuditLogController
+	devMailboxController  controller.DevMailboxController
+	emailOutboxController controller.EmailOutboxController
+	healthController      controller.HealthController
+	roleController        controller.RoleController
 	transactionController controller.TransactionController
+	twoFactorController   controller.TwoFactorController
 	userController        controller.UserController
+	wellKnownController   controller.WellKnownController
 }
 
-func NewServer(db *gorm.DB) *Server {
-	jwtService := service.NewJWTService()
-	mailer := mailer.NewMailer()
+func NewServer(cfg *config.Config, db *gorm.DB) *Server {
+	jwtService := service.NewJWTService(cfg)
+	mailer := mailer.NewMailer(cfg.Mail, cfg.SMTP)
+	var s3 storage.AwsS3
+	if cfg.AWS.Bucket != "" {
+		s3 = storage.NewAwsS3(cfg.AWS)
+	}
 
 	// Repository
+	auditLogRepo := repository.NewAuditLogRepository(db)
+	unlockTokenRepo := repository.NewAccountUnlockTokenRepository(db)
+	emailOutboxRepo := repository.NewEmailOutboxRepository(db)
+	loginAttemptRepo := repository.NewMemoryLoginAttemptRepository()
+	if cfg.Security.LoginAttemptStore == "postgres" {
+		loginAttemptRepo = repository.NewLoginAttemptRepository(db)
+	}
+	passwordResetRepo := repository.NewPasswordResetTokenRepository(db)
+	recoveryCodeRepo := repository.NewRecoveryCodeRepository(db)
+	refreshTokenRepo := repository.NewRefreshTokenRepository(db)
+	roleRepo := repository.NewRoleRepository(db)
 	transactionRepo := repository.NewTransactionRepository(db)
+	twoFactorRepo := repository.NewTwoFactorChallengeRepository(db)
 	userRepo := repository.NewUserController(db)
 
 	// Service
-	transactionService := service.NewTransactionService(transactionRepo, db)
-	userService := service.NewUserService(userRepo, jwtService, mailer, db)
+	auditService := service.NewAuditService(auditLogRepo, cfg)
+	emailOutboxService := service.NewEmailOutboxService(emailOutboxRepo, mailer, cfg)
+	healthService := service.NewHealthService(cfg)
+	loginGuardService := service.NewLoginGuardService(loginAttemptRepo, unlockTokenRepo, userRepo, emailOutboxService, mailer, cfg, db)
+	roleService := service.NewRoleService(roleRepo, userRepo, auditService, db)
+	sessionService := service.NewSessionService(refreshTokenRepo, db)
+	adminUserService := service.NewAdminUserService(userRepo, sessionService, roleService, auditService, db)
+	transactionService := service.NewTransactionService(transactionRepo, auditService, cfg, db)
+	twoFactorService := service.NewTwoFactorService(userRepo, recoveryCodeRepo, twoFactorRepo, jwtService, sessionService, roleService, loginGuardService, auditService, cfg, db)
+	userService := service.NewUserService(userRepo, passwordResetRepo, twoFactorRepo, jwtService, sessionService, roleService, loginGuardService, auditService, emailOutboxService, mailer, cfg, db)
 
 	// Controller
+	adminUserController := controller.NewAdminUserController(adminUserService)
+	auditLogController := controller.NewAuditLogController(auditService)
+	emailOutboxController := controller.NewEmailOutboxController(emailOutboxService)
+	healthController := controller.NewHealthController(healthService)
+	roleController := controller.NewRoleController(roleService)
 	transactionController := controller.NewTransactionController(transactionService)
+	twoFactorController := controller.NewTwoFactorController(twoFactorService)
 	userController := controller.NewUserController(userService)
-
-	// Get current mode
-	port := os.Getenv("APP_PORT")
-	if port == "" {
-		port = "8888"
+	wellKnownController := controller.NewWellKnownController(jwtService)
+	var devMailboxController controller.DevMailboxController
+	if mailbox, ok := mailer.Mailbox(); ok && !cfg.App.IsProduction {
+		devMailboxController = controller.NewDevMailboxController(mailbox)
 	}
 
-	mode := os.Getenv("APP_ENV")
-	if mode == "" {
-		mode = "localhost"
+	// Readiness checks, modules with their own dependencies register here
+	healthService.Register(service.NewDatabaseHealthCheck(db), true)
+	if cfg.Mail.Transport == "smtp" && cfg.SMTP.Host != "" {
+		healthService.Register(service.NewSMTPHealthCheck(cfg.SMTP), false)
 	}
+	if s3 != nil {
+		healthService.Register(service.NewStorageHealthCheck(s3), false)
+	}
+	healthService.Register(service.NewEmailOutboxHealthCheck(emailOutboxService), false)
 
 	return &Server{
-		port:                  port,
-		env:                   mode,
+		cfg:                   cfg,
 		db:                    db,
+		mailer:                mailer,
+		storage:               s3,
+		adminUserService:      adminUserService,
+		adminUserController:   adminUserController,
+		auditLogRepo:          auditLogRepo,
+		unlockTokenRepo:       unlockTokenRepo,
+		auditService:          auditService,
+		auditLogController:    auditLogController,
+		devMailboxController:  devMailboxController,
+		emailOutboxRepo:       emailOutboxRepo,
+		emailOutboxService:    emailOutboxService,
+		emailOutboxController: emailOutboxController,
+		healthService:         healthService,
+		healthController:      healthController,
+		loginAttemptRepo:      loginAttemptRepo,
+		loginGuardService:     loginGuardService,
+		passwordResetRepo:     passwordResetRepo,
+		recoveryCodeRepo:      recoveryCodeRepo,
+		refreshTokenRepo:      refreshTokenRepo,
+		roleRepo:              roleRepo,
+		roleService:           roleService,
+		roleController:        roleController,
+		sessionService:        sessionService,
 		transactionRepo:       transactionRepo,
+		twoFactorRepo:         twoFactorRepo,
 		transactionService:    transactionService,
 		transactionController: transactionController,
+		twoFactorService:      twoFactorService,
+		twoFactorController:   twoFactorController,
 		userRepo:              userRepo,
 		userService:           userService,
 		userController:        userController,
+		wellKnownController:   wellKnownController,
 		jwtService:            jwtService,
 	}
 }
 
 func main() {
+	// Commands that only render files run without configuration or database
+	if len(os.Args) > 1 && cmd.Standalone(os.Args[1:]) {
+		return
+	}
+
 	// Initialize environment variables
 	if err := godotenv.Load(".env"); err != nil {
 		logger.Errorf("cannot load .env: %v", err)
@@ -107,9 +205,31 @@ func main() {
 		logger.Infof(".env loaded successfully")
 	}
 
+	// Load and validate configuration
+	cfg, err := config.Load()
+	if err != nil {
+		logger.Fatalf("%v", err)
+	}
+	if err := logger.Setup(cfg.Log); err != nil {
+		logger.Fatalf("setup logger: %v", err)
+	}
+
+	if err := validation.Setup(); err != nil {
+		logger.Fatalf("setup validation: %v", err)
+	}
+
+	// Initialize tracing before anything opens spans
+	shutdownTracing, err := tracing.Setup(context.Background(), cfg.Tracing)
+	if err != nil {
+		logger.Fatalf("setup tracing: %v", err)
+	}
+
 	// Initialized database
 	logger.Infof("Setting up database connection...")
-	db := database.SetUpDatabaseConnection()
+	db, err := database.SetUpDatabaseConnection(cfg.Database)
+	if err != nil {
+		logger.Fatalf("%v", err)
+	}
 	defer database.CloseDatabaseConnection(db)
 	logger.Infof("Database connection established.")
 
@@ -121,11 +241,11 @@ func main() {
 	}
 
 	// Create server instance
-	server := NewServer(db)
+	server := NewServer(cfg, db)
 
 	// Start server
 	if err := server.Start(); err != nil {
-		log.Fatalf("Failed to start server: %v", err)
+		logger.Fatalf("Failed to start server: %v", err)
 	}
 
 	quit := make(chan os.Signal, 1)
@@ -141,6 +261,10 @@ func main() {
 		logger.Errorf("Shutdown error: %v", err)
 	}
 
+	if err := shutdownTracing(ctx); err != nil {
+		logger.Errorf("Tracing shutdown error: %v", err)
+	}
+
 	logger.Infof("Application exited")
 }
 
@@ -151,15 +275,27 @@ func (s *Server) Start() error {
 	logger.Infof("Setting up server...")
 
 	// Setup Gin
-	s.ginEngine = gin.Default()
+	s.ginEngine = gin.New()
+	if err := s.ginEngine.SetTrustedProxies(s.cfg.App.TrustedProxyList()); err != nil {
+		return err
+	}
+	s.ginEngine.Use(middleware.RequestID())
+	s.ginEngine.Use(otelgin.Middleware(s.cfg.Tracing.ServiceName, otelgin.WithFilter(middleware.SkipProbes)))
+	s.ginEngine.Use(middleware.ContextLogger())
+	s.ginEngine.Use(middleware.Locale())
+	s.ginEngine.Use(middleware.AccessLog())
+	// Metrics wraps Recovery and ErrorHandler so it records the final status
+	if s.cfg.Metrics.Enabled {
+		s.ginEngine.Use(middleware.Metrics())
+	}
+	s.ginEngine.Use(gin.Recovery())
+	s.ginEngine.Use(middleware.ErrorHandler())
 	s.ginEngine.Use(middleware.CORSMiddleware())
+	s.ginEngine.Use(middleware.RequestMeta())
 
 	// No route handler
 	s.ginEngine.NoRoute(func(ctx *gin.Context) {
-		ctx.JSON(http.StatusNotFound, gin.H{
-			"status":  http.StatusNotFound,
-			"message": "Route Not Found",
-		})
+		response.Fail(ctx, dto.MESSAGE_FAILED_PROSES_REQUEST, dto.ErrRouteNotFound)
 	})
 
 	// Health check
@@ -170,18 +306,29 @@ func (s *Server) Start() error {
 	})
 
 	// Register routes
+	routes.Health(s.ginEngine, s.healthController)
+	if s.cfg.Metrics.Enabled {
+		s.ginEngine.GET("/metrics", middleware.MetricsToken(s.cfg.Metrics.Token), gin.WrapH(metrics.Handler()))
+	}
 	routes.Transaction(s.ginEngine, s.transactionController)
-	routes.User(s.ginEngine, s.userController, s.jwtService)
+	routes.User(s.ginEngine, s.userController, s.jwtService, s.sessionService)
+	routes.TwoFactor(s.ginEngine, s.twoFactorController, s.jwtService, s.sessionService)
+	routes.Admin(s.ginEngine, s.adminUserController, s.roleController, s.auditLogController, s.emailOutboxController, s.jwtService, s.sessionService, s.roleService)
+	routes.WellKnown(s.ginEngine, s.wellKnownController)
+	if s.devMailboxController != nil {
+		routes.DevMailbox(s.ginEngine, s.devMailboxController)
+		logger.Warnf("Mail is captured in memory, browse it at /dev/mailbox")
+	}
 
 	s.ginEngine.Static("/assets", "./assets")
 
+	// Background workers run until the root context is cancelled
+	s.runWorker(s.auditService.RunRetention)
+	s.runWorker(s.emailOutboxService.Run)
+	s.runWorker(s.emailOutboxService.RunRetention)
+
 	// Create HTTP server
-	var addr string
-	if s.env == "localhost" {
-		addr = "127.0.0.1:" + s.port
-	} else {
-		addr = ":" + s.port
-	}
+	addr := s.cfg.App.Address()
 
 	s.httpServer = &http.Server{
 		Addr:    addr,
@@ -190,9 +337,12 @@ func (s *Server) Start() error {
 
 	// Start HTTP server in goroutine
 	go func() {
-		myFigure := figure.NewColorFigure("Backend Boilerplate", "", "blue", true)
-		myFigure.Print()
-		fmt.Printf("Starting server on %s\n", addr)
+		// the banner would break line-per-record JSON log collectors
+		if s.cfg.Log.Format == "text" {
+			myFigure := figure.NewColorFigure("Backend Boilerplate", "", "blue", true)
+			myFigure.Print()
+		}
+		logger.Infof("Starting server on %s", addr)
 
 		if err := s.httpServer.ListenAndServe(); err != nil && err != http.ErrServerClosed {
 			logger.Errorf("Server error: %v", err)
@@ -205,10 +355,20 @@ func (s *Server) Start() error {
 func (s *Server) Stop(ctx context.Context) error {
 	logger.Infof("Starting Graceful Shutdown")
 
-	// Step 1: Cancel root context
+	// Step 1: Report not ready so load balancers stop sending traffic
+	s.healthService.MarkShuttingDown()
+	if delay := s.cfg.App.ShutdownDrainDelay; delay > 0 {
+		logger.Infof("Draining for %s...", delay)
+		select {
+		case <-time.After(delay):
+		case <-ctx.Done():
+		}
+	}
+
+	// Step 2: Cancel root context
 	s.cancelFunc()
 
-	// Step 2: Shutdown HTTP server
+	// Step 3: Shutdown HTTP server
 	logger.Infof("Shutting down HTTP server...")
 	if err := s.httpServer.Shutdown(ctx); err != nil {
 		logger.Errorf("HTTP server shutdown error: %v", err)
@@ -216,13 +376,33 @@ func (s *Server) Stop(ctx context.Context) error {
 	}
 	logger.Infof("HTTP Server stopped")
 
-	// Step 3: Close database connections
-	logger.Infof("Closing database connections...")
-	sqlDB, err := s.db.DB()
-	if err == nil {
-		sqlDB.Close()
+	// Step 4: Let background workers finish, the outbox worker completes the batch it is sending
+	logger.Infof("Waiting for background workers...")
+	workersDone := make(chan struct{})
+	go func() {
+		s.workers.Wait()
+		close(workersDone)
+	}()
+	select {
+	case <-workersDone:
+		logger.Infof("Background workers stopped")
+	case <-ctx.Done():
+		logger.Errorf("Background workers did not stop in time: %v", ctx.Err())
 	}
+
+	// Step 5: Close database connections
+	logger.Infof("Closing database connections...")
+	database.CloseDatabaseConnection(s.db)
 	logger.Infof("Database connections closed")
 	logger.Infof("Graceful Shutdown completed")
 	return nil
 }
+
+// runWorker starts a background job that Stop waits for before closing the database.
+func (s *Server) runWorker(run func(ctx context.Context)) {
+	s.workers.Add(1)
+	go func() {
+		defer s.workers.Done()
+		run(s.rootCTX)
+	}()
+}
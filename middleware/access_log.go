@@ -0,0 +1,120 @@
+package middleware
+
+import (
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/Shabrinashsf/go-gin-gorm-boilerplate/utils/logger"
+	"github.com/gin-gonic/gin"
+)
+
+const REDACTED = "[REDACTED]"
+
+// SENSITIVE_PATHS carry one-time tokens in the query string, their query is
+// never logged.
+var SENSITIVE_PATHS = []string{
+	"/api/auth/unlock",
+	"/api/auth/verify-email",
+	"/api/auth/email/confirm",
+}
+
+// SENSITIVE_QUERY_PARAMS are masked on every other path.
+var SENSITIVE_QUERY_PARAMS = []string{"token", "code", "password", "secret", "signature", "key"}
+
+// SENSITIVE_HEADERS are masked when request headers are logged at debug level.
+var SENSITIVE_HEADERS = []string{"Authorization", "Cookie", "Set-Cookie", "X-Callback-Signature"}
+
+// AccessLog writes one structured line per request through the request-scoped
+// logger, so request_id, user_id and trace_id come along. Server errors log at
+// error level, client errors at warn and probe requests only at debug.
+func AccessLog() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		start := time.Now()
+
+		ctx.Next()
+
+		route := ctx.FullPath()
+		if route == "" {
+			route = "unmatched"
+		}
+		status := ctx.Writer.Status()
+
+		fields := logger.Fields{
+			"method":     ctx.Request.Method,
+			"route":      route,
+			"path":       ctx.Request.URL.Path,
+			"status":     status,
+			"latency_ms": float64(time.Since(start).Microseconds()) / 1000,
+			"bytes":      max(ctx.Writer.Size(), 0),
+			"client_ip":  ctx.ClientIP(),
+			"user_agent": ctx.Request.UserAgent(),
+		}
+		if query := redactQuery(ctx.Request.URL); query != "" {
+			fields["query"] = query
+		}
+		if userId := ctx.GetString("user_id"); userId != "" {
+			fields["user_id"] = userId
+		}
+		if len(ctx.Errors) > 0 {
+			fields["errors"] = ctx.Errors.String()
+		}
+		if logger.DebugEnabled() {
+			fields["headers"] = redactHeaders(ctx.Request.Header)
+		}
+
+		entry := logger.FromContext(ctx.Request.Context()).WithFields(fields)
+		switch {
+		case status >= http.StatusInternalServerError:
+			entry.Error("request completed")
+		case status >= http.StatusBadRequest:
+			entry.Warn("request completed")
+		case isProbe(ctx.Request):
+			entry.Debug("request completed")
+		default:
+			entry.Info("request completed")
+		}
+	}
+}
+
+func isProbe(r *http.Request) bool {
+	return !SkipProbes(r)
+}
+
+func redactQuery(u *url.URL) string {
+	if u.RawQuery == "" {
+		return ""
+	}
+	for _, path := range SENSITIVE_PATHS {
+		if u.Path == path {
+			return REDACTED
+		}
+	}
+
+	values, err := url.ParseQuery(u.RawQuery)
+	if err != nil {
+		return REDACTED
+	}
+	for key := range values {
+		for _, sensitive := range SENSITIVE_QUERY_PARAMS {
+			if strings.EqualFold(key, sensitive) {
+				values[key] = []string{REDACTED}
+			}
+		}
+	}
+	return values.Encode()
+}
+
+func redactHeaders(header http.Header) map[string]string {
+	redacted := make(map[string]string, len(header))
+	for key, values := range header {
+		redacted[key] = strings.Join(values, ", ")
+		for _, sensitive := range SENSITIVE_HEADERS {
+			if strings.EqualFold(key, sensitive) {
+				redacted[key] = REDACTED
+			}
+		}
+	}
+	return redacted
+}
@@ -1,64 +1,83 @@
 package middleware
 
 import (
-	"net/http"
 	"strings"
 
 	"github.com/Shabrinashsf/go-gin-gorm-boilerplate/constants"
 	"github.com/Shabrinashsf/go-gin-gorm-boilerplate/dto"
 	"github.com/Shabrinashsf/go-gin-gorm-boilerplate/service"
+	"github.com/Shabrinashsf/go-gin-gorm-boilerplate/utils/logger"
 	"github.com/Shabrinashsf/go-gin-gorm-boilerplate/utils/response"
 	"github.com/gin-gonic/gin"
 	"github.com/golang-jwt/jwt/v4"
+	"github.com/google/uuid"
 )
 
-func Authenticate(jwtService service.JWTService) gin.HandlerFunc {
+func Authenticate(jwtService service.JWTService, sessionService service.SessionService) gin.HandlerFunc {
 	return func(ctx *gin.Context) {
 		authHeader := ctx.GetHeader("Authorization")
 		if authHeader == "" {
-			response := response.BuildResponseFailed(dto.MESSAGE_FAILED_PROSES_REQUEST, dto.MESSAGE_FAILED_TOKEN_NOT_FOUND, nil)
-			ctx.AbortWithStatusJSON(http.StatusUnauthorized, response)
+			response.Fail(ctx, dto.MESSAGE_FAILED_PROSES_REQUEST, dto.ErrTokenNotFound)
 			return
 		}
 		if !strings.Contains(authHeader, "Bearer ") {
-			response := response.BuildResponseFailed(dto.MESSAGE_FAILED_PROSES_REQUEST, dto.MESSAGE_FAILED_TOKEN_NOT_VALID, nil)
-			ctx.AbortWithStatusJSON(http.StatusUnauthorized, response)
+			response.Fail(ctx, dto.MESSAGE_FAILED_PROSES_REQUEST, dto.ErrTokenNotValid)
 			return
 		}
 		authHeader = strings.Replace(authHeader, "Bearer ", "", -1)
 		token, err := jwtService.ValidateToken(authHeader)
 		if err != nil {
-			response := response.BuildResponseFailed(dto.MESSAGE_FAILED_PROSES_REQUEST, dto.MESSAGE_FAILED_TOKEN_NOT_VALID, nil)
-			ctx.AbortWithStatusJSON(http.StatusUnauthorized, response)
+			response.Fail(ctx, dto.MESSAGE_FAILED_PROSES_REQUEST, dto.ErrTokenNotValid)
 			return
 		}
 		if !token.Valid {
-			response := response.BuildResponseFailed(dto.MESSAGE_FAILED_PROSES_REQUEST, dto.MESSAGE_FAILED_DENIED_ACCESS, nil)
-			ctx.AbortWithStatusJSON(http.StatusUnauthorized, response)
+			response.Fail(ctx, dto.MESSAGE_FAILED_PROSES_REQUEST, dto.ErrDeniedAccess)
 			return
 		}
 		claims, ok := token.Claims.(jwt.MapClaims)
 		if !ok {
-			response := response.BuildResponseFailed(dto.MESSAGE_FAILED_PROSES_REQUEST, dto.MESSAGE_FAILED_TOKEN_NOT_VALID, nil)
-			ctx.AbortWithStatusJSON(http.StatusUnauthorized, response)
+			response.Fail(ctx, dto.MESSAGE_FAILED_PROSES_REQUEST, dto.ErrTokenNotValid)
 			return
 		}
 		roleClaim, _ := claims[constants.CTX_KEY_ROLE_NAME]
 		role, _ := roleClaim.(string)
 		if role == "" {
-			response := response.BuildResponseFailed(dto.MESSAGE_FAILED_PROSES_REQUEST, dto.MESSAGE_FAILED_DENIED_ACCESS, nil)
-			ctx.AbortWithStatusJSON(http.StatusUnauthorized, response)
+			response.Fail(ctx, dto.MESSAGE_FAILED_PROSES_REQUEST, dto.ErrDeniedAccess)
+			return
+		}
+		sessionClaim, _ := claims["sid"].(string)
+		sessionId, err := uuid.Parse(sessionClaim)
+		if err != nil {
+			response.Fail(ctx, dto.MESSAGE_FAILED_PROSES_REQUEST, dto.ErrTokenNotValid)
+			return
+		}
+		if !sessionService.IsSessionActive(ctx.Request.Context(), sessionId) {
+			response.Fail(ctx, dto.MESSAGE_FAILED_PROSES_REQUEST, dto.ErrSessionRevoked)
 			return
 		}
 		userId, err := jwtService.GetUserIDByToken(authHeader)
 		if err != nil {
-			response := response.BuildResponseFailed(dto.MESSAGE_FAILED_PROSES_REQUEST, err.Error(), nil)
-			ctx.AbortWithStatusJSON(http.StatusUnauthorized, response)
+			response.Fail(ctx, dto.MESSAGE_FAILED_PROSES_REQUEST, dto.ErrTokenNotValid.Wrap(err))
 			return
 		}
 		ctx.Set("token", authHeader)
 		ctx.Set("user_id", userId)
 		ctx.Set(constants.CTX_KEY_ROLE_NAME, role)
+		ctx.Set(constants.CTX_KEY_SESSION, sessionId.String())
+		ctx.Set(constants.CTX_KEY_PERMS, claimPermissions(claims))
+		ctx.Request = ctx.Request.WithContext(logger.WithFields(ctx.Request.Context(), logger.Fields{"user_id": userId}))
 		ctx.Next()
 	}
 }
+
+// claimPermissions reads the "perms" claim, tokens issued before it existed carry none.
+func claimPermissions(claims jwt.MapClaims) []string {
+	raw, _ := claims["perms"].([]interface{})
+	permissions := make([]string, 0, len(raw))
+	for _, p := range raw {
+		if permission, ok := p.(string); ok {
+			permissions = append(permissions, permission)
+		}
+	}
+	return permissions
+}
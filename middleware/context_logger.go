@@ -0,0 +1,23 @@
+package middleware
+
+import (
+	"github.com/Shabrinashsf/go-gin-gorm-boilerplate/constants"
+	"github.com/Shabrinashsf/go-gin-gorm-boilerplate/utils/logger"
+	"github.com/gin-gonic/gin"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// ContextLogger attaches a request-scoped logger to the request context.
+// Services log through logger.FromContext(ctx) and every line carries the
+// request_id set by RequestID, plus user_id once Authenticate has run.
+func ContextLogger() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		requestId := ctx.GetString(constants.CTX_KEY_REQUEST_ID)
+
+		reqCtx := logger.WithFields(ctx.Request.Context(), logger.Fields{"request_id": requestId})
+		trace.SpanFromContext(reqCtx).SetAttributes(attribute.String("http.request_id", requestId))
+		ctx.Request = ctx.Request.WithContext(reqCtx)
+		ctx.Next()
+	}
+}
@@ -0,0 +1,58 @@
+package middleware
+
+import (
+	"net/http"
+
+	"github.com/Shabrinashsf/go-gin-gorm-boilerplate/dto"
+	"github.com/Shabrinashsf/go-gin-gorm-boilerplate/utils/i18n"
+	"github.com/Shabrinashsf/go-gin-gorm-boilerplate/utils/logger"
+	"github.com/Shabrinashsf/go-gin-gorm-boilerplate/utils/response"
+	"github.com/Shabrinashsf/go-gin-gorm-boilerplate/utils/validation"
+	"github.com/gin-gonic/gin"
+)
+
+// ErrorHandler renders the last error recorded with response.Fail. Errors
+// that are not a dto.AppError become INTERNAL_ERROR, the original is logged
+// with the request_id the client receives but never leaves the server.
+// The error text comes from the i18n catalog in the request locale, and
+// validation failures are rendered as a list of dto.FieldError.
+func ErrorHandler() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		ctx.Next()
+
+		if len(ctx.Errors) == 0 || ctx.Writer.Written() {
+			return
+		}
+
+		last := ctx.Errors.Last()
+		message, _ := last.Meta.(string)
+		if message == "" {
+			message = dto.MESSAGE_FAILED_PROSES_REQUEST
+		}
+
+		appErr := dto.ToAppError(last.Err)
+		if appErr.Status >= http.StatusInternalServerError {
+			cause := last.Err
+			if appErr.Err != nil {
+				cause = appErr.Err
+			}
+			logger.FromContext(ctx.Request.Context()).
+				WithField("code", appErr.Code).
+				WithError(cause).
+				Error(message)
+		}
+
+		locale := i18n.FromContext(ctx.Request.Context())
+		errorText, ok := i18n.Lookup(locale, appErr.MessageID())
+		if !ok {
+			errorText = appErr.Message
+		}
+
+		res := response.BuildResponseFailed(message, errorText, nil)
+		if fields := validation.FieldErrors(last.Err, locale); len(fields) > 0 {
+			res.Error = fields
+		}
+		res.Code = appErr.Code
+		response.Abort(ctx, appErr.Status, res)
+	}
+}
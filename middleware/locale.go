@@ -0,0 +1,18 @@
+package middleware
+
+import (
+	"github.com/Shabrinashsf/go-gin-gorm-boilerplate/utils/i18n"
+	"github.com/gin-gonic/gin"
+)
+
+// Locale negotiates the response language from Accept-Language and stores it
+// on the request context, where response.JSON and the mail templates read it.
+func Locale() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		locale := i18n.Negotiate(ctx.GetHeader("Accept-Language"))
+
+		ctx.Request = ctx.Request.WithContext(i18n.WithLocale(ctx.Request.Context(), locale))
+		ctx.Header("Content-Language", locale)
+		ctx.Next()
+	}
+}
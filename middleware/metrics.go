@@ -0,0 +1,48 @@
+package middleware
+
+import (
+	"crypto/subtle"
+	"strings"
+	"time"
+
+	"github.com/Shabrinashsf/go-gin-gorm-boilerplate/dto"
+	"github.com/Shabrinashsf/go-gin-gorm-boilerplate/utils/metrics"
+	"github.com/Shabrinashsf/go-gin-gorm-boilerplate/utils/response"
+	"github.com/gin-gonic/gin"
+)
+
+// Metrics records the latency and status of every request. The route label
+// is the registered path pattern, never the raw URL, to keep cardinality low.
+func Metrics() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		start := time.Now()
+		metrics.HTTPRequestStarted()
+		defer metrics.HTTPRequestFinished()
+
+		ctx.Next()
+
+		route := ctx.FullPath()
+		if route == "" {
+			route = "unmatched"
+		}
+		metrics.ObserveHTTPRequest(ctx.Request.Method, route, ctx.Writer.Status(), time.Since(start))
+	}
+}
+
+// MetricsToken protects /metrics with a static bearer token when one is configured.
+func MetricsToken(token string) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		if token == "" {
+			ctx.Next()
+			return
+		}
+
+		given := strings.TrimPrefix(ctx.GetHeader("Authorization"), "Bearer ")
+		if subtle.ConstantTimeCompare([]byte(given), []byte(token)) != 1 {
+			response.Fail(ctx, dto.MESSAGE_FAILED_PROSES_REQUEST, dto.ErrTokenNotValid)
+			return
+		}
+
+		ctx.Next()
+	}
+}
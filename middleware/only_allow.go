@@ -1,9 +1,6 @@
 package middleware
 
 import (
-	"fmt"
-	"net/http"
-
 	"github.com/Shabrinashsf/go-gin-gorm-boilerplate/constants"
 	"github.com/Shabrinashsf/go-gin-gorm-boilerplate/dto"
 	"github.com/Shabrinashsf/go-gin-gorm-boilerplate/utils/response"
@@ -21,8 +18,6 @@ func OnlyAllow(roles ...string) gin.HandlerFunc {
 			}
 		}
 
-		err := fmt.Sprintf(dto.ErrRoleNotAllowed.Error(), userRole)
-		response := response.BuildResponseFailed(dto.MESSAGE_FAILED_TOKEN_NOT_VALID, err, nil)
-		ctx.AbortWithStatusJSON(http.StatusUnauthorized, response)
+		response.Fail(ctx, dto.MESSAGE_FAILED_PROSES_REQUEST, dto.ErrRoleNotAllowed)
 	}
 }
@@ -0,0 +1,31 @@
+package middleware
+
+import (
+	"regexp"
+
+	"github.com/Shabrinashsf/go-gin-gorm-boilerplate/constants"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+const REQUEST_ID_HEADER = "X-Request-ID"
+
+// REQUEST_ID_PATTERN limits what a caller may send as X-Request-ID, the value
+// ends up in logs and response headers verbatim.
+var REQUEST_ID_PATTERN = regexp.MustCompile(`^[A-Za-z0-9._:-]{1,128}$`)
+
+// RequestID reuses the caller's X-Request-ID when it is well formed and
+// generates one otherwise. The ID is echoed in the response header and in the
+// body of failed responses.
+func RequestID() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		requestId := ctx.GetHeader(REQUEST_ID_HEADER)
+		if !REQUEST_ID_PATTERN.MatchString(requestId) {
+			requestId = uuid.NewString()
+		}
+
+		ctx.Set(constants.CTX_KEY_REQUEST_ID, requestId)
+		ctx.Header(REQUEST_ID_HEADER, requestId)
+		ctx.Next()
+	}
+}
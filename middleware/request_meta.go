@@ -0,0 +1,19 @@
+package middleware
+
+import (
+	"github.com/Shabrinashsf/go-gin-gorm-boilerplate/helpers"
+	"github.com/gin-gonic/gin"
+)
+
+// RequestMeta stores the client IP and user agent on the request context so
+// services can read them without extra parameters.
+func RequestMeta() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		reqCtx := helpers.WithRequestMeta(ctx.Request.Context(), helpers.RequestMeta{
+			IPAddress: ctx.ClientIP(),
+			UserAgent: ctx.Request.UserAgent(),
+		})
+		ctx.Request = ctx.Request.WithContext(reqCtx)
+		ctx.Next()
+	}
+}
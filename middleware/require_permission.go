@@ -0,0 +1,37 @@
+package middleware
+
+import (
+	"github.com/Shabrinashsf/go-gin-gorm-boilerplate/dto"
+	"github.com/Shabrinashsf/go-gin-gorm-boilerplate/service"
+	"github.com/Shabrinashsf/go-gin-gorm-boilerplate/utils/response"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// RequirePermission must run after Authenticate. Permissions are looked up
+// through the role service cache rather than the token claim, so revoking a
+// role takes effect without waiting for the access token to expire.
+func RequirePermission(roleService service.RoleService, permissions ...string) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		userId, err := uuid.Parse(ctx.GetString("user_id"))
+		if err != nil {
+			response.Fail(ctx, dto.MESSAGE_FAILED_PROSES_REQUEST, dto.ErrDeniedAccess)
+			return
+		}
+
+		granted, err := roleService.GetUserPermissions(ctx.Request.Context(), userId)
+		if err != nil {
+			response.Fail(ctx, dto.MESSAGE_FAILED_PROSES_REQUEST, err)
+			return
+		}
+
+		for _, permission := range permissions {
+			if !service.MatchPermission(granted, permission) {
+				response.Fail(ctx, dto.MESSAGE_FAILED_PROSES_REQUEST, dto.ErrPermissionDenied)
+				return
+			}
+		}
+
+		ctx.Next()
+	}
+}
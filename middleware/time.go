@@ -1,7 +1,6 @@
 package middleware
 
 import (
-	"net/http"
 	"time"
 
 	"github.com/Shabrinashsf/go-gin-gorm-boilerplate/dto"
@@ -14,15 +13,13 @@ func NotBefore(limit string) gin.HandlerFunc {
 	return func(ctx *gin.Context) {
 		waktu, err := time.Parse("2006-01-02 15:04:05", limit)
 		if err != nil {
-			response := response.BuildResponseFailed(dto.MESSAGE_FAILED_PROSES_REQUEST, dto.MESSAGE_FAILED_PARSE_TIME, nil)
-			ctx.AbortWithStatusJSON(http.StatusInternalServerError, response)
+			response.Fail(ctx, dto.MESSAGE_FAILED_PROSES_REQUEST, err)
 			return
 		}
 
 		now := time.Now()
 		if now.Before(waktu) {
-			response := response.BuildResponseFailed(dto.PESAN_DILUAR_MASA_REGISTRASI, dto.MESSAGE_FAILED_PROSES_REQUEST, nil)
-			ctx.AbortWithStatusJSON(http.StatusBadRequest, response)
+			response.Fail(ctx, dto.MESSAGE_FAILED_PROSES_REQUEST, dto.ErrOutsideTimeFrame)
 			return
 		}
 
@@ -35,15 +32,13 @@ func NotAfter(limit string) gin.HandlerFunc {
 	return func(ctx *gin.Context) {
 		waktu, err := time.Parse("2006-01-02 15:04:05", limit)
 		if err != nil {
-			response := response.BuildResponseFailed(dto.MESSAGE_FAILED_PROSES_REQUEST, dto.MESSAGE_FAILED_PARSE_TIME, nil)
-			ctx.AbortWithStatusJSON(http.StatusInternalServerError, response)
+			response.Fail(ctx, dto.MESSAGE_FAILED_PROSES_REQUEST, err)
 			return
 		}
 
 		now := time.Now()
 		if now.After(waktu) {
-			response := response.BuildResponseFailed(dto.PESAN_DILUAR_MASA_REGISTRASI, dto.PESAN_DILUAR_MASA_REGISTRASI, nil)
-			ctx.AbortWithStatusJSON(http.StatusBadRequest, response)
+			response.Fail(ctx, dto.MESSAGE_FAILED_PROSES_REQUEST, dto.ErrOutsideTimeFrame)
 			return
 		}
 
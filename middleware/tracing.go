@@ -0,0 +1,16 @@
+package middleware
+
+import "net/http"
+
+// PROBE_PATHS are polled every few seconds and would drown real requests in traces.
+var PROBE_PATHS = []string{"/healthz", "/readyz", "/metrics"}
+
+// SkipProbes is an otelgin filter that returns false for probe and scrape requests.
+func SkipProbes(r *http.Request) bool {
+	for _, path := range PROBE_PATHS {
+		if r.URL.Path == path {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,86 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/Shabrinashsf/go-gin-gorm-boilerplate/dto"
+	"github.com/Shabrinashsf/go-gin-gorm-boilerplate/entity"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type (
+	AccountUnlockTokenRepository interface {
+		CreateAccountUnlockToken(ctx context.Context, tx *gorm.DB, token entity.AccountUnlockToken) (entity.AccountUnlockToken, error)
+		GetAccountUnlockTokenByHash(ctx context.Context, tx *gorm.DB, tokenHash string) (entity.AccountUnlockToken, error)
+		ConsumeAccountUnlockToken(ctx context.Context, tx *gorm.DB, id uuid.UUID) (bool, error)
+		ConsumeUserAccountUnlockTokens(ctx context.Context, tx *gorm.DB, userId uuid.UUID) error
+	}
+
+	accountUnlockTokenRepository struct {
+		db *gorm.DB
+	}
+)
+
+func NewAccountUnlockTokenRepository(db *gorm.DB) AccountUnlockTokenRepository {
+	return &accountUnlockTokenRepository{
+		db: db,
+	}
+}
+
+func (r *accountUnlockTokenRepository) CreateAccountUnlockToken(ctx context.Context, tx *gorm.DB, token entity.AccountUnlockToken) (entity.AccountUnlockToken, error) {
+	if tx == nil {
+		tx = r.db
+	}
+
+	if err := tx.WithContext(ctx).Create(&token).Error; err != nil {
+		return entity.AccountUnlockToken{}, err
+	}
+
+	return token, nil
+}
+
+func (r *accountUnlockTokenRepository) GetAccountUnlockTokenByHash(ctx context.Context, tx *gorm.DB, tokenHash string) (entity.AccountUnlockToken, error) {
+	if tx == nil {
+		tx = r.db
+	}
+
+	var token entity.AccountUnlockToken
+	if err := tx.WithContext(ctx).Where("token_hash = ?", tokenHash).First(&token).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return entity.AccountUnlockToken{}, dto.ErrTokenInvalid
+		}
+		return entity.AccountUnlockToken{}, err
+	}
+
+	return token, nil
+}
+
+// ConsumeAccountUnlockToken marks the token as used and reports whether this
+// call was the one that consumed it, so concurrent requests cannot both win.
+func (r *accountUnlockTokenRepository) ConsumeAccountUnlockToken(ctx context.Context, tx *gorm.DB, id uuid.UUID) (bool, error) {
+	if tx == nil {
+		tx = r.db
+	}
+
+	result := tx.WithContext(ctx).Model(&entity.AccountUnlockToken{}).
+		Where("id = ? AND consumed_at IS NULL", id).
+		Update("consumed_at", time.Now())
+	if result.Error != nil {
+		return false, result.Error
+	}
+
+	return result.RowsAffected == 1, nil
+}
+
+func (r *accountUnlockTokenRepository) ConsumeUserAccountUnlockTokens(ctx context.Context, tx *gorm.DB, userId uuid.UUID) error {
+	if tx == nil {
+		tx = r.db
+	}
+
+	return tx.WithContext(ctx).Model(&entity.AccountUnlockToken{}).
+		Where("user_id = ? AND consumed_at IS NULL", userId).
+		Update("consumed_at", time.Now()).Error
+}
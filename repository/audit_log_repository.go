@@ -0,0 +1,110 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/Shabrinashsf/go-gin-gorm-boilerplate/dto"
+	"github.com/Shabrinashsf/go-gin-gorm-boilerplate/entity"
+	"github.com/Shabrinashsf/go-gin-gorm-boilerplate/utils/pagination"
+	"gorm.io/gorm"
+)
+
+type (
+	AuditLogRepository interface {
+		CreateAuditLog(ctx context.Context, tx *gorm.DB, auditLog entity.AuditLog) error
+		GetAllAuditLogsWithPagination(ctx context.Context, tx *gorm.DB, filter dto.AuditLogFilter, meta pagination.Meta) ([]entity.AuditLog, int64, error)
+		DeleteAuditLogsBefore(ctx context.Context, tx *gorm.DB, before time.Time) (int64, error)
+	}
+
+	auditLogRepository struct {
+		db *gorm.DB
+	}
+)
+
+func NewAuditLogRepository(db *gorm.DB) AuditLogRepository {
+	return &auditLogRepository{
+		db: db,
+	}
+}
+
+// auditLogSortColumns whitelists the columns accepted in the sort_by query parameter.
+var auditLogSortColumns = map[string]string{
+	"created_at": "created_at",
+	"action":     "action",
+	"actor_id":   "actor_id",
+}
+
+func (r *auditLogRepository) CreateAuditLog(ctx context.Context, tx *gorm.DB, auditLog entity.AuditLog) error {
+	if tx == nil {
+		tx = r.db
+	}
+
+	return tx.WithContext(ctx).Create(&auditLog).Error
+}
+
+func (r *auditLogRepository) GetAllAuditLogsWithPagination(ctx context.Context, tx *gorm.DB, filter dto.AuditLogFilter, meta pagination.Meta) ([]entity.AuditLog, int64, error) {
+	if tx == nil {
+		tx = r.db
+	}
+
+	query := tx.WithContext(ctx).Scopes(ReadOnly).Model(&entity.AuditLog{})
+	if filter.ActorID != "" {
+		query = query.Where("actor_id = ?", filter.ActorID)
+	}
+	if filter.Action != "" {
+		query = query.Where("action LIKE ?", strings.TrimSuffix(filter.Action, "*")+"%")
+	}
+	if filter.TargetType != "" {
+		query = query.Where("target_type = ?", filter.TargetType)
+	}
+	if filter.TargetID != "" {
+		query = query.Where("target_id = ?", filter.TargetID)
+	}
+	if filter.IPAddress != "" {
+		query = query.Where("ip_address = ?", filter.IPAddress)
+	}
+	if filter.Success != nil {
+		query = query.Where("success = ?", *filter.Success)
+	}
+	if !filter.From.IsZero() {
+		query = query.Where("created_at >= ?", filter.From)
+	}
+	if !filter.To.IsZero() {
+		query = query.Where("created_at < ?", filter.To.AddDate(0, 0, 1))
+	}
+
+	var count int64
+	if err := query.Count(&count).Error; err != nil {
+		return nil, 0, err
+	}
+
+	sortBy, ok := auditLogSortColumns[meta.SortBy]
+	if !ok {
+		sortBy = "created_at"
+	}
+	sort := "DESC"
+	if ok && strings.EqualFold(meta.Sort, "asc") {
+		sort = "ASC"
+	}
+
+	var auditLogs []entity.AuditLog
+	if err := query.Order(fmt.Sprintf("%s %s", sortBy, sort)).
+		Scopes(Paginate(meta.Page, meta.Take)).
+		Find(&auditLogs).Error; err != nil {
+		return nil, 0, err
+	}
+
+	return auditLogs, count, nil
+}
+
+func (r *auditLogRepository) DeleteAuditLogsBefore(ctx context.Context, tx *gorm.DB, before time.Time) (int64, error) {
+	if tx == nil {
+		tx = r.db
+	}
+
+	result := tx.WithContext(ctx).Where("created_at < ?", before).Delete(&entity.AuditLog{})
+	return result.RowsAffected, result.Error
+}
@@ -1,6 +1,10 @@
 package repository
 
-import "gorm.io/gorm"
+import (
+	"github.com/Shabrinashsf/go-gin-gorm-boilerplate/constants"
+	"gorm.io/gorm"
+	"gorm.io/plugin/dbresolver"
+)
 
 func Paginate(page, perPage int) func(db *gorm.DB) *gorm.DB {
 	return func(db *gorm.DB) *gorm.DB {
@@ -8,3 +12,10 @@ func Paginate(page, perPage int) func(db *gorm.DB) *gorm.DB {
 		return db.Offset(offset).Limit(perPage)
 	}
 }
+
+// ReadOnly sends the query to a read replica when DB_REPLICA_HOSTS is set.
+// Use it for listings that tolerate replication lag; inside a transaction the
+// query stays on the transaction's connection.
+func ReadOnly(db *gorm.DB) *gorm.DB {
+	return db.Clauses(dbresolver.Use(constants.DB_RESOLVER_REPLICA))
+}
@@ -0,0 +1,199 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/Shabrinashsf/go-gin-gorm-boilerplate/dto"
+	"github.com/Shabrinashsf/go-gin-gorm-boilerplate/entity"
+	"github.com/Shabrinashsf/go-gin-gorm-boilerplate/utils/pagination"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type (
+	EmailOutboxRepository interface {
+		CreateEmail(ctx context.Context, tx *gorm.DB, email entity.EmailOutbox) (entity.EmailOutbox, error)
+		ClaimDueEmails(ctx context.Context, limit int, lease time.Duration) ([]entity.EmailOutbox, error)
+		UpdateEmail(ctx context.Context, tx *gorm.DB, id uuid.UUID, updates map[string]interface{}) (entity.EmailOutbox, error)
+		DeleteEmailsBefore(ctx context.Context, tx *gorm.DB, status entity.EmailStatus, before time.Time) (int64, error)
+		CountEmailsByStatus(ctx context.Context, tx *gorm.DB) (map[entity.EmailStatus]int64, error)
+		GetEmailByID(ctx context.Context, tx *gorm.DB, id uuid.UUID) (entity.EmailOutbox, error)
+		GetAllEmailsWithPagination(ctx context.Context, tx *gorm.DB, filter dto.EmailOutboxFilter, meta pagination.Meta) ([]entity.EmailOutbox, int64, error)
+	}
+
+	emailOutboxRepository struct {
+		db *gorm.DB
+	}
+)
+
+func NewEmailOutboxRepository(db *gorm.DB) EmailOutboxRepository {
+	return &emailOutboxRepository{
+		db: db,
+	}
+}
+
+// emailOutboxSortColumns whitelists the columns accepted in the sort_by query parameter.
+var emailOutboxSortColumns = map[string]string{
+	"created_at":      "created_at",
+	"next_attempt_at": "next_attempt_at",
+	"attempts":        "attempts",
+}
+
+func (r *emailOutboxRepository) CreateEmail(ctx context.Context, tx *gorm.DB, email entity.EmailOutbox) (entity.EmailOutbox, error) {
+	if tx == nil {
+		tx = r.db
+	}
+
+	if err := tx.WithContext(ctx).Create(&email).Error; err != nil {
+		return entity.EmailOutbox{}, err
+	}
+
+	return email, nil
+}
+
+// ClaimDueEmails picks up to limit pending emails whose next attempt is due,
+// counts the attempt and pushes next_attempt_at past the lease. Other workers
+// skip the locked rows, and an email whose sender dies mid-send becomes due
+// again once the lease runs out.
+func (r *emailOutboxRepository) ClaimDueEmails(ctx context.Context, limit int, lease time.Duration) ([]entity.EmailOutbox, error) {
+	var emails []entity.EmailOutbox
+
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		now := time.Now()
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+			Where("status = ? AND next_attempt_at <= ?", entity.EmailStatusPending, now).
+			Order("next_attempt_at ASC").
+			Limit(limit).
+			Find(&emails).Error; err != nil {
+			return err
+		}
+
+		if len(emails) == 0 {
+			return nil
+		}
+
+		ids := make([]uuid.UUID, 0, len(emails))
+		for i := range emails {
+			ids = append(ids, emails[i].ID)
+			emails[i].Attempts++
+			emails[i].NextAttemptAt = now.Add(lease)
+		}
+
+		return tx.Model(&entity.EmailOutbox{}).Where("id IN ?", ids).Updates(map[string]interface{}{
+			"attempts":        gorm.Expr("attempts + 1"),
+			"next_attempt_at": now.Add(lease),
+		}).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return emails, nil
+}
+
+func (r *emailOutboxRepository) UpdateEmail(ctx context.Context, tx *gorm.DB, id uuid.UUID, updates map[string]interface{}) (entity.EmailOutbox, error) {
+	if tx == nil {
+		tx = r.db
+	}
+
+	if err := tx.WithContext(ctx).Model(&entity.EmailOutbox{}).Where("id = ?", id).Updates(&updates).Error; err != nil {
+		return entity.EmailOutbox{}, err
+	}
+
+	var email entity.EmailOutbox
+	if err := tx.WithContext(ctx).Where("id = ?", id).First(&email).Error; err != nil {
+		return entity.EmailOutbox{}, err
+	}
+
+	return email, nil
+}
+
+func (r *emailOutboxRepository) GetEmailByID(ctx context.Context, tx *gorm.DB, id uuid.UUID) (entity.EmailOutbox, error) {
+	if tx == nil {
+		tx = r.db
+	}
+
+	var email entity.EmailOutbox
+	if err := tx.WithContext(ctx).Where("id = ?", id).First(&email).Error; err != nil {
+		return entity.EmailOutbox{}, err
+	}
+
+	return email, nil
+}
+
+func (r *emailOutboxRepository) GetAllEmailsWithPagination(ctx context.Context, tx *gorm.DB, filter dto.EmailOutboxFilter, meta pagination.Meta) ([]entity.EmailOutbox, int64, error) {
+	if tx == nil {
+		tx = r.db
+	}
+
+	query := tx.WithContext(ctx).Scopes(ReadOnly).Model(&entity.EmailOutbox{})
+	if filter.Status != "" {
+		query = query.Where("status = ?", filter.Status)
+	}
+	if filter.ToEmail != "" {
+		query = query.Where("LOWER(to_email) = ?", strings.ToLower(filter.ToEmail))
+	}
+
+	var count int64
+	if err := query.Count(&count).Error; err != nil {
+		return nil, 0, err
+	}
+
+	sortBy, ok := emailOutboxSortColumns[meta.SortBy]
+	if !ok {
+		sortBy = "created_at"
+	}
+	sort := "DESC"
+	if ok && strings.EqualFold(meta.Sort, "asc") {
+		sort = "ASC"
+	}
+
+	// the message can carry attachments, the list never shows it
+	var emails []entity.EmailOutbox
+	if err := query.Omit("message").Order(fmt.Sprintf("%s %s", sortBy, sort)).
+		Scopes(Paginate(meta.Page, meta.Take)).
+		Find(&emails).Error; err != nil {
+		return nil, 0, err
+	}
+
+	return emails, count, nil
+}
+
+// DeleteEmailsBefore removes email in status last updated before the given time.
+func (r *emailOutboxRepository) DeleteEmailsBefore(ctx context.Context, tx *gorm.DB, status entity.EmailStatus, before time.Time) (int64, error) {
+	if tx == nil {
+		tx = r.db
+	}
+
+	result := tx.WithContext(ctx).Where("status = ? AND updated_at < ?", status, before).Delete(&entity.EmailOutbox{})
+	return result.RowsAffected, result.Error
+}
+
+// CountEmailsByStatus returns the number of email in every status present in the outbox.
+func (r *emailOutboxRepository) CountEmailsByStatus(ctx context.Context, tx *gorm.DB) (map[entity.EmailStatus]int64, error) {
+	if tx == nil {
+		tx = r.db
+	}
+
+	var rows []struct {
+		Status entity.EmailStatus
+		Count  int64
+	}
+	if err := tx.WithContext(ctx).Scopes(ReadOnly).Model(&entity.EmailOutbox{}).
+		Select("status, COUNT(*) AS count").
+		Group("status").
+		Scan(&rows).Error; err != nil {
+		return nil, err
+	}
+
+	counts := make(map[entity.EmailStatus]int64, len(rows))
+	for _, row := range rows {
+		counts[row.Status] = row.Count
+	}
+
+	return counts, nil
+}
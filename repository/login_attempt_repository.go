@@ -0,0 +1,157 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/Shabrinashsf/go-gin-gorm-boilerplate/entity"
+	"gorm.io/gorm"
+)
+
+type (
+	// LoginAttemptRepository counts failed logins per key. Counters are not part
+	// of any business transaction, so unlike other repositories it takes no tx.
+	LoginAttemptRepository interface {
+		GetLoginAttempt(ctx context.Context, key string) (entity.LoginAttempt, error)
+		IncrementLoginAttempt(ctx context.Context, key string, window time.Duration) (entity.LoginAttempt, error)
+		LockLoginAttempt(ctx context.Context, key string, until time.Time) error
+		ResetLoginAttempt(ctx context.Context, key string) error
+	}
+
+	// loginAttemptRepository shares counters between instances through Postgres.
+	loginAttemptRepository struct {
+		db *gorm.DB
+	}
+
+	// memoryLoginAttemptRepository keeps counters in process, suitable for a single instance.
+	memoryLoginAttemptRepository struct {
+		mu       sync.Mutex
+		attempts map[string]entity.LoginAttempt
+	}
+)
+
+func NewLoginAttemptRepository(db *gorm.DB) LoginAttemptRepository {
+	return &loginAttemptRepository{
+		db: db,
+	}
+}
+
+func NewMemoryLoginAttemptRepository() LoginAttemptRepository {
+	return &memoryLoginAttemptRepository{
+		attempts: make(map[string]entity.LoginAttempt),
+	}
+}
+
+func (r *loginAttemptRepository) GetLoginAttempt(ctx context.Context, key string) (entity.LoginAttempt, error) {
+	var attempt entity.LoginAttempt
+	if err := r.db.WithContext(ctx).Where("key = ?", key).First(&attempt).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return entity.LoginAttempt{Key: key}, nil
+		}
+		return entity.LoginAttempt{}, err
+	}
+
+	return attempt, nil
+}
+
+func (r *loginAttemptRepository) IncrementLoginAttempt(ctx context.Context, key string, window time.Duration) (entity.LoginAttempt, error) {
+	now := time.Now()
+
+	var attempt entity.LoginAttempt
+	err := r.db.WithContext(ctx).Raw(`
+		INSERT INTO login_attempts (key, failures, last_failure_at, updated_at)
+		VALUES (?, 1, ?, ?)
+		ON CONFLICT (key) DO UPDATE SET
+			failures = CASE WHEN login_attempts.last_failure_at < ? THEN 1 ELSE login_attempts.failures + 1 END,
+			last_failure_at = EXCLUDED.last_failure_at,
+			updated_at = EXCLUDED.updated_at
+		RETURNING *`, key, now, now, now.Add(-window)).Scan(&attempt).Error
+	if err != nil {
+		return entity.LoginAttempt{}, err
+	}
+
+	return attempt, nil
+}
+
+func (r *loginAttemptRepository) LockLoginAttempt(ctx context.Context, key string, until time.Time) error {
+	return r.db.WithContext(ctx).Model(&entity.LoginAttempt{}).Where("key = ?", key).Update("locked_until", until).Error
+}
+
+func (r *loginAttemptRepository) ResetLoginAttempt(ctx context.Context, key string) error {
+	return r.db.WithContext(ctx).Where("key = ?", key).Delete(&entity.LoginAttempt{}).Error
+}
+
+func (r *memoryLoginAttemptRepository) GetLoginAttempt(ctx context.Context, key string) (entity.LoginAttempt, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	attempt, ok := r.attempts[key]
+	if !ok {
+		return entity.LoginAttempt{Key: key}, nil
+	}
+
+	return attempt, nil
+}
+
+func (r *memoryLoginAttemptRepository) IncrementLoginAttempt(ctx context.Context, key string, window time.Duration) (entity.LoginAttempt, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	now := time.Now()
+	attempt, ok := r.attempts[key]
+	if !ok || attempt.LastFailureAt.Before(now.Add(-window)) {
+		attempt.Key = key
+		attempt.Failures = 0
+	}
+
+	attempt.Failures++
+	attempt.LastFailureAt = now
+	attempt.UpdatedAt = now
+	r.attempts[key] = attempt
+
+	r.prune(now, window)
+
+	return attempt, nil
+}
+
+func (r *memoryLoginAttemptRepository) LockLoginAttempt(ctx context.Context, key string, until time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	attempt, ok := r.attempts[key]
+	if !ok {
+		attempt = entity.LoginAttempt{Key: key}
+	}
+
+	attempt.LockedUntil = &until
+	attempt.UpdatedAt = time.Now()
+	r.attempts[key] = attempt
+
+	return nil
+}
+
+func (r *memoryLoginAttemptRepository) ResetLoginAttempt(ctx context.Context, key string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	delete(r.attempts, key)
+
+	return nil
+}
+
+// prune drops counters that are outside the window and not locked so the map
+// does not grow without bound under a spray of random emails or IPs.
+func (r *memoryLoginAttemptRepository) prune(now time.Time, window time.Duration) {
+	if len(r.attempts) < 10000 {
+		return
+	}
+
+	for key, attempt := range r.attempts {
+		locked := attempt.LockedUntil != nil && attempt.LockedUntil.After(now)
+		if !locked && attempt.LastFailureAt.Before(now.Add(-window)) {
+			delete(r.attempts, key)
+		}
+	}
+}
@@ -0,0 +1,86 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/Shabrinashsf/go-gin-gorm-boilerplate/dto"
+	"github.com/Shabrinashsf/go-gin-gorm-boilerplate/entity"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type (
+	PasswordResetTokenRepository interface {
+		CreatePasswordResetToken(ctx context.Context, tx *gorm.DB, token entity.PasswordResetToken) (entity.PasswordResetToken, error)
+		GetPasswordResetTokenByHash(ctx context.Context, tx *gorm.DB, tokenHash string) (entity.PasswordResetToken, error)
+		ConsumePasswordResetToken(ctx context.Context, tx *gorm.DB, id uuid.UUID) (bool, error)
+		ConsumeUserPasswordResetTokens(ctx context.Context, tx *gorm.DB, userId uuid.UUID) error
+	}
+
+	passwordResetTokenRepository struct {
+		db *gorm.DB
+	}
+)
+
+func NewPasswordResetTokenRepository(db *gorm.DB) PasswordResetTokenRepository {
+	return &passwordResetTokenRepository{
+		db: db,
+	}
+}
+
+func (r *passwordResetTokenRepository) CreatePasswordResetToken(ctx context.Context, tx *gorm.DB, token entity.PasswordResetToken) (entity.PasswordResetToken, error) {
+	if tx == nil {
+		tx = r.db
+	}
+
+	if err := tx.WithContext(ctx).Create(&token).Error; err != nil {
+		return entity.PasswordResetToken{}, err
+	}
+
+	return token, nil
+}
+
+func (r *passwordResetTokenRepository) GetPasswordResetTokenByHash(ctx context.Context, tx *gorm.DB, tokenHash string) (entity.PasswordResetToken, error) {
+	if tx == nil {
+		tx = r.db
+	}
+
+	var token entity.PasswordResetToken
+	if err := tx.WithContext(ctx).Where("token_hash = ?", tokenHash).First(&token).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return entity.PasswordResetToken{}, dto.ErrTokenInvalid
+		}
+		return entity.PasswordResetToken{}, err
+	}
+
+	return token, nil
+}
+
+// ConsumePasswordResetToken marks the token as used and reports whether this
+// call was the one that consumed it, so concurrent requests cannot both win.
+func (r *passwordResetTokenRepository) ConsumePasswordResetToken(ctx context.Context, tx *gorm.DB, id uuid.UUID) (bool, error) {
+	if tx == nil {
+		tx = r.db
+	}
+
+	result := tx.WithContext(ctx).Model(&entity.PasswordResetToken{}).
+		Where("id = ? AND consumed_at IS NULL", id).
+		Update("consumed_at", time.Now())
+	if result.Error != nil {
+		return false, result.Error
+	}
+
+	return result.RowsAffected == 1, nil
+}
+
+func (r *passwordResetTokenRepository) ConsumeUserPasswordResetTokens(ctx context.Context, tx *gorm.DB, userId uuid.UUID) error {
+	if tx == nil {
+		tx = r.db
+	}
+
+	return tx.WithContext(ctx).Model(&entity.PasswordResetToken{}).
+		Where("user_id = ? AND consumed_at IS NULL", userId).
+		Update("consumed_at", time.Now()).Error
+}
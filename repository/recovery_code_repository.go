@@ -0,0 +1,72 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/Shabrinashsf/go-gin-gorm-boilerplate/entity"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type (
+	RecoveryCodeRepository interface {
+		ReplaceRecoveryCodes(ctx context.Context, tx *gorm.DB, userId uuid.UUID, codeHashes []string) error
+		UseRecoveryCode(ctx context.Context, tx *gorm.DB, userId uuid.UUID, codeHash string) (bool, error)
+		DeleteRecoveryCodes(ctx context.Context, tx *gorm.DB, userId uuid.UUID) error
+	}
+
+	recoveryCodeRepository struct {
+		db *gorm.DB
+	}
+)
+
+func NewRecoveryCodeRepository(db *gorm.DB) RecoveryCodeRepository {
+	return &recoveryCodeRepository{
+		db: db,
+	}
+}
+
+func (r *recoveryCodeRepository) ReplaceRecoveryCodes(ctx context.Context, tx *gorm.DB, userId uuid.UUID, codeHashes []string) error {
+	if tx == nil {
+		tx = r.db
+	}
+
+	if err := tx.WithContext(ctx).Unscoped().Where("user_id = ?", userId).Delete(&entity.RecoveryCode{}).Error; err != nil {
+		return err
+	}
+
+	codes := make([]entity.RecoveryCode, 0, len(codeHashes))
+	for _, hash := range codeHashes {
+		codes = append(codes, entity.RecoveryCode{
+			UserID:   userId,
+			CodeHash: hash,
+		})
+	}
+
+	return tx.WithContext(ctx).Create(&codes).Error
+}
+
+// UseRecoveryCode consumes a matching unused code and reports whether one was found.
+func (r *recoveryCodeRepository) UseRecoveryCode(ctx context.Context, tx *gorm.DB, userId uuid.UUID, codeHash string) (bool, error) {
+	if tx == nil {
+		tx = r.db
+	}
+
+	result := tx.WithContext(ctx).Model(&entity.RecoveryCode{}).
+		Where("user_id = ? AND code_hash = ? AND used_at IS NULL", userId, codeHash).
+		Update("used_at", time.Now())
+	if result.Error != nil {
+		return false, result.Error
+	}
+
+	return result.RowsAffected > 0, nil
+}
+
+func (r *recoveryCodeRepository) DeleteRecoveryCodes(ctx context.Context, tx *gorm.DB, userId uuid.UUID) error {
+	if tx == nil {
+		tx = r.db
+	}
+
+	return tx.WithContext(ctx).Unscoped().Where("user_id = ?", userId).Delete(&entity.RecoveryCode{}).Error
+}
@@ -0,0 +1,119 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/Shabrinashsf/go-gin-gorm-boilerplate/dto"
+	"github.com/Shabrinashsf/go-gin-gorm-boilerplate/entity"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type (
+	RefreshTokenRepository interface {
+		CreateRefreshToken(ctx context.Context, tx *gorm.DB, token entity.RefreshToken) (entity.RefreshToken, error)
+		GetRefreshTokenByHash(ctx context.Context, tx *gorm.DB, tokenHash string) (entity.RefreshToken, error)
+		RevokeRefreshToken(ctx context.Context, tx *gorm.DB, id uuid.UUID, replacedBy *uuid.UUID) (bool, error)
+		RevokeSession(ctx context.Context, tx *gorm.DB, sessionId uuid.UUID) error
+		RevokeUserSessions(ctx context.Context, tx *gorm.DB, userId uuid.UUID, exceptSessionId *uuid.UUID) error
+		IsSessionActive(ctx context.Context, tx *gorm.DB, sessionId uuid.UUID) (bool, error)
+	}
+
+	refreshTokenRepository struct {
+		db *gorm.DB
+	}
+)
+
+func NewRefreshTokenRepository(db *gorm.DB) RefreshTokenRepository {
+	return &refreshTokenRepository{
+		db: db,
+	}
+}
+
+func (r *refreshTokenRepository) CreateRefreshToken(ctx context.Context, tx *gorm.DB, token entity.RefreshToken) (entity.RefreshToken, error) {
+	if tx == nil {
+		tx = r.db
+	}
+
+	if err := tx.WithContext(ctx).Create(&token).Error; err != nil {
+		return entity.RefreshToken{}, err
+	}
+
+	return token, nil
+}
+
+func (r *refreshTokenRepository) GetRefreshTokenByHash(ctx context.Context, tx *gorm.DB, tokenHash string) (entity.RefreshToken, error) {
+	if tx == nil {
+		tx = r.db
+	}
+
+	var token entity.RefreshToken
+	if err := tx.WithContext(ctx).Where("token_hash = ?", tokenHash).First(&token).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return entity.RefreshToken{}, dto.ErrRefreshTokenInvalid
+		}
+		return entity.RefreshToken{}, err
+	}
+
+	return token, nil
+}
+
+// RevokeRefreshToken revokes the token and reports whether this call was the
+// one that revoked it, so two refreshes with the same token cannot both win.
+func (r *refreshTokenRepository) RevokeRefreshToken(ctx context.Context, tx *gorm.DB, id uuid.UUID, replacedBy *uuid.UUID) (bool, error) {
+	if tx == nil {
+		tx = r.db
+	}
+
+	updates := map[string]interface{}{
+		"revoked_at":  time.Now(),
+		"replaced_by": replacedBy,
+	}
+
+	result := tx.WithContext(ctx).Model(&entity.RefreshToken{}).Where("id = ? AND revoked_at IS NULL", id).Updates(updates)
+	if result.Error != nil {
+		return false, result.Error
+	}
+
+	return result.RowsAffected == 1, nil
+}
+
+func (r *refreshTokenRepository) RevokeSession(ctx context.Context, tx *gorm.DB, sessionId uuid.UUID) error {
+	if tx == nil {
+		tx = r.db
+	}
+
+	return tx.WithContext(ctx).Model(&entity.RefreshToken{}).
+		Where("session_id = ? AND revoked_at IS NULL", sessionId).
+		Update("revoked_at", time.Now()).Error
+}
+
+func (r *refreshTokenRepository) RevokeUserSessions(ctx context.Context, tx *gorm.DB, userId uuid.UUID, exceptSessionId *uuid.UUID) error {
+	if tx == nil {
+		tx = r.db
+	}
+
+	query := tx.WithContext(ctx).Model(&entity.RefreshToken{}).Where("user_id = ? AND revoked_at IS NULL", userId)
+	if exceptSessionId != nil {
+		query = query.Where("session_id <> ?", *exceptSessionId)
+	}
+
+	return query.Update("revoked_at", time.Now()).Error
+}
+
+func (r *refreshTokenRepository) IsSessionActive(ctx context.Context, tx *gorm.DB, sessionId uuid.UUID) (bool, error) {
+	if tx == nil {
+		tx = r.db
+	}
+
+	var count int64
+	if err := tx.WithContext(ctx).Model(&entity.RefreshToken{}).
+		Where("session_id = ? AND revoked_at IS NULL AND expires_at > ?", sessionId, time.Now()).
+		Count(&count).Error; err != nil {
+		return false, err
+	}
+
+	return count > 0, nil
+}
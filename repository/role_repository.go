@@ -0,0 +1,213 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"github.com/Shabrinashsf/go-gin-gorm-boilerplate/dto"
+	"github.com/Shabrinashsf/go-gin-gorm-boilerplate/entity"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type (
+	RoleRepository interface {
+		GetAllRoles(ctx context.Context, tx *gorm.DB) ([]entity.Role, error)
+		GetRoleByID(ctx context.Context, tx *gorm.DB, id uuid.UUID) (entity.Role, error)
+		GetRolesByNames(ctx context.Context, tx *gorm.DB, names []string) ([]entity.Role, error)
+		CreateRole(ctx context.Context, tx *gorm.DB, role entity.Role) (entity.Role, error)
+		UpdateRole(ctx context.Context, tx *gorm.DB, role entity.Role) (entity.Role, error)
+		DeleteRole(ctx context.Context, tx *gorm.DB, id uuid.UUID) error
+		GetAllPermissions(ctx context.Context, tx *gorm.DB) ([]entity.Permission, error)
+		GetPermissionsByNames(ctx context.Context, tx *gorm.DB, names []string) ([]entity.Permission, error)
+		CreatePermission(ctx context.Context, tx *gorm.DB, permission entity.Permission) (entity.Permission, error)
+		ReplaceUserRoles(ctx context.Context, tx *gorm.DB, userId uuid.UUID, roles []entity.Role) error
+		GetUserRoleNames(ctx context.Context, tx *gorm.DB, userId uuid.UUID) ([]string, error)
+		GetUserPermissionNames(ctx context.Context, tx *gorm.DB, userId uuid.UUID, primaryRole string) ([]string, error)
+	}
+
+	roleRepository struct {
+		db *gorm.DB
+	}
+)
+
+func NewRoleRepository(db *gorm.DB) RoleRepository {
+	return &roleRepository{
+		db: db,
+	}
+}
+
+func (r *roleRepository) GetAllRoles(ctx context.Context, tx *gorm.DB) ([]entity.Role, error) {
+	if tx == nil {
+		tx = r.db
+	}
+
+	var roles []entity.Role
+	if err := tx.WithContext(ctx).Scopes(ReadOnly).Preload("Permissions").Order("name ASC").Find(&roles).Error; err != nil {
+		return nil, err
+	}
+
+	return roles, nil
+}
+
+func (r *roleRepository) GetRoleByID(ctx context.Context, tx *gorm.DB, id uuid.UUID) (entity.Role, error) {
+	if tx == nil {
+		tx = r.db
+	}
+
+	var role entity.Role
+	if err := tx.WithContext(ctx).Preload("Permissions").Where("id = ?", id).First(&role).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return entity.Role{}, dto.ErrRoleNotFound
+		}
+		return entity.Role{}, err
+	}
+
+	return role, nil
+}
+
+func (r *roleRepository) GetRolesByNames(ctx context.Context, tx *gorm.DB, names []string) ([]entity.Role, error) {
+	if tx == nil {
+		tx = r.db
+	}
+
+	var roles []entity.Role
+	if err := tx.WithContext(ctx).Where("name IN ?", names).Find(&roles).Error; err != nil {
+		return nil, err
+	}
+
+	return roles, nil
+}
+
+func (r *roleRepository) CreateRole(ctx context.Context, tx *gorm.DB, role entity.Role) (entity.Role, error) {
+	if tx == nil {
+		tx = r.db
+	}
+
+	if err := tx.WithContext(ctx).Create(&role).Error; err != nil {
+		return entity.Role{}, err
+	}
+
+	return role, nil
+}
+
+// UpdateRole saves the description and replaces the permission set with role.Permissions.
+func (r *roleRepository) UpdateRole(ctx context.Context, tx *gorm.DB, role entity.Role) (entity.Role, error) {
+	if tx == nil {
+		tx = r.db
+	}
+
+	if err := tx.WithContext(ctx).Model(&role).Update("description", role.Description).Error; err != nil {
+		return entity.Role{}, err
+	}
+
+	if err := tx.WithContext(ctx).Model(&role).Association("Permissions").Replace(role.Permissions); err != nil {
+		return entity.Role{}, err
+	}
+
+	return role, nil
+}
+
+func (r *roleRepository) DeleteRole(ctx context.Context, tx *gorm.DB, id uuid.UUID) error {
+	if tx == nil {
+		tx = r.db
+	}
+
+	if err := tx.WithContext(ctx).Exec("DELETE FROM user_roles WHERE role_id = ?", id).Error; err != nil {
+		return err
+	}
+
+	if err := tx.WithContext(ctx).Exec("DELETE FROM role_permissions WHERE role_id = ?", id).Error; err != nil {
+		return err
+	}
+
+	// Hard delete so the name can be reused
+	return tx.WithContext(ctx).Unscoped().Where("id = ?", id).Delete(&entity.Role{}).Error
+}
+
+func (r *roleRepository) GetAllPermissions(ctx context.Context, tx *gorm.DB) ([]entity.Permission, error) {
+	if tx == nil {
+		tx = r.db
+	}
+
+	var permissions []entity.Permission
+	if err := tx.WithContext(ctx).Scopes(ReadOnly).Order("name ASC").Find(&permissions).Error; err != nil {
+		return nil, err
+	}
+
+	return permissions, nil
+}
+
+func (r *roleRepository) GetPermissionsByNames(ctx context.Context, tx *gorm.DB, names []string) ([]entity.Permission, error) {
+	if tx == nil {
+		tx = r.db
+	}
+
+	var permissions []entity.Permission
+	if err := tx.WithContext(ctx).Where("name IN ?", names).Find(&permissions).Error; err != nil {
+		return nil, err
+	}
+
+	return permissions, nil
+}
+
+func (r *roleRepository) CreatePermission(ctx context.Context, tx *gorm.DB, permission entity.Permission) (entity.Permission, error) {
+	if tx == nil {
+		tx = r.db
+	}
+
+	if err := tx.WithContext(ctx).Create(&permission).Error; err != nil {
+		return entity.Permission{}, err
+	}
+
+	return permission, nil
+}
+
+func (r *roleRepository) ReplaceUserRoles(ctx context.Context, tx *gorm.DB, userId uuid.UUID, roles []entity.Role) error {
+	if tx == nil {
+		tx = r.db
+	}
+
+	user := entity.User{ID: userId}
+	return tx.WithContext(ctx).Model(&user).Association("Roles").Replace(roles)
+}
+
+func (r *roleRepository) GetUserRoleNames(ctx context.Context, tx *gorm.DB, userId uuid.UUID) ([]string, error) {
+	if tx == nil {
+		tx = r.db
+	}
+
+	var names []string
+	if err := tx.WithContext(ctx).Table("roles").
+		Select("roles.name").
+		Joins("JOIN user_roles ON user_roles.role_id = roles.id").
+		Where("user_roles.user_id = ? AND roles.deleted_at IS NULL", userId).
+		Order("roles.name ASC").
+		Scan(&names).Error; err != nil {
+		return nil, err
+	}
+
+	return names, nil
+}
+
+// GetUserPermissionNames resolves permissions from the assigned roles plus the
+// role named after the user's primary UserRole.
+func (r *roleRepository) GetUserPermissionNames(ctx context.Context, tx *gorm.DB, userId uuid.UUID, primaryRole string) ([]string, error) {
+	if tx == nil {
+		tx = r.db
+	}
+
+	var names []string
+	if err := tx.WithContext(ctx).Raw(`
+		SELECT DISTINCT permissions.name
+		FROM permissions
+		JOIN role_permissions ON role_permissions.permission_id = permissions.id
+		JOIN roles ON roles.id = role_permissions.role_id AND roles.deleted_at IS NULL
+		WHERE permissions.deleted_at IS NULL
+		  AND (roles.name = ? OR roles.id IN (SELECT role_id FROM user_roles WHERE user_id = ?))
+		ORDER BY permissions.name`, primaryRole, userId).Scan(&names).Error; err != nil {
+		return nil, err
+	}
+
+	return names, nil
+}
@@ -0,0 +1,101 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/Shabrinashsf/go-gin-gorm-boilerplate/dto"
+	"github.com/Shabrinashsf/go-gin-gorm-boilerplate/entity"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type (
+	TwoFactorChallengeRepository interface {
+		CreateChallenge(ctx context.Context, tx *gorm.DB, challenge entity.TwoFactorChallenge) (entity.TwoFactorChallenge, error)
+		GetChallengeByID(ctx context.Context, tx *gorm.DB, id uuid.UUID) (entity.TwoFactorChallenge, error)
+		FailChallenge(ctx context.Context, tx *gorm.DB, id uuid.UUID, maxFailures int) (entity.TwoFactorChallenge, error)
+		ConsumeChallenge(ctx context.Context, tx *gorm.DB, id uuid.UUID) (bool, error)
+	}
+
+	twoFactorChallengeRepository struct {
+		db *gorm.DB
+	}
+)
+
+func NewTwoFactorChallengeRepository(db *gorm.DB) TwoFactorChallengeRepository {
+	return &twoFactorChallengeRepository{
+		db: db,
+	}
+}
+
+func (r *twoFactorChallengeRepository) CreateChallenge(ctx context.Context, tx *gorm.DB, challenge entity.TwoFactorChallenge) (entity.TwoFactorChallenge, error) {
+	if tx == nil {
+		tx = r.db
+	}
+
+	if err := tx.WithContext(ctx).Create(&challenge).Error; err != nil {
+		return entity.TwoFactorChallenge{}, err
+	}
+
+	return challenge, nil
+}
+
+func (r *twoFactorChallengeRepository) GetChallengeByID(ctx context.Context, tx *gorm.DB, id uuid.UUID) (entity.TwoFactorChallenge, error) {
+	if tx == nil {
+		tx = r.db
+	}
+
+	var challenge entity.TwoFactorChallenge
+	if err := tx.WithContext(ctx).Where("id = ?", id).First(&challenge).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return entity.TwoFactorChallenge{}, dto.ErrChallengeTokenInvalid
+		}
+		return entity.TwoFactorChallenge{}, err
+	}
+
+	return challenge, nil
+}
+
+// FailChallenge counts a wrong code against a challenge the caller consumed
+// to check it, and reopens it for another attempt until maxFailures is
+// reached. Consuming first keeps parallel guesses from overshooting.
+func (r *twoFactorChallengeRepository) FailChallenge(ctx context.Context, tx *gorm.DB, id uuid.UUID, maxFailures int) (entity.TwoFactorChallenge, error) {
+	if tx == nil {
+		tx = r.db
+	}
+
+	now := time.Now()
+
+	var challenge entity.TwoFactorChallenge
+	err := tx.WithContext(ctx).Raw(`
+		UPDATE two_factor_challenges SET
+			failures = failures + 1,
+			consumed_at = CASE WHEN failures + 1 >= ? THEN consumed_at ELSE NULL END,
+			updated_at = ?
+		WHERE id = ? AND consumed_at IS NOT NULL
+		RETURNING *`, maxFailures, now, id).Scan(&challenge).Error
+	if err != nil {
+		return entity.TwoFactorChallenge{}, err
+	}
+
+	return challenge, nil
+}
+
+// ConsumeChallenge marks the challenge as used and reports whether this call
+// was the one that consumed it, so concurrent requests cannot both win.
+func (r *twoFactorChallengeRepository) ConsumeChallenge(ctx context.Context, tx *gorm.DB, id uuid.UUID) (bool, error) {
+	if tx == nil {
+		tx = r.db
+	}
+
+	result := tx.WithContext(ctx).Model(&entity.TwoFactorChallenge{}).
+		Where("id = ? AND consumed_at IS NULL", id).
+		Update("consumed_at", time.Now())
+	if result.Error != nil {
+		return false, result.Error
+	}
+
+	return result.RowsAffected == 1, nil
+}
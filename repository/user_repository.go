@@ -3,9 +3,12 @@ package repository
 import (
 	"context"
 	"errors"
+	"fmt"
+	"strings"
 
 	"github.com/Shabrinashsf/go-gin-gorm-boilerplate/dto"
 	"github.com/Shabrinashsf/go-gin-gorm-boilerplate/entity"
+	"github.com/Shabrinashsf/go-gin-gorm-boilerplate/utils/pagination"
 	"github.com/google/uuid"
 	"gorm.io/gorm"
 )
@@ -16,7 +19,13 @@ type (
 		UpdateUser(ctx context.Context, tx *gorm.DB, id uuid.UUID, updates map[string]interface{}) (entity.User, error)
 		GetUserByID(ctx context.Context, tx *gorm.DB, id uuid.UUID) (entity.User, error)
 		GetUserByEmail(ctx context.Context, tx *gorm.DB, email string) (entity.User, bool, error)
+		GetUserByPendingEmailToken(ctx context.Context, tx *gorm.DB, tokenHash string) (entity.User, error)
 		ResetPassword(ctx context.Context, email, hashedPassword string) error
+		GetAllUsersWithPagination(ctx context.Context, tx *gorm.DB, filter dto.AdminUserFilter, meta pagination.Meta) ([]entity.User, int64, error)
+		GetUserByIDUnscoped(ctx context.Context, tx *gorm.DB, id uuid.UUID) (entity.User, error)
+		SoftDeleteUser(ctx context.Context, tx *gorm.DB, id uuid.UUID) error
+		RestoreUser(ctx context.Context, tx *gorm.DB, id uuid.UUID) error
+		UseTwoFactorStep(ctx context.Context, tx *gorm.DB, id uuid.UUID, step int64) (bool, error)
 	}
 
 	userRepository struct {
@@ -85,6 +94,22 @@ func (r *userRepository) GetUserByEmail(ctx context.Context, tx *gorm.DB, email
 	return user, true, nil
 }
 
+func (r *userRepository) GetUserByPendingEmailToken(ctx context.Context, tx *gorm.DB, tokenHash string) (entity.User, error) {
+	if tx == nil {
+		tx = r.db
+	}
+
+	var user entity.User
+	if err := tx.WithContext(ctx).Where("pending_email_token_hash = ?", tokenHash).First(&user).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return entity.User{}, dto.ErrTokenInvalid
+		}
+		return entity.User{}, err
+	}
+
+	return user, nil
+}
+
 func (r *userRepository) ResetPassword(ctx context.Context, email, hashedPassword string) error {
 	var user entity.User
 	if err := r.db.WithContext(ctx).Where("email = ?", email).First(&user).Error; err != nil {
@@ -99,3 +124,115 @@ func (r *userRepository) ResetPassword(ctx context.Context, email, hashedPasswor
 	}
 	return nil
 }
+
+// userSortColumns whitelists the columns the admin list can be sorted by, the
+// value ends up in ORDER BY so it must never come straight from the query string.
+var userSortColumns = map[string]string{
+	"id":         "id",
+	"name":       "name",
+	"email":      "email",
+	"role":       "role",
+	"instansi":   "instansi",
+	"created_at": "created_at",
+	"updated_at": "updated_at",
+}
+
+func (r *userRepository) GetAllUsersWithPagination(ctx context.Context, tx *gorm.DB, filter dto.AdminUserFilter, meta pagination.Meta) ([]entity.User, int64, error) {
+	if tx == nil {
+		tx = r.db
+	}
+
+	query := tx.WithContext(ctx).Scopes(ReadOnly).Model(&entity.User{})
+	if filter.Deleted {
+		query = query.Unscoped().Where("deleted_at IS NOT NULL")
+	}
+
+	if filter.Search != "" {
+		search := "%" + strings.ToLower(filter.Search) + "%"
+		query = query.Where("LOWER(name) LIKE ? OR LOWER(email) LIKE ?", search, search)
+	}
+	if filter.Role != "" {
+		query = query.Where("role = ?", filter.Role)
+	}
+	if filter.IsVerified != nil {
+		query = query.Where("is_verified = ?", *filter.IsVerified)
+	}
+	if filter.Suspended != nil {
+		query = query.Where("is_suspended = ?", *filter.Suspended)
+	}
+	if filter.Instansi != "" {
+		query = query.Where("LOWER(instansi) LIKE ?", "%"+strings.ToLower(filter.Instansi)+"%")
+	}
+
+	var count int64
+	if err := query.Count(&count).Error; err != nil {
+		return nil, 0, err
+	}
+
+	sortBy, ok := userSortColumns[meta.SortBy]
+	if !ok {
+		sortBy = "created_at"
+	}
+	sort := "ASC"
+	if strings.EqualFold(meta.Sort, "desc") {
+		sort = "DESC"
+	}
+
+	var users []entity.User
+	if err := query.Order(fmt.Sprintf("%s %s", sortBy, sort)).
+		Scopes(Paginate(meta.Page, meta.Take)).
+		Find(&users).Error; err != nil {
+		return nil, 0, err
+	}
+
+	return users, count, nil
+}
+
+func (r *userRepository) GetUserByIDUnscoped(ctx context.Context, tx *gorm.DB, id uuid.UUID) (entity.User, error) {
+	if tx == nil {
+		tx = r.db
+	}
+
+	var user entity.User
+	if err := tx.WithContext(ctx).Unscoped().Where("id = ?", id).First(&user).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return entity.User{}, dto.ErrUserNotFound
+		}
+		return entity.User{}, err
+	}
+
+	return user, nil
+}
+
+func (r *userRepository) SoftDeleteUser(ctx context.Context, tx *gorm.DB, id uuid.UUID) error {
+	if tx == nil {
+		tx = r.db
+	}
+
+	return tx.WithContext(ctx).Where("id = ?", id).Delete(&entity.User{}).Error
+}
+
+func (r *userRepository) RestoreUser(ctx context.Context, tx *gorm.DB, id uuid.UUID) error {
+	if tx == nil {
+		tx = r.db
+	}
+
+	return tx.WithContext(ctx).Unscoped().Model(&entity.User{}).Where("id = ?", id).Update("deleted_at", nil).Error
+}
+
+// UseTwoFactorStep records step as the last accepted TOTP time step and
+// reports false when it is not newer than the stored one, i.e. a replayed code.
+func (r *userRepository) UseTwoFactorStep(ctx context.Context, tx *gorm.DB, id uuid.UUID, step int64) (bool, error) {
+	if tx == nil {
+		tx = r.db
+	}
+
+	result := tx.WithContext(ctx).Model(&entity.User{}).
+		Where("id = ? AND (two_factor_last_step IS NULL OR two_factor_last_step < ?)", id, step).
+		Update("two_factor_last_step", step)
+	if result.Error != nil {
+		return false, result.Error
+	}
+
+	return result.RowsAffected == 1, nil
+}
@@ -0,0 +1,42 @@
+package routes
+
+import (
+	"github.com/Shabrinashsf/go-gin-gorm-boilerplate/constants"
+	"github.com/Shabrinashsf/go-gin-gorm-boilerplate/controller"
+	"github.com/Shabrinashsf/go-gin-gorm-boilerplate/middleware"
+	"github.com/Shabrinashsf/go-gin-gorm-boilerplate/service"
+	"github.com/gin-gonic/gin"
+)
+
+func Admin(route *gin.Engine, adminUserController controller.AdminUserController, roleController controller.RoleController, auditLogController controller.AuditLogController, emailOutboxController controller.EmailOutboxController, jwtService service.JWTService, sessionService service.SessionService, roleService service.RoleService) {
+	routes := route.Group("/api/admin", middleware.Authenticate(jwtService, sessionService))
+	{
+		users := routes.Group("/users", middleware.OnlyAllow(constants.ENUM_ROLE_ADMIN))
+		users.GET("", adminUserController.GetAllUsers)
+		users.GET("/:id", adminUserController.GetUserByID)
+		users.PATCH("/:id/role", adminUserController.ChangeRole)
+		users.PATCH("/:id/verify", adminUserController.VerifyUser)
+		users.PATCH("/:id/suspend", adminUserController.SuspendUser)
+		users.PATCH("/:id/unsuspend", adminUserController.UnsuspendUser)
+		users.DELETE("/:id", adminUserController.DeleteUser)
+		users.PATCH("/:id/restore", adminUserController.RestoreUser)
+		users.PUT("/:id/roles", middleware.RequirePermission(roleService, "roles:assign"), roleController.AssignUserRoles)
+
+		roles := routes.Group("/roles", middleware.RequirePermission(roleService, "roles:manage"))
+		roles.GET("", roleController.GetAllRoles)
+		roles.POST("", roleController.CreateRole)
+		roles.PUT("/:id", roleController.UpdateRole)
+		roles.DELETE("/:id", roleController.DeleteRole)
+
+		permissions := routes.Group("/permissions", middleware.RequirePermission(roleService, "roles:manage"))
+		permissions.GET("", roleController.GetAllPermissions)
+		permissions.POST("", roleController.CreatePermission)
+
+		routes.GET("/audit-logs", middleware.RequirePermission(roleService, "audit:read"), auditLogController.GetAuditLogs)
+
+		emails := routes.Group("/emails", middleware.RequirePermission(roleService, "emails:manage"))
+		emails.GET("", emailOutboxController.GetEmails)
+		emails.GET("/:id", emailOutboxController.GetEmailByID)
+		emails.POST("/:id/resend", emailOutboxController.ResendEmail)
+	}
+}
@@ -0,0 +1,16 @@
+package routes
+
+import (
+	"github.com/Shabrinashsf/go-gin-gorm-boilerplate/controller"
+	"github.com/gin-gonic/gin"
+)
+
+func DevMailbox(route *gin.Engine, devMailboxController controller.DevMailboxController) {
+	routes := route.Group("/dev/mailbox")
+	{
+		routes.GET("", devMailboxController.GetMessages)
+		routes.DELETE("", devMailboxController.ClearMessages)
+		routes.GET("/:id", devMailboxController.GetMessage)
+		routes.GET("/:id/preview", devMailboxController.PreviewMessage)
+	}
+}
@@ -0,0 +1,11 @@
+package routes
+
+import (
+	"github.com/Shabrinashsf/go-gin-gorm-boilerplate/controller"
+	"github.com/gin-gonic/gin"
+)
+
+func Health(route *gin.Engine, healthController controller.HealthController) {
+	route.GET("/healthz", healthController.Liveness)
+	route.GET("/readyz", healthController.Readiness)
+}
@@ -0,0 +1,22 @@
+package routes
+
+import (
+	"github.com/Shabrinashsf/go-gin-gorm-boilerplate/controller"
+	"github.com/Shabrinashsf/go-gin-gorm-boilerplate/middleware"
+	"github.com/Shabrinashsf/go-gin-gorm-boilerplate/service"
+	"github.com/gin-gonic/gin"
+)
+
+func TwoFactor(route *gin.Engine, twoFactorController controller.TwoFactorController, jwtService service.JWTService, sessionService service.SessionService) {
+	routes := route.Group("/api/auth/2fa")
+	{
+		// Second login step, authenticated by the challenge token returned from /login
+		routes.POST("/verify", twoFactorController.Verify)
+		routes.POST("/enroll", twoFactorController.EnrollSetup)
+		routes.POST("/enroll/confirm", twoFactorController.EnrollConfirm)
+
+		routes.POST("/setup", middleware.Authenticate(jwtService, sessionService), twoFactorController.Setup)
+		routes.POST("/confirm", middleware.Authenticate(jwtService, sessionService), twoFactorController.Confirm)
+		routes.POST("/disable", middleware.Authenticate(jwtService, sessionService), twoFactorController.Disable)
+	}
+}
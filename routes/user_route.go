@@ -7,16 +7,22 @@ import (
 	"github.com/gin-gonic/gin"
 )
 
-func User(route *gin.Engine, userController controller.UserController, jwtService service.JWTService) {
+func User(route *gin.Engine, userController controller.UserController, jwtService service.JWTService, sessionService service.SessionService) {
 	routes := route.Group("api/auth")
 	{
 		routes.POST("", userController.RegisterUser)
 		routes.POST("/login", userController.Login)
+		routes.POST("/refresh", userController.RefreshToken)
+		routes.GET("/unlock", userController.UnlockAccount)
+		routes.POST("/logout", middleware.Authenticate(jwtService, sessionService), userController.Logout)
 		routes.POST("/send-verification-email", userController.SendVerificationEmail)
 		routes.GET("/verify-email", userController.VerifyEmail)
 		routes.POST("/forgot-password", userController.ForgotPassword)
 		routes.POST("/reset-password", userController.ResetPassword)
-		routes.GET("/me", middleware.Authenticate(jwtService), userController.MeAuth)
-		routes.PATCH("/update", middleware.Authenticate(jwtService), userController.UpdateUser)
+		routes.GET("/me", middleware.Authenticate(jwtService, sessionService), userController.MeAuth)
+		routes.PATCH("/update", middleware.Authenticate(jwtService, sessionService), userController.UpdateUser)
+		routes.PATCH("/password", middleware.Authenticate(jwtService, sessionService), userController.ChangePassword)
+		routes.PATCH("/email", middleware.Authenticate(jwtService, sessionService), userController.ChangeEmail)
+		routes.GET("/email/confirm", userController.ConfirmEmailChange)
 	}
 }
@@ -0,0 +1,13 @@
+package routes
+
+import (
+	"github.com/Shabrinashsf/go-gin-gorm-boilerplate/controller"
+	"github.com/gin-gonic/gin"
+)
+
+func WellKnown(route *gin.Engine, wellKnownController controller.WellKnownController) {
+	routes := route.Group("/.well-known")
+	{
+		routes.GET("/jwks.json", wellKnownController.JWKS)
+	}
+}
@@ -0,0 +1,267 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/Shabrinashsf/go-gin-gorm-boilerplate/dto"
+	"github.com/Shabrinashsf/go-gin-gorm-boilerplate/entity"
+	"github.com/Shabrinashsf/go-gin-gorm-boilerplate/repository"
+	"github.com/Shabrinashsf/go-gin-gorm-boilerplate/utils/pagination"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type (
+	AdminUserService interface {
+		GetAllUsers(ctx context.Context, filter dto.AdminUserFilter, meta pagination.Meta) (dto.AdminUserPaginationResponse, error)
+		GetUserByID(ctx context.Context, userId uuid.UUID) (dto.AdminUserResponse, error)
+		ChangeRole(ctx context.Context, actorId uuid.UUID, userId uuid.UUID, req dto.ChangeRoleRequest) (dto.AdminUserResponse, error)
+		VerifyUser(ctx context.Context, actorId uuid.UUID, userId uuid.UUID) (dto.AdminUserResponse, error)
+		SuspendUser(ctx context.Context, actorId uuid.UUID, userId uuid.UUID) (dto.AdminUserResponse, error)
+		UnsuspendUser(ctx context.Context, actorId uuid.UUID, userId uuid.UUID) (dto.AdminUserResponse, error)
+		DeleteUser(ctx context.Context, actorId uuid.UUID, userId uuid.UUID) error
+		RestoreUser(ctx context.Context, actorId uuid.UUID, userId uuid.UUID) (dto.AdminUserResponse, error)
+	}
+
+	adminUserService struct {
+		userRepository repository.UserRepository
+		sessionService SessionService
+		roleService    RoleService
+		auditService   AuditService
+		db             *gorm.DB
+	}
+)
+
+func NewAdminUserService(ur repository.UserRepository, ss SessionService, rs RoleService, as AuditService, db *gorm.DB) AdminUserService {
+	return &adminUserService{
+		userRepository: ur,
+		sessionService: ss,
+		roleService:    rs,
+		auditService:   as,
+		db:             db,
+	}
+}
+
+func (s *adminUserService) GetAllUsers(ctx context.Context, filter dto.AdminUserFilter, meta pagination.Meta) (dto.AdminUserPaginationResponse, error) {
+	meta.GetSkipAndLimit()
+
+	users, count, err := s.userRepository.GetAllUsersWithPagination(ctx, nil, filter, meta)
+	if err != nil {
+		return dto.AdminUserPaginationResponse{}, err
+	}
+
+	meta.Count(int(count))
+
+	data := make([]dto.AdminUserResponse, 0, len(users))
+	for _, user := range users {
+		data = append(data, toAdminUserResponse(user))
+	}
+
+	return dto.AdminUserPaginationResponse{
+		Data: data,
+		Meta: meta,
+	}, nil
+}
+
+func (s *adminUserService) GetUserByID(ctx context.Context, userId uuid.UUID) (dto.AdminUserResponse, error) {
+	user, err := s.userRepository.GetUserByIDUnscoped(ctx, nil, userId)
+	if err != nil {
+		return dto.AdminUserResponse{}, err
+	}
+
+	return toAdminUserResponse(user), nil
+}
+
+func (s *adminUserService) ChangeRole(ctx context.Context, actorId uuid.UUID, userId uuid.UUID, req dto.ChangeRoleRequest) (dto.AdminUserResponse, error) {
+	role := entity.UserRole(req.Role)
+	if role != entity.RoleAdmin && role != entity.RoleUser {
+		return dto.AdminUserResponse{}, dto.ErrInvalidRole
+	}
+
+	if actorId == userId {
+		return dto.AdminUserResponse{}, dto.ErrCannotModifySelf
+	}
+
+	user, err := s.userRepository.GetUserByID(ctx, nil, userId)
+	if err != nil {
+		return dto.AdminUserResponse{}, dto.ErrUserNotFound
+	}
+
+	updates := map[string]interface{}{}
+	updates["role"] = role
+
+	updatedUser, err := s.updateAndRevoke(ctx, userId, updates)
+	if err != nil {
+		return dto.AdminUserResponse{}, err
+	}
+
+	s.record(ctx, actorId, entity.AuditActionAdminChangeRole, userId, auditChanges(map[string]any{"role": string(user.Role)}, map[string]any{"role": string(role)}))
+	return toAdminUserResponse(updatedUser), nil
+}
+
+func (s *adminUserService) VerifyUser(ctx context.Context, actorId uuid.UUID, userId uuid.UUID) (dto.AdminUserResponse, error) {
+	user, err := s.userRepository.GetUserByID(ctx, nil, userId)
+	if err != nil {
+		return dto.AdminUserResponse{}, dto.ErrUserNotFound
+	}
+
+	if user.IsVerified {
+		return dto.AdminUserResponse{}, dto.ErrAccountAlreadyVerified
+	}
+
+	updates := map[string]interface{}{}
+	updates["is_verified"] = true
+
+	updatedUser, err := s.userRepository.UpdateUser(ctx, nil, userId, updates)
+	if err != nil {
+		return dto.AdminUserResponse{}, dto.ErrUpdateUser.Wrap(err)
+	}
+
+	s.record(ctx, actorId, entity.AuditActionAdminVerifyUser, userId, auditChanges(map[string]any{"is_verified": false}, map[string]any{"is_verified": true}))
+	return toAdminUserResponse(updatedUser), nil
+}
+
+func (s *adminUserService) SuspendUser(ctx context.Context, actorId uuid.UUID, userId uuid.UUID) (dto.AdminUserResponse, error) {
+	if actorId == userId {
+		return dto.AdminUserResponse{}, dto.ErrCannotModifySelf
+	}
+
+	user, err := s.userRepository.GetUserByID(ctx, nil, userId)
+	if err != nil {
+		return dto.AdminUserResponse{}, dto.ErrUserNotFound
+	}
+
+	if user.IsSuspended {
+		return dto.AdminUserResponse{}, dto.ErrAccountSuspended
+	}
+
+	updates := map[string]interface{}{}
+	updates["is_suspended"] = true
+
+	updatedUser, err := s.updateAndRevoke(ctx, userId, updates)
+	if err != nil {
+		return dto.AdminUserResponse{}, err
+	}
+
+	s.record(ctx, actorId, entity.AuditActionAdminSuspendUser, userId, auditChanges(map[string]any{"is_suspended": false}, map[string]any{"is_suspended": true}))
+	return toAdminUserResponse(updatedUser), nil
+}
+
+func (s *adminUserService) UnsuspendUser(ctx context.Context, actorId uuid.UUID, userId uuid.UUID) (dto.AdminUserResponse, error) {
+	user, err := s.userRepository.GetUserByID(ctx, nil, userId)
+	if err != nil {
+		return dto.AdminUserResponse{}, dto.ErrUserNotFound
+	}
+
+	if !user.IsSuspended {
+		return dto.AdminUserResponse{}, dto.ErrAccountNotSuspended
+	}
+
+	updates := map[string]interface{}{}
+	updates["is_suspended"] = false
+
+	updatedUser, err := s.userRepository.UpdateUser(ctx, nil, userId, updates)
+	if err != nil {
+		return dto.AdminUserResponse{}, dto.ErrUpdateUser.Wrap(err)
+	}
+
+	s.record(ctx, actorId, entity.AuditActionAdminUnsuspendUser, userId, auditChanges(map[string]any{"is_suspended": true}, map[string]any{"is_suspended": false}))
+	return toAdminUserResponse(updatedUser), nil
+}
+
+func (s *adminUserService) DeleteUser(ctx context.Context, actorId uuid.UUID, userId uuid.UUID) error {
+	if actorId == userId {
+		return dto.ErrCannotModifySelf
+	}
+
+	if _, err := s.userRepository.GetUserByID(ctx, nil, userId); err != nil {
+		return dto.ErrUserNotFound
+	}
+
+	if err := s.userRepository.SoftDeleteUser(ctx, nil, userId); err != nil {
+		return err
+	}
+
+	s.roleService.InvalidateUser(userId)
+	s.record(ctx, actorId, entity.AuditActionAdminDeleteUser, userId, auditChanges(map[string]any{"deleted": false}, map[string]any{"deleted": true}))
+
+	return s.sessionService.RevokeUserSessions(ctx, userId, nil)
+}
+
+func (s *adminUserService) RestoreUser(ctx context.Context, actorId uuid.UUID, userId uuid.UUID) (dto.AdminUserResponse, error) {
+	user, err := s.userRepository.GetUserByIDUnscoped(ctx, nil, userId)
+	if err != nil {
+		return dto.AdminUserResponse{}, err
+	}
+
+	if !user.DeletedAt.Valid {
+		return dto.AdminUserResponse{}, dto.ErrUserNotDeleted
+	}
+
+	// the address may have been registered again while the user was deleted
+	if _, exists, _ := s.userRepository.GetUserByEmail(ctx, nil, user.Email); exists {
+		return dto.AdminUserResponse{}, dto.ErrorEmailAlreadyExists
+	}
+
+	if err := s.userRepository.RestoreUser(ctx, nil, userId); err != nil {
+		return dto.AdminUserResponse{}, err
+	}
+
+	s.record(ctx, actorId, entity.AuditActionAdminRestoreUser, userId, auditChanges(map[string]any{"deleted": true}, map[string]any{"deleted": false}))
+
+	user.DeletedAt = gorm.DeletedAt{}
+	return toAdminUserResponse(user), nil
+}
+
+// updateAndRevoke applies changes that alter what the user's tokens claim,
+// so cached permissions and any live session are dropped and the user has to
+// log in again.
+func (s *adminUserService) updateAndRevoke(ctx context.Context, userId uuid.UUID, updates map[string]interface{}) (entity.User, error) {
+	updatedUser, err := s.userRepository.UpdateUser(ctx, nil, userId, updates)
+	if err != nil {
+		return entity.User{}, dto.ErrUpdateUser.Wrap(err)
+	}
+
+	s.roleService.InvalidateUser(userId)
+
+	if err := s.sessionService.RevokeUserSessions(ctx, userId, nil); err != nil {
+		return entity.User{}, err
+	}
+
+	return updatedUser, nil
+}
+
+// record writes the audit entry of an admin action on a user account.
+func (s *adminUserService) record(ctx context.Context, actorId uuid.UUID, action entity.AuditAction, userId uuid.UUID, changes string) {
+	s.auditService.Record(ctx, entity.AuditLog{
+		ActorID:    auditActor(actorId),
+		Action:     action,
+		TargetType: "user",
+		TargetID:   userId.String(),
+		Success:    true,
+		Changes:    changes,
+	})
+}
+
+func toAdminUserResponse(user entity.User) dto.AdminUserResponse {
+	var deletedAt *time.Time
+	if user.DeletedAt.Valid {
+		deletedAt = &user.DeletedAt.Time
+	}
+
+	return dto.AdminUserResponse{
+		ID:               user.ID.String(),
+		Name:             user.Name,
+		Email:            user.Email,
+		Instansi:         user.Instansi,
+		NoTelp:           user.NoTelp,
+		Role:             string(user.Role),
+		IsVerified:       user.IsVerified,
+		IsSuspended:      user.IsSuspended,
+		TwoFactorEnabled: user.TwoFactorEnabled,
+		CreatedAt:        user.CreatedAt,
+		UpdatedAt:        user.UpdatedAt,
+		DeletedAt:        deletedAt,
+	}
+}
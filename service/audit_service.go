@@ -0,0 +1,172 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"time"
+
+	"github.com/Shabrinashsf/go-gin-gorm-boilerplate/config"
+	"github.com/Shabrinashsf/go-gin-gorm-boilerplate/dto"
+	"github.com/Shabrinashsf/go-gin-gorm-boilerplate/entity"
+	"github.com/Shabrinashsf/go-gin-gorm-boilerplate/helpers"
+	"github.com/Shabrinashsf/go-gin-gorm-boilerplate/repository"
+	"github.com/Shabrinashsf/go-gin-gorm-boilerplate/utils/logger"
+	"github.com/Shabrinashsf/go-gin-gorm-boilerplate/utils/pagination"
+	"github.com/google/uuid"
+)
+
+type (
+	AuditService interface {
+		Record(ctx context.Context, auditLog entity.AuditLog)
+		GetAuditLogs(ctx context.Context, filter dto.AuditLogFilter, meta pagination.Meta) (dto.AuditLogPaginationResponse, error)
+		PurgeExpired(ctx context.Context) (int64, error)
+		RunRetention(ctx context.Context)
+	}
+
+	auditService struct {
+		auditLogRepo repository.AuditLogRepository
+		retention    time.Duration
+	}
+)
+
+func NewAuditService(alr repository.AuditLogRepository, cfg *config.Config) AuditService {
+	return &auditService{
+		auditLogRepo: alr,
+		retention:    time.Duration(cfg.Security.AuditLogRetentionDays) * time.Hour * 24,
+	}
+}
+
+var (
+	AUDIT_LOG_PURGE_INTERVAL = time.Hour * 24
+)
+
+// Record stores an audit entry, filling the client IP and user agent from the
+// request context. Failures are logged and never break the audited action.
+func (s *auditService) Record(ctx context.Context, auditLog entity.AuditLog) {
+	meta := helpers.GetRequestMeta(ctx)
+	if auditLog.IPAddress == "" {
+		auditLog.IPAddress = meta.IPAddress
+	}
+	if auditLog.UserAgent == "" {
+		auditLog.UserAgent = meta.UserAgent
+	}
+
+	// the audited request may already be cancelled or timed out
+	if err := s.auditLogRepo.CreateAuditLog(context.WithoutCancel(ctx), nil, auditLog); err != nil {
+		logger.FromContext(ctx).Errorf("failed to write audit log %s: %v", auditLog.Action, err)
+	}
+}
+
+func (s *auditService) GetAuditLogs(ctx context.Context, filter dto.AuditLogFilter, meta pagination.Meta) (dto.AuditLogPaginationResponse, error) {
+	meta.GetSkipAndLimit()
+
+	auditLogs, count, err := s.auditLogRepo.GetAllAuditLogsWithPagination(ctx, nil, filter, meta)
+	if err != nil {
+		return dto.AuditLogPaginationResponse{}, err
+	}
+
+	meta.Count(int(count))
+
+	data := make([]dto.AuditLogResponse, 0, len(auditLogs))
+	for _, auditLog := range auditLogs {
+		data = append(data, toAuditLogResponse(auditLog))
+	}
+
+	return dto.AuditLogPaginationResponse{
+		Data: data,
+		Meta: meta,
+	}, nil
+}
+
+func (s *auditService) PurgeExpired(ctx context.Context) (int64, error) {
+	if s.retention <= 0 {
+		return 0, nil
+	}
+
+	return s.auditLogRepo.DeleteAuditLogsBefore(ctx, nil, time.Now().Add(-s.retention))
+}
+
+// RunRetention purges expired audit logs once at startup and then every
+// AUDIT_LOG_PURGE_INTERVAL until ctx is cancelled.
+func (s *auditService) RunRetention(ctx context.Context) {
+	if s.retention <= 0 {
+		return
+	}
+
+	ticker := time.NewTicker(AUDIT_LOG_PURGE_INTERVAL)
+	defer ticker.Stop()
+
+	for {
+		deleted, err := s.PurgeExpired(ctx)
+		if err != nil {
+			logger.Errorf("failed to purge audit logs: %v", err)
+		} else if deleted > 0 {
+			logger.Infof("purged %d audit logs older than %s", deleted, s.retention)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// auditActor returns a pointer suitable for AuditLog.ActorID.
+func auditActor(id uuid.UUID) *uuid.UUID {
+	if id == uuid.Nil {
+		return nil
+	}
+	return &id
+}
+
+// auditChanges encodes the fields that differ between before and after as
+// {"field": {"from": .., "to": ..}}. Only keys present in after are compared.
+func auditChanges(before map[string]any, after map[string]any) string {
+	changes := map[string]dto.AuditChange{}
+	for key, to := range after {
+		from := before[key]
+		if reflect.DeepEqual(from, to) {
+			continue
+		}
+		changes[key] = dto.AuditChange{From: from, To: to}
+	}
+
+	if len(changes) == 0 {
+		return ""
+	}
+
+	encoded, err := json.Marshal(changes)
+	if err != nil {
+		return fmt.Sprintf(`{"error": %q}`, err.Error())
+	}
+	return string(encoded)
+}
+
+func toAuditLogResponse(auditLog entity.AuditLog) dto.AuditLogResponse {
+	var actorId *string
+	if auditLog.ActorID != nil {
+		id := auditLog.ActorID.String()
+		actorId = &id
+	}
+
+	var changes json.RawMessage
+	if auditLog.Changes != "" {
+		changes = json.RawMessage(auditLog.Changes)
+	}
+
+	return dto.AuditLogResponse{
+		ID:         auditLog.ID.String(),
+		ActorID:    actorId,
+		Action:     string(auditLog.Action),
+		TargetType: auditLog.TargetType,
+		TargetID:   auditLog.TargetID,
+		Success:    auditLog.Success,
+		IPAddress:  auditLog.IPAddress,
+		UserAgent:  auditLog.UserAgent,
+		Changes:    changes,
+		CreatedAt:  auditLog.CreatedAt,
+	}
+}
@@ -0,0 +1,327 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/Shabrinashsf/go-gin-gorm-boilerplate/config"
+	"github.com/Shabrinashsf/go-gin-gorm-boilerplate/dto"
+	"github.com/Shabrinashsf/go-gin-gorm-boilerplate/entity"
+	"github.com/Shabrinashsf/go-gin-gorm-boilerplate/repository"
+	"github.com/Shabrinashsf/go-gin-gorm-boilerplate/utils/logger"
+	"github.com/Shabrinashsf/go-gin-gorm-boilerplate/utils/mailer"
+	"github.com/Shabrinashsf/go-gin-gorm-boilerplate/utils/metrics"
+	"github.com/Shabrinashsf/go-gin-gorm-boilerplate/utils/pagination"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type (
+	// EmailOutboxService queues email in the caller's transaction and delivers
+	// it in the background, retrying failed sends with exponential backoff
+	// until OUTBOX_MAX_ATTEMPTS, after which the email is dead-lettered.
+	EmailOutboxService interface {
+		Enqueue(ctx context.Context, tx *gorm.DB, msg mailer.Message) error
+		GetEmails(ctx context.Context, filter dto.EmailOutboxFilter, meta pagination.Meta) (dto.EmailOutboxPaginationResponse, error)
+		GetEmailByID(ctx context.Context, id uuid.UUID) (dto.EmailOutboxResponse, error)
+		ResendEmail(ctx context.Context, id uuid.UUID) (dto.EmailOutboxResponse, error)
+		Run(ctx context.Context)
+		RunRetention(ctx context.Context)
+		Stats(ctx context.Context) (dto.EmailOutboxStats, error)
+	}
+
+	emailOutboxService struct {
+		emailOutboxRepo repository.EmailOutboxRepository
+		mailer          mailer.Mailer
+		cfg             config.OutboxConfig
+		running         atomic.Bool
+	}
+)
+
+func NewEmailOutboxService(eor repository.EmailOutboxRepository, mailer mailer.Mailer, cfg *config.Config) EmailOutboxService {
+	return &emailOutboxService{
+		emailOutboxRepo: eor,
+		mailer:          mailer,
+		cfg:             cfg.Outbox,
+	}
+}
+
+var (
+	// EMAIL_OUTBOX_LEASE is how long a claimed email is hidden from other
+	// workers, it must outlast a send.
+	EMAIL_OUTBOX_LEASE        = time.Minute * 5
+	EMAIL_OUTBOX_SEND_TIMEOUT = time.Second * 30
+
+	EMAIL_OUTBOX_PURGE_INTERVAL = time.Hour
+)
+
+// Enqueue stores the message, recipients, headers and attachments included,
+// for delivery. Pass the transaction of the change the email belongs to, so
+// both commit or roll back together.
+func (s *emailOutboxService) Enqueue(ctx context.Context, tx *gorm.DB, msg mailer.Message) error {
+	if len(msg.Recipients()) == 0 {
+		return dto.ErrEnqueueMail.Wrap(mailer.ErrNoRecipients)
+	}
+
+	raw, err := json.Marshal(msg)
+	if err != nil {
+		return dto.ErrEnqueueMail.Wrap(err)
+	}
+
+	_, err = s.emailOutboxRepo.CreateEmail(ctx, tx, entity.EmailOutbox{
+		ToEmail:       strings.Join(msg.To, ", "),
+		Subject:       msg.Subject,
+		Message:       string(raw),
+		Status:        entity.EmailStatusPending,
+		NextAttemptAt: time.Now(),
+	})
+	if err != nil {
+		return dto.ErrEnqueueMail.Wrap(err)
+	}
+
+	return nil
+}
+
+func (s *emailOutboxService) GetEmails(ctx context.Context, filter dto.EmailOutboxFilter, meta pagination.Meta) (dto.EmailOutboxPaginationResponse, error) {
+	meta.GetSkipAndLimit()
+
+	emails, count, err := s.emailOutboxRepo.GetAllEmailsWithPagination(ctx, nil, filter, meta)
+	if err != nil {
+		return dto.EmailOutboxPaginationResponse{}, err
+	}
+
+	meta.Count(int(count))
+
+	data := make([]dto.EmailOutboxResponse, 0, len(emails))
+	for _, email := range emails {
+		data = append(data, toEmailOutboxResponse(email))
+	}
+
+	return dto.EmailOutboxPaginationResponse{
+		Data: data,
+		Meta: meta,
+	}, nil
+}
+
+func (s *emailOutboxService) GetEmailByID(ctx context.Context, id uuid.UUID) (dto.EmailOutboxResponse, error) {
+	email, err := s.emailOutboxRepo.GetEmailByID(ctx, nil, id)
+	if err != nil {
+		return dto.EmailOutboxResponse{}, dto.ErrOutboxEmailNotFound
+	}
+
+	return toEmailOutboxResponse(email), nil
+}
+
+// ResendEmail puts a dead-lettered email back in the queue with a fresh
+// retry budget. The last error is kept until the next attempt replaces it.
+func (s *emailOutboxService) ResendEmail(ctx context.Context, id uuid.UUID) (dto.EmailOutboxResponse, error) {
+	email, err := s.emailOutboxRepo.GetEmailByID(ctx, nil, id)
+	if err != nil {
+		return dto.EmailOutboxResponse{}, dto.ErrOutboxEmailNotFound
+	}
+
+	if email.Status != entity.EmailStatusDead {
+		return dto.EmailOutboxResponse{}, dto.ErrEmailNotFailed
+	}
+
+	updates := map[string]interface{}{}
+	updates["status"] = entity.EmailStatusPending
+	updates["attempts"] = 0
+	updates["next_attempt_at"] = time.Now()
+
+	updatedEmail, err := s.emailOutboxRepo.UpdateEmail(ctx, nil, id, updates)
+	if err != nil {
+		return dto.EmailOutboxResponse{}, err
+	}
+
+	logger.FromContext(ctx).Infof("[outbox] email %s to %s queued again", id, email.ToEmail)
+	return toEmailOutboxResponse(updatedEmail), nil
+}
+
+// Run delivers due email every OUTBOX_POLL_INTERVAL until ctx is cancelled.
+// It returns once the batch in flight is finished, so callers can wait for
+// it before closing the database.
+func (s *emailOutboxService) Run(ctx context.Context) {
+	s.running.Store(true)
+	defer s.running.Store(false)
+
+	ticker := time.NewTicker(s.cfg.PollInterval)
+	defer ticker.Stop()
+
+	for {
+		s.deliverDue(ctx)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// RunRetention deletes dead email older than OUTBOX_DEAD_RETENTION once at
+// startup and then every EMAIL_OUTBOX_PURGE_INTERVAL until ctx is cancelled.
+func (s *emailOutboxService) RunRetention(ctx context.Context) {
+	if s.cfg.DeadRetention <= 0 {
+		return
+	}
+
+	ticker := time.NewTicker(EMAIL_OUTBOX_PURGE_INTERVAL)
+	defer ticker.Stop()
+
+	for {
+		deleted, err := s.emailOutboxRepo.DeleteEmailsBefore(ctx, nil, entity.EmailStatusDead, time.Now().Add(-s.cfg.DeadRetention))
+		if err != nil {
+			if ctx.Err() == nil {
+				logger.Errorf("[outbox] failed to purge dead email: %v", err)
+			}
+		} else if deleted > 0 {
+			logger.Infof("[outbox] purged %d dead emails older than %s", deleted, s.cfg.DeadRetention)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Stats counts the pending and dead backlog and reports whether Run is active.
+func (s *emailOutboxService) Stats(ctx context.Context) (dto.EmailOutboxStats, error) {
+	counts, err := s.emailOutboxRepo.CountEmailsByStatus(ctx, nil)
+	if err != nil {
+		return dto.EmailOutboxStats{WorkerRunning: s.running.Load()}, err
+	}
+
+	return dto.EmailOutboxStats{
+		Pending:       counts[entity.EmailStatusPending],
+		Dead:          counts[entity.EmailStatusDead],
+		WorkerRunning: s.running.Load(),
+	}, nil
+}
+
+// deliverDue claims batches until nothing is due. A claimed batch is sent
+// even when ctx is cancelled meanwhile, otherwise its emails would wait for
+// the lease to run out.
+func (s *emailOutboxService) deliverDue(ctx context.Context) {
+	for ctx.Err() == nil {
+		emails, err := s.emailOutboxRepo.ClaimDueEmails(ctx, s.cfg.BatchSize, EMAIL_OUTBOX_LEASE)
+		if err != nil {
+			if ctx.Err() == nil {
+				logger.Errorf("[outbox] failed to claim emails: %v", err)
+			}
+			return
+		}
+
+		s.deliver(context.WithoutCancel(ctx), emails)
+
+		if len(emails) < s.cfg.BatchSize {
+			return
+		}
+	}
+}
+
+// deliver sends a claimed batch over one mailer connection and records the
+// outcome of every email.
+func (s *emailOutboxService) deliver(ctx context.Context, emails []entity.EmailOutbox) {
+	if len(emails) == 0 {
+		return
+	}
+
+	batch := make([]entity.EmailOutbox, 0, len(emails))
+	msgs := make([]mailer.Message, 0, len(emails))
+	for _, email := range emails {
+		msg, err := s.decode(email)
+		if err != nil {
+			s.record(ctx, email, err)
+			continue
+		}
+		batch = append(batch, email)
+		msgs = append(msgs, msg)
+	}
+
+	// the batch must finish within the lease, the outcome is recorded with
+	// ctx so a batch that ran out of time still gets its status updates
+	timeout := min(EMAIL_OUTBOX_SEND_TIMEOUT*time.Duration(len(msgs)), EMAIL_OUTBOX_LEASE-EMAIL_OUTBOX_SEND_TIMEOUT)
+	sendCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	for i, sendErr := range s.mailer.SendBatch(sendCtx, msgs) {
+		s.record(ctx, batch[i], sendErr)
+	}
+}
+
+// decode restores the queued message. Rows migrated from before messages
+// were stored have no From, they get the configured sender.
+func (s *emailOutboxService) decode(email entity.EmailOutbox) (mailer.Message, error) {
+	var msg mailer.Message
+	if err := json.Unmarshal([]byte(email.Message), &msg); err != nil {
+		return mailer.Message{}, fmt.Errorf("outbox: decode message: %w", err)
+	}
+	if len(msg.Recipients()) == 0 {
+		return mailer.Message{}, mailer.ErrNoRecipients
+	}
+
+	if msg.From == "" {
+		msg.From = s.mailer.Sender()
+	}
+	msg.Date = time.Now()
+	return msg, nil
+}
+
+func (s *emailOutboxService) record(ctx context.Context, email entity.EmailOutbox, sendErr error) {
+	updates := map[string]interface{}{}
+	switch {
+	case sendErr == nil:
+		updates["status"] = entity.EmailStatusSent
+		updates["sent_at"] = time.Now()
+		updates["last_error"] = ""
+		// the message carries one-time links, which must not outlive delivery
+		updates["message"] = "{}"
+	case email.Attempts >= s.cfg.MaxAttempts:
+		updates["status"] = entity.EmailStatusDead
+		updates["last_error"] = sendErr.Error()
+		metrics.EmailDeadLettered()
+		logger.Errorf("[outbox] giving up on email %s to %s after %d attempts: %v", email.ID, email.ToEmail, email.Attempts, sendErr)
+	default:
+		retryAt := time.Now().Add(s.backoff(email.Attempts))
+		updates["next_attempt_at"] = retryAt
+		updates["last_error"] = sendErr.Error()
+		logger.Warnf("[outbox] attempt %d for email %s to %s failed, retrying at %s: %v", email.Attempts, email.ID, email.ToEmail, retryAt.Format(time.RFC3339), sendErr)
+	}
+
+	if _, err := s.emailOutboxRepo.UpdateEmail(ctx, nil, email.ID, updates); err != nil {
+		// the lease expires and the email is sent again, possibly twice
+		logger.Errorf("[outbox] failed to record delivery of email %s: %v", email.ID, err)
+	}
+}
+
+// backoff is OUTBOX_RETRY_BACKOFF doubled for every attempt after the first,
+// capped at OUTBOX_MAX_BACKOFF.
+func (s *emailOutboxService) backoff(attempts int) time.Duration {
+	delay := s.cfg.RetryBackoff
+	for i := 1; i < attempts && delay < s.cfg.MaxBackoff; i++ {
+		delay *= 2
+	}
+	return min(delay, s.cfg.MaxBackoff)
+}
+
+func toEmailOutboxResponse(email entity.EmailOutbox) dto.EmailOutboxResponse {
+	return dto.EmailOutboxResponse{
+		ID:            email.ID.String(),
+		ToEmail:       email.ToEmail,
+		Subject:       email.Subject,
+		Status:        string(email.Status),
+		Attempts:      email.Attempts,
+		NextAttemptAt: email.NextAttemptAt,
+		LastError:     email.LastError,
+		SentAt:        email.SentAt,
+		CreatedAt:     email.CreatedAt,
+		UpdatedAt:     email.UpdatedAt,
+	}
+}
@@ -0,0 +1,137 @@
+package service
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Shabrinashsf/go-gin-gorm-boilerplate/config"
+	"github.com/Shabrinashsf/go-gin-gorm-boilerplate/entity"
+	"github.com/Shabrinashsf/go-gin-gorm-boilerplate/repository"
+	"github.com/Shabrinashsf/go-gin-gorm-boilerplate/utils/mailer"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type fakeEmailOutboxRepository struct {
+	repository.EmailOutboxRepository
+	created []entity.EmailOutbox
+	updates map[uuid.UUID]map[string]interface{}
+}
+
+func (r *fakeEmailOutboxRepository) CreateEmail(_ context.Context, _ *gorm.DB, email entity.EmailOutbox) (entity.EmailOutbox, error) {
+	email.ID = uuid.New()
+	email.Attempts = 1
+	r.created = append(r.created, email)
+	return email, nil
+}
+
+func (r *fakeEmailOutboxRepository) UpdateEmail(_ context.Context, _ *gorm.DB, id uuid.UUID, updates map[string]interface{}) (entity.EmailOutbox, error) {
+	if r.updates == nil {
+		r.updates = map[uuid.UUID]map[string]interface{}{}
+	}
+	r.updates[id] = updates
+	return entity.EmailOutbox{ID: id}, nil
+}
+
+func newTestOutbox() (*emailOutboxService, *fakeEmailOutboxRepository, mailer.Mailbox) {
+	transport := mailer.NewMemoryTransport()
+	repo := &fakeEmailOutboxRepository{}
+	service := &emailOutboxService{
+		emailOutboxRepo: repo,
+		mailer:          mailer.NewMailerWithTransport(config.SMTPConfig{SenderName: "App", SenderEmail: "noreply@example.com"}, transport),
+		cfg:             config.OutboxConfig{BatchSize: 10, MaxAttempts: 3, RetryBackoff: time.Second, MaxBackoff: time.Minute},
+	}
+	return service, repo, transport.(mailer.Mailbox)
+}
+
+func TestEmailOutboxRoundTrip(t *testing.T) {
+	s, repo, mailbox := newTestOutbox()
+
+	msg, err := s.mailer.NewMessage().
+		To("ann@example.com", "Bob <bob@example.com>").
+		Cc("cc@example.com").
+		Bcc("audit@example.com").
+		ReplyTo("support@example.com").
+		Subject("Invoice").
+		Body(mailer.Email{Body: "<p>Hi</p>", Text: "Hi\n"}).
+		ListUnsubscribe("https://example.com/unsubscribe").
+		Attach("invoice.pdf", "", strings.NewReader("%PDF-1.7 \x00\xff binary")).
+		Embed("logo.png", "image/png", strings.NewReader("\x89PNG")).
+		Build()
+	if err != nil {
+		t.Fatalf("Build() error = %v", err)
+	}
+
+	if err := s.Enqueue(context.Background(), nil, msg); err != nil {
+		t.Fatalf("Enqueue() error = %v", err)
+	}
+	if len(repo.created) != 1 {
+		t.Fatalf("Enqueue() stored %d emails, want 1", len(repo.created))
+	}
+	stored := repo.created[0]
+	if stored.ToEmail != "ann@example.com, Bob <bob@example.com>" || stored.Subject != "Invoice" || stored.Status != entity.EmailStatusPending {
+		t.Errorf("stored email = %+v", stored)
+	}
+
+	decoded, err := s.decode(stored)
+	if err != nil {
+		t.Fatalf("decode() error = %v", err)
+	}
+	// Date is stamped again when the email goes out
+	decoded.Date, msg.Date = time.Time{}, time.Time{}
+	if !reflect.DeepEqual(decoded, msg) {
+		t.Errorf("decode() =\n%+v\nwant\n%+v", decoded, msg)
+	}
+
+	s.deliver(context.Background(), []entity.EmailOutbox{stored})
+
+	sent := mailbox.Messages()
+	if len(sent) != 1 {
+		t.Fatalf("delivered %d messages, want 1", len(sent))
+	}
+	if got := strings.Join(sent[0].Recipients(), " "); got != "ann@example.com Bob <bob@example.com> cc@example.com audit@example.com" {
+		t.Errorf("delivered to %s", got)
+	}
+	if len(sent[0].Attachments) != 1 || string(sent[0].Attachments[0].Content) != "%PDF-1.7 \x00\xff binary" {
+		t.Errorf("delivered attachments = %+v", sent[0].Attachments)
+	}
+
+	updates := repo.updates[stored.ID]
+	if updates["status"] != entity.EmailStatusSent || updates["message"] != "{}" {
+		t.Errorf("recorded delivery = %v, want sent with the message cleared", updates)
+	}
+}
+
+func TestEmailOutboxDecode(t *testing.T) {
+	tests := []struct {
+		name     string
+		message  string
+		wantFrom string
+		wantErr  bool
+	}{
+		{name: "stored message", message: `{"From":"Shop <shop@example.com>","To":["ann@example.com"],"Subject":"Hi"}`, wantFrom: "Shop <shop@example.com>"},
+		{name: "migrated row without sender", message: `{"To":["ann@example.com"],"Subject":"Hi","HTML":"<p>Hi</p>","Text":"Hi"}`, wantFrom: "App <noreply@example.com>"},
+		{name: "cleared after sending", message: `{}`, wantErr: true},
+		{name: "not json", message: `<p>Hi</p>`, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, _, _ := newTestOutbox()
+
+			msg, err := s.decode(entity.EmailOutbox{ID: uuid.New(), Message: tt.message})
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("decode() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if msg.From != tt.wantFrom || msg.Date.IsZero() {
+				t.Errorf("decode() From = %q, Date = %v, want From %q and a Date", msg.From, msg.Date, tt.wantFrom)
+			}
+		})
+	}
+}
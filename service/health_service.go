@@ -0,0 +1,261 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"net"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/Shabrinashsf/go-gin-gorm-boilerplate/config"
+	"github.com/Shabrinashsf/go-gin-gorm-boilerplate/database"
+	"github.com/Shabrinashsf/go-gin-gorm-boilerplate/dto"
+	"github.com/Shabrinashsf/go-gin-gorm-boilerplate/utils/logger"
+	"github.com/Shabrinashsf/go-gin-gorm-boilerplate/utils/storage"
+	"gorm.io/gorm"
+)
+
+type (
+	// HealthChecker is implemented by every dependency that takes part in
+	// readiness. Modules register their own checkers in NewServer.
+	HealthChecker interface {
+		Name() string
+		Check(ctx context.Context) error
+	}
+
+	// HealthDetailer can be implemented next to HealthChecker to attach extra
+	// information, such as pool statistics, to the readiness response.
+	HealthDetailer interface {
+		Details() any
+	}
+
+	HealthService interface {
+		Register(checker HealthChecker, required bool)
+		Liveness() dto.LivenessResponse
+		Readiness(ctx context.Context) (dto.ReadinessResponse, bool)
+		MarkShuttingDown()
+	}
+
+	healthCheck struct {
+		checker  HealthChecker
+		required bool
+	}
+
+	healthService struct {
+		mu           sync.RWMutex
+		checks       []healthCheck
+		startedAt    time.Time
+		shuttingDown atomic.Bool
+		verbose      bool
+	}
+)
+
+// NewHealthService reports errors and details of the components only outside
+// production, /readyz is public and they reveal the infrastructure.
+func NewHealthService(cfg *config.Config) HealthService {
+	return &healthService{
+		startedAt: time.Now(),
+		verbose:   !cfg.App.IsProduction,
+	}
+}
+
+var (
+	HEALTH_CHECK_TIMEOUT = time.Second * 2
+)
+
+// Register adds a readiness check. A failing required check makes /readyz
+// return 503, an optional one is only reported.
+func (s *healthService) Register(checker HealthChecker, required bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.checks = append(s.checks, healthCheck{checker: checker, required: required})
+}
+
+func (s *healthService) Liveness() dto.LivenessResponse {
+	return dto.LivenessResponse{
+		Status: dto.HEALTH_STATUS_OK,
+		Uptime: time.Since(s.startedAt).Round(time.Second).String(),
+	}
+}
+
+// Readiness runs every check concurrently. Once shutdown has started it
+// reports not ready without checking anything so load balancers drain the
+// instance before the HTTP server stops.
+func (s *healthService) Readiness(ctx context.Context) (dto.ReadinessResponse, bool) {
+	if s.shuttingDown.Load() {
+		return dto.ReadinessResponse{
+			Status:     dto.HEALTH_STATUS_DRAINING,
+			Components: []dto.HealthComponent{},
+		}, false
+	}
+
+	s.mu.RLock()
+	checks := append([]healthCheck(nil), s.checks...)
+	s.mu.RUnlock()
+
+	components := make([]dto.HealthComponent, len(checks))
+	var wg sync.WaitGroup
+	for i, check := range checks {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			components[i] = runHealthCheck(ctx, check, s.verbose)
+		}()
+	}
+	wg.Wait()
+
+	ready := true
+	for _, component := range components {
+		if component.Required && component.Status != dto.HEALTH_STATUS_UP {
+			ready = false
+		}
+	}
+
+	status := dto.HEALTH_STATUS_READY
+	if !ready {
+		status = dto.HEALTH_STATUS_NOT_READY
+	}
+
+	return dto.ReadinessResponse{
+		Status:     status,
+		Components: components,
+	}, ready
+}
+
+func (s *healthService) MarkShuttingDown() {
+	s.shuttingDown.Store(true)
+}
+
+// runHealthCheck always logs a failure, the error and details only go into
+// the response when verbose is set.
+func runHealthCheck(ctx context.Context, check healthCheck, verbose bool) dto.HealthComponent {
+	checkCtx, cancel := context.WithTimeout(ctx, HEALTH_CHECK_TIMEOUT)
+	defer cancel()
+
+	start := time.Now()
+	err := check.checker.Check(checkCtx)
+	component := dto.HealthComponent{
+		Name:      check.checker.Name(),
+		Status:    dto.HEALTH_STATUS_UP,
+		Required:  check.required,
+		LatencyMS: float64(time.Since(start).Microseconds()) / 1000,
+	}
+	if err != nil {
+		component.Status = dto.HEALTH_STATUS_DOWN
+		logger.FromContext(ctx).Warnf("[health] %s is down: %v", component.Name, err)
+	}
+	if !verbose {
+		return component
+	}
+
+	if err != nil {
+		component.Error = err.Error()
+	}
+	if detailer, ok := check.checker.(HealthDetailer); ok {
+		component.Details = detailer.Details()
+	}
+
+	return component
+}
+
+type (
+	databaseHealthCheck struct {
+		db *gorm.DB
+	}
+
+	smtpHealthCheck struct {
+		address string
+	}
+
+	storageHealthCheck struct {
+		storage storage.AwsS3
+	}
+
+	emailOutboxHealthCheck struct {
+		outbox EmailOutboxService
+
+		mu    sync.Mutex
+		stats dto.EmailOutboxStats
+	}
+)
+
+// NewDatabaseHealthCheck pings the primary and reports the pool statistics
+// of the primary and every read replica.
+func NewDatabaseHealthCheck(db *gorm.DB) HealthChecker {
+	return &databaseHealthCheck{db: db}
+}
+
+func (c *databaseHealthCheck) Name() string { return "database" }
+
+func (c *databaseHealthCheck) Check(ctx context.Context) error {
+	return database.Ping(ctx, c.db)
+}
+
+func (c *databaseHealthCheck) Details() any {
+	stats, err := database.Stats(c.db)
+	if err != nil {
+		return nil
+	}
+	return stats
+}
+
+// NewSMTPHealthCheck only opens a TCP connection to the SMTP server, a full
+// handshake with authentication on every probe would be too expensive.
+func NewSMTPHealthCheck(cfg config.SMTPConfig) HealthChecker {
+	return &smtpHealthCheck{address: net.JoinHostPort(cfg.Host, strconv.Itoa(cfg.Port))}
+}
+
+func (c *smtpHealthCheck) Name() string { return "smtp" }
+
+func (c *smtpHealthCheck) Check(ctx context.Context) error {
+	var dialer net.Dialer
+	conn, err := dialer.DialContext(ctx, "tcp", c.address)
+	if err != nil {
+		return err
+	}
+	return conn.Close()
+}
+
+func NewStorageHealthCheck(s storage.AwsS3) HealthChecker {
+	return &storageHealthCheck{storage: s}
+}
+
+func (c *storageHealthCheck) Name() string { return "storage" }
+
+func (c *storageHealthCheck) Check(ctx context.Context) error {
+	return c.storage.Ping(ctx)
+}
+
+// NewEmailOutboxHealthCheck reports the pending and dead backlog and fails
+// when the delivery worker is not running.
+func NewEmailOutboxHealthCheck(outbox EmailOutboxService) HealthChecker {
+	return &emailOutboxHealthCheck{outbox: outbox}
+}
+
+func (c *emailOutboxHealthCheck) Name() string { return "email_outbox" }
+
+func (c *emailOutboxHealthCheck) Check(ctx context.Context) error {
+	stats, err := c.outbox.Stats(ctx)
+
+	c.mu.Lock()
+	c.stats = stats
+	c.mu.Unlock()
+
+	if err != nil {
+		return err
+	}
+	if !stats.WorkerRunning {
+		return errors.New("outbox worker is not running")
+	}
+	return nil
+}
+
+func (c *emailOutboxHealthCheck) Details() any {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.stats
+}
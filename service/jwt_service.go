@@ -2,105 +2,168 @@ package service
 
 import (
 	"fmt"
-	"log"
-	"os"
-	"sync"
 	"time"
 
+	"github.com/Shabrinashsf/go-gin-gorm-boilerplate/config"
+	"github.com/Shabrinashsf/go-gin-gorm-boilerplate/utils/jwk"
+	"github.com/Shabrinashsf/go-gin-gorm-boilerplate/utils/logger"
 	"github.com/golang-jwt/jwt/v4"
 )
 
-var (
-	// Track latest reset token issue time per email
-	resetTokenTimes = make(map[string]int64)
-	resetTokenMutex sync.RWMutex
+const (
+	accessTokenTTL    = time.Minute * 15
+	challengeTokenTTL = time.Minute * 5
 )
 
 type JWTService interface {
-	GenerateToken(userId string, role string) string
-	GenerateResetPasswordToken(email string) string
+	GenerateToken(userId string, role string, sessionId string, permissions []string) string
 	ValidateToken(token string) (*jwt.Token, error)
 	GetUserIDByToken(token string) (string, error)
 	GetEmailByToken(token string) (string, error)
-	ValidateResetToken(token string) (string, error)
+	GenerateChallengeToken(userId string, purpose string, challengeId string) string
+	ValidateChallengeToken(token string, purpose string) (string, string, error)
+	GetJWKS() jwk.JSONWebKeySet
 }
 
 type jwtCustomClaim struct {
-	UserID string `json:"user_id"`
-	Role   string `json:"role"`
+	UserID      string   `json:"user_id"`
+	Role        string   `json:"role"`
+	SessionID   string   `json:"sid"`
+	Permissions []string `json:"perms"`
+	jwt.RegisteredClaims
+}
+
+// jwtChallengeClaim is issued after a correct password when a second factor is
+// still needed. It carries no role or session, so Authenticate never accepts it.
+// The jti is the ID of the TwoFactorChallenge row that makes it single-use.
+type jwtChallengeClaim struct {
+	UserID  string `json:"user_id"`
+	Purpose string `json:"purpose"`
 	jwt.RegisteredClaims
 }
 
 type jwtService struct {
-	secretKey string
-	issuer    string
+	keys   *jwk.KeySet
+	issuer string
 }
 
-func NewJWTService() JWTService {
+func NewJWTService(cfg *config.Config) JWTService {
+	keys, err := loadKeySet(cfg)
+	if err != nil {
+		panic(fmt.Sprintf("failed to load JWT keys: %v", err))
+	}
+
 	return &jwtService{
-		secretKey: getSecretKey(),
-		issuer:    "Template",
+		keys:   keys,
+		issuer: cfg.JWT.Issuer,
 	}
 }
 
-func getSecretKey() string {
-	secretKey := os.Getenv("JWT_SECRET")
+// loadKeySet prefers asymmetric keys from JWT_KEYS_DIR. JWT_SECRET then only
+// verifies HS256 tokens issued before the switch, and only until
+// JWT_LEGACY_HS256_UNTIL: whoever knows the shared secret can forge tokens, so
+// it must not stay trusted once the old tokens have expired.
+func loadKeySet(cfg *config.Config) (*jwk.KeySet, error) {
+	secretKey := cfg.JWT.Secret
+
+	if dir := cfg.JWT.KeysDir; dir != "" {
+		keys, err := jwk.LoadKeySet(dir, cfg.JWT.ActiveKID)
+		if err != nil {
+			return nil, err
+		}
+		if secretKey == "" {
+			return keys, nil
+		}
+
+		until, err := cfg.JWT.LegacyHS256Deadline()
+		if err != nil {
+			return nil, err
+		}
+		if until.IsZero() || time.Now().After(until) {
+			logger.Warnf("JWT_SECRET is ignored because JWT_KEYS_DIR is set, HS256 tokens are no longer accepted")
+			return keys, nil
+		}
+
+		keys.AddVerifyKey(&jwk.Key{
+			Method:    jwt.SigningMethodHS256,
+			VerifyKey: []byte(secretKey),
+			NotAfter:  until,
+		})
+		logger.Infof("accepting HS256 tokens signed with JWT_SECRET until %s", until.Format(time.RFC3339))
+		return keys, nil
+	}
+
 	if secretKey == "" {
+		if cfg.App.IsProduction {
+			return nil, jwk.ErrNoSigningKey
+		}
+		logger.Warnf("JWT_SECRET is not set, using an insecure development secret")
 		secretKey = "Template"
 	}
-	return secretKey
+
+	return jwk.NewHMACKeySet(secretKey), nil
 }
 
-func (j *jwtService) GenerateToken(userId string, role string) string {
+func (j *jwtService) GenerateToken(userId string, role string, sessionId string, permissions []string) string {
 	claims := jwtCustomClaim{
 		userId,
 		role,
+		sessionId,
+		permissions,
 		jwt.RegisteredClaims{
-			ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Minute * 120)),
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(accessTokenTTL)),
 			Issuer:    j.issuer,
 			IssuedAt:  jwt.NewNumericDate(time.Now()),
 		},
 	}
 
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	tx, err := token.SignedString([]byte(j.secretKey))
+	tx, err := j.keys.Sign(claims)
 	if err != nil {
-		log.Println(err)
+		logger.Errorf("failed to sign token: %v", err)
 	}
 	return tx
 }
 
-func (j *jwtService) GenerateResetPasswordToken(email string) string {
-	// Store the current timestamp for this email - invalidates all previous tokens
-	resetTokenMutex.Lock()
-	issuedAt := time.Now().Unix()
-	resetTokenTimes[email] = issuedAt
-	resetTokenMutex.Unlock()
-
-	claims := jwt.MapClaims{
-		"email": email,
-		"exp":   time.Now().Add(time.Hour * 1).Unix(), // Token expires in 1 hour
-		"iat":   issuedAt,
-		"iss":   j.issuer,
+func (j *jwtService) GenerateChallengeToken(userId string, purpose string, challengeId string) string {
+	claims := jwtChallengeClaim{
+		userId,
+		purpose,
+		jwt.RegisteredClaims{
+			ID:        challengeId,
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(challengeTokenTTL)),
+			Issuer:    j.issuer,
+			IssuedAt:  jwt.NewNumericDate(time.Now()),
+		},
 	}
 
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	tx, err := token.SignedString([]byte(j.secretKey))
+	tx, err := j.keys.Sign(claims)
 	if err != nil {
-		log.Println(err)
+		logger.Errorf("failed to sign token: %v", err)
 	}
 	return tx
 }
 
-func (j *jwtService) parseToken(t_ *jwt.Token) (any, error) {
-	if _, ok := t_.Method.(*jwt.SigningMethodHMAC); !ok {
-		return nil, fmt.Errorf("unexpected signing method %v", t_.Header["alg"])
+// ValidateChallengeToken returns the user and challenge IDs of a challenge
+// token issued for purpose.
+func (j *jwtService) ValidateChallengeToken(token string, purpose string) (string, string, error) {
+	var claims jwtChallengeClaim
+	if _, err := jwt.ParseWithClaims(token, &claims, j.keys.Keyfunc); err != nil {
+		return "", "", err
+	}
+
+	if claims.Purpose == "" || claims.Purpose != purpose {
+		return "", "", fmt.Errorf("invalid challenge purpose")
 	}
-	return []byte(j.secretKey), nil
+
+	if claims.ID == "" {
+		return "", "", fmt.Errorf("challenge token has no jti")
+	}
+
+	return claims.UserID, claims.ID, nil
 }
 
 func (j *jwtService) ValidateToken(token string) (*jwt.Token, error) {
-	return jwt.Parse(token, j.parseToken)
+	return jwt.Parse(token, j.keys.Keyfunc)
 }
 
 func (j *jwtService) GetUserIDByToken(token string) (string, error) {
@@ -125,27 +188,6 @@ func (j *jwtService) GetEmailByToken(token string) (string, error) {
 	return email, nil
 }
 
-func (j *jwtService) ValidateResetToken(token string) (string, error) {
-	t_Token, err := j.ValidateToken(token)
-	if err != nil {
-		return "", fmt.Errorf("invalid token")
-	}
-
-	claims := t_Token.Claims.(jwt.MapClaims)
-	email := fmt.Sprintf("%v", claims["email"])
-
-	iat, ok := claims["iat"].(float64)
-	if !ok {
-		return "", fmt.Errorf("invalid token: missing iat")
-	}
-
-	resetTokenMutex.RLock()
-	latestIat, exists := resetTokenTimes[email]
-	resetTokenMutex.RUnlock()
-
-	if exists && int64(iat) < latestIat {
-		return "", fmt.Errorf("token has been superseded by a newer reset request")
-	}
-
-	return email, nil
+func (j *jwtService) GetJWKS() jwk.JSONWebKeySet {
+	return j.keys.JWKS()
 }
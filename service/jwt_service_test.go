@@ -0,0 +1,179 @@
+package service
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/pem"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/Shabrinashsf/go-gin-gorm-boilerplate/config"
+	"github.com/golang-jwt/jwt/v4"
+)
+
+// newKeysDirConfig writes a single Ed25519 signing key with kid "active" and
+// returns a config using it.
+func newKeysDirConfig(t *testing.T) *config.Config {
+	t.Helper()
+
+	_, key, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatalf("generate key: %v", err)
+	}
+	der, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		t.Fatalf("marshal key: %v", err)
+	}
+
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "active.pem"), pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der}), 0o600); err != nil {
+		t.Fatalf("write key: %v", err)
+	}
+
+	return &config.Config{JWT: config.JWTConfig{KeysDir: dir, ActiveKID: "active", Issuer: "test"}}
+}
+
+func hs256Token(t *testing.T, secret string, claims jwt.Claims) string {
+	t.Helper()
+
+	signed, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString([]byte(secret))
+	if err != nil {
+		t.Fatalf("sign token: %v", err)
+	}
+	return signed
+}
+
+func TestJWTServiceSignsWithActiveKid(t *testing.T) {
+	service := NewJWTService(newKeysDirConfig(t))
+
+	signed := service.GenerateToken("user-id", "admin", "session-id", []string{"users:read"})
+
+	token, err := service.ValidateToken(signed)
+	if err != nil {
+		t.Fatalf("ValidateToken() error = %v", err)
+	}
+	if kid := token.Header["kid"]; kid != "active" {
+		t.Errorf("kid header = %v, want active", kid)
+	}
+	if alg := token.Method.Alg(); alg != "EdDSA" {
+		t.Errorf("alg = %q, want EdDSA", alg)
+	}
+
+	userId, err := service.GetUserIDByToken(signed)
+	if err != nil || userId != "user-id" {
+		t.Errorf("GetUserIDByToken() = (%q, %v), want user-id", userId, err)
+	}
+
+	jwks := service.GetJWKS()
+	if len(jwks.Keys) != 1 || jwks.Keys[0].Kid != "active" || jwks.Keys[0].Alg != "EdDSA" {
+		t.Errorf("GetJWKS() = %+v, want the active Ed25519 key", jwks.Keys)
+	}
+}
+
+func TestJWTServiceRejectsForgedTokens(t *testing.T) {
+	service := NewJWTService(newKeysDirConfig(t))
+	claims := jwt.MapClaims{"user_id": "user-id", "exp": time.Now().Add(time.Minute).Unix()}
+
+	none := jwt.NewWithClaims(jwt.SigningMethodNone, claims)
+	none.Header["kid"] = "active"
+	noneSigned, err := none.SignedString(jwt.UnsafeAllowNoneSignatureType)
+	if err != nil {
+		t.Fatalf("sign none token: %v", err)
+	}
+
+	_, otherKey, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatalf("generate key: %v", err)
+	}
+	unknown := jwt.NewWithClaims(jwt.SigningMethodEdDSA, claims)
+	unknown.Header["kid"] = "unknown"
+	unknownSigned, err := unknown.SignedString(otherKey)
+	if err != nil {
+		t.Fatalf("sign token: %v", err)
+	}
+
+	tests := []struct {
+		name  string
+		token string
+	}{
+		{name: "alg none", token: noneSigned},
+		{name: "unknown kid", token: unknownSigned},
+		{name: "HS256 without kid", token: hs256Token(t, "secret", claims)},
+		{name: "garbage", token: "not.a.token"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := service.ValidateToken(tt.token); err == nil {
+				t.Error("ValidateToken() accepted the token")
+			}
+			if _, err := service.GetUserIDByToken(tt.token); err == nil {
+				t.Error("GetUserIDByToken() accepted the token")
+			}
+		})
+	}
+}
+
+func TestLegacyHS256Secret(t *testing.T) {
+	claims := jwt.MapClaims{"user_id": "user-id", "exp": time.Now().Add(time.Minute).Unix()}
+
+	tests := []struct {
+		name     string
+		until    string
+		accepted bool
+	}{
+		{name: "before the deadline", until: time.Now().Add(time.Hour).Format(time.RFC3339), accepted: true},
+		{name: "after the deadline", until: time.Now().Add(-time.Hour).Format(time.RFC3339)},
+		{name: "no deadline", until: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := newKeysDirConfig(t)
+			cfg.JWT.Secret = "legacy-secret"
+			cfg.JWT.LegacyHS256Until = tt.until
+			service := NewJWTService(cfg)
+
+			_, err := service.ValidateToken(hs256Token(t, "legacy-secret", claims))
+			if accepted := err == nil; accepted != tt.accepted {
+				t.Errorf("HS256 token accepted = %v, want %v (error %v)", accepted, tt.accepted, err)
+			}
+
+			// the shared secret never shows up in the published key set
+			if jwks := service.GetJWKS(); len(jwks.Keys) != 1 {
+				t.Errorf("GetJWKS() = %+v, want only the active key", jwks.Keys)
+			}
+		})
+	}
+}
+
+func TestValidateChallengeToken(t *testing.T) {
+	service := NewJWTService(newKeysDirConfig(t))
+
+	tests := []struct {
+		name    string
+		token   string
+		purpose string
+		wantErr bool
+	}{
+		{name: "matching purpose", token: service.GenerateChallengeToken("user-id", TWO_FACTOR_CHALLENGE_VERIFY, "challenge-id"), purpose: TWO_FACTOR_CHALLENGE_VERIFY},
+		{name: "other purpose", token: service.GenerateChallengeToken("user-id", TWO_FACTOR_CHALLENGE_ENROLL, "challenge-id"), purpose: TWO_FACTOR_CHALLENGE_VERIFY, wantErr: true},
+		{name: "no jti", token: service.GenerateChallengeToken("user-id", TWO_FACTOR_CHALLENGE_VERIFY, ""), purpose: TWO_FACTOR_CHALLENGE_VERIFY, wantErr: true},
+		{name: "access token", token: service.GenerateToken("user-id", "user", "session-id", nil), purpose: TWO_FACTOR_CHALLENGE_VERIFY, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			userId, challengeId, err := service.ValidateChallengeToken(tt.token, tt.purpose)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ValidateChallengeToken() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && (userId != "user-id" || challengeId != "challenge-id") {
+				t.Errorf("ValidateChallengeToken() = (%q, %q), want (user-id, challenge-id)", userId, challengeId)
+			}
+		})
+	}
+}
@@ -0,0 +1,247 @@
+package service
+
+import (
+	"context"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/Shabrinashsf/go-gin-gorm-boilerplate/config"
+	"github.com/Shabrinashsf/go-gin-gorm-boilerplate/dto"
+	"github.com/Shabrinashsf/go-gin-gorm-boilerplate/entity"
+	"github.com/Shabrinashsf/go-gin-gorm-boilerplate/helpers"
+	"github.com/Shabrinashsf/go-gin-gorm-boilerplate/repository"
+	"github.com/Shabrinashsf/go-gin-gorm-boilerplate/utils/logger"
+	"github.com/Shabrinashsf/go-gin-gorm-boilerplate/utils/mailer"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type (
+	// LoginGuardService tracks failed logins per account and per client IP,
+	// slows down repeated failures and temporarily locks the offender.
+	LoginGuardService interface {
+		Check(ctx context.Context, email string, clientIP string) error
+		RegisterFailure(ctx context.Context, email string, clientIP string)
+		RegisterSuccess(ctx context.Context, email string, clientIP string)
+		Unlock(ctx context.Context, token string) (uuid.UUID, error)
+	}
+
+	loginGuardService struct {
+		loginAttemptRepo repository.LoginAttemptRepository
+		unlockTokenRepo  repository.AccountUnlockTokenRepository
+		userRepository   repository.UserRepository
+		emailOutbox      EmailOutboxService
+		mailer           mailer.Mailer
+		cfg              *config.Config
+		db               *gorm.DB
+	}
+)
+
+func NewLoginGuardService(lar repository.LoginAttemptRepository, autr repository.AccountUnlockTokenRepository, ur repository.UserRepository, eos EmailOutboxService, mailer mailer.Mailer, cfg *config.Config, db *gorm.DB) LoginGuardService {
+	return &loginGuardService{
+		loginAttemptRepo: lar,
+		unlockTokenRepo:  autr,
+		userRepository:   ur,
+		emailOutbox:      eos,
+		mailer:           mailer,
+		cfg:              cfg,
+		db:               db,
+	}
+}
+
+var (
+	LOGIN_MAX_ACCOUNT_ATTEMPTS = 5
+	LOGIN_MAX_IP_ATTEMPTS      = 20
+	LOGIN_ATTEMPT_WINDOW       = time.Minute * 15
+	LOGIN_LOCKOUT_DURATION     = time.Minute * 15
+	LOGIN_DELAY_AFTER          = 3
+	LOGIN_MAX_DELAY            = time.Second * 8
+
+	UNLOCK_ACCOUNT_TEMPLATE = "unlock_account_email"
+	UNLOCK_ACCOUNT_PATH     = "unlock-account"
+)
+
+func accountKey(email string) string {
+	return "email:" + strings.ToLower(strings.TrimSpace(email))
+}
+
+func ipKey(clientIP string) string {
+	return "ip:" + clientIP
+}
+
+func (s *loginGuardService) Check(ctx context.Context, email string, clientIP string) error {
+	now := time.Now()
+
+	ipAttempt, err := s.loginAttemptRepo.GetLoginAttempt(ctx, ipKey(clientIP))
+	if err != nil {
+		return err
+	}
+	if ipAttempt.LockedUntil != nil && ipAttempt.LockedUntil.After(now) {
+		return dto.ErrTooManyLoginAttempts
+	}
+
+	accountAttempt, err := s.loginAttemptRepo.GetLoginAttempt(ctx, accountKey(email))
+	if err != nil {
+		return err
+	}
+	if accountAttempt.LockedUntil != nil && accountAttempt.LockedUntil.After(now) {
+		return dto.ErrAccountLocked
+	}
+
+	// Progressive delay: 1s, 2s, 4s, ... once the account passes LOGIN_DELAY_AFTER failures
+	if accountAttempt.Failures >= LOGIN_DELAY_AFTER && accountAttempt.LastFailureAt.After(now.Add(-LOGIN_ATTEMPT_WINDOW)) {
+		delay := time.Duration(math.Pow(2, float64(accountAttempt.Failures-LOGIN_DELAY_AFTER))) * time.Second
+		if delay > LOGIN_MAX_DELAY {
+			delay = LOGIN_MAX_DELAY
+		}
+
+		select {
+		case <-time.After(delay):
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+
+	return nil
+}
+
+func (s *loginGuardService) RegisterFailure(ctx context.Context, email string, clientIP string) {
+	accountAttempt, err := s.loginAttemptRepo.IncrementLoginAttempt(ctx, accountKey(email), LOGIN_ATTEMPT_WINDOW)
+	if err != nil {
+		logger.FromContext(ctx).Errorf("[login-guard] failed to record attempt for %s: %v", email, err)
+	} else if accountAttempt.Failures >= LOGIN_MAX_ACCOUNT_ATTEMPTS {
+		s.lock(ctx, accountKey(email), accountAttempt.Failures, clientIP)
+
+		// one link per lockout, later failures only extend the lock
+		if accountAttempt.Failures == LOGIN_MAX_ACCOUNT_ATTEMPTS {
+			s.sendUnlockEmail(ctx, email)
+		}
+	}
+
+	ipAttempt, err := s.loginAttemptRepo.IncrementLoginAttempt(ctx, ipKey(clientIP), LOGIN_ATTEMPT_WINDOW)
+	if err != nil {
+		logger.FromContext(ctx).Errorf("[login-guard] failed to record attempt for ip %s: %v", clientIP, err)
+	} else if ipAttempt.Failures >= LOGIN_MAX_IP_ATTEMPTS {
+		s.lock(ctx, ipKey(clientIP), ipAttempt.Failures, clientIP)
+	}
+}
+
+func (s *loginGuardService) RegisterSuccess(ctx context.Context, email string, clientIP string) {
+	// The IP counter is left alone: one valid account must not reset a spraying client
+	if err := s.loginAttemptRepo.ResetLoginAttempt(ctx, accountKey(email)); err != nil {
+		logger.FromContext(ctx).Errorf("[login-guard] failed to reset attempts for %s: %v", email, err)
+	}
+}
+
+// Unlock consumes an unlock link and clears the account's failure counter.
+// It returns the unlocked user.
+func (s *loginGuardService) Unlock(ctx context.Context, token string) (uuid.UUID, error) {
+	if token == "" {
+		return uuid.Nil, dto.ErrTokenInvalid
+	}
+
+	unlockToken, err := s.unlockTokenRepo.GetAccountUnlockTokenByHash(ctx, nil, helpers.HashToken(token))
+	if err != nil {
+		return uuid.Nil, dto.ErrTokenInvalid
+	}
+
+	if unlockToken.ConsumedAt != nil {
+		return uuid.Nil, dto.ErrTokenInvalid
+	}
+
+	if time.Now().After(unlockToken.ExpiresAt) {
+		return uuid.Nil, dto.ErrTokenExpired
+	}
+
+	consumed, err := s.unlockTokenRepo.ConsumeAccountUnlockToken(ctx, nil, unlockToken.ID)
+	if err != nil || !consumed {
+		return uuid.Nil, dto.ErrTokenInvalid
+	}
+
+	user, err := s.userRepository.GetUserByID(ctx, nil, unlockToken.UserID)
+	if err != nil {
+		return uuid.Nil, dto.ErrUserNotFound
+	}
+
+	if err := s.loginAttemptRepo.ResetLoginAttempt(ctx, accountKey(user.Email)); err != nil {
+		return uuid.Nil, err
+	}
+
+	logger.FromContext(ctx).Infof("[login-guard] account %s unlocked via email link", user.Email)
+	return user.ID, nil
+}
+
+func (s *loginGuardService) lock(ctx context.Context, key string, failures int, clientIP string) {
+	until := time.Now().Add(LOGIN_LOCKOUT_DURATION)
+	if err := s.loginAttemptRepo.LockLoginAttempt(ctx, key, until); err != nil {
+		logger.FromContext(ctx).Errorf("[login-guard] failed to lock %s: %v", key, err)
+		return
+	}
+
+	logger.FromContext(ctx).Warnf("[login-guard] locked %s until %s after %d failed attempts (last from %s)", key, until.Format(time.RFC3339), failures, clientIP)
+}
+
+// sendUnlockEmail queues an unlock link for a verified account, the outbox
+// worker delivers it.
+func (s *loginGuardService) sendUnlockEmail(ctx context.Context, email string) {
+	user, _, err := s.userRepository.GetUserByEmail(ctx, nil, email)
+	if err != nil || !user.IsVerified {
+		return
+	}
+
+	token, err := helpers.GenerateRandomToken(32)
+	if err != nil {
+		logger.FromContext(ctx).Errorf("[login-guard] failed to create unlock token: %v", err)
+		return
+	}
+
+	lockedUntil := time.Now().Add(LOGIN_LOCKOUT_DURATION)
+	data := map[string]any{
+		"Email":       user.Email,
+		"Verify":      s.cfg.App.URL + "/" + UNLOCK_ACCOUNT_PATH + "?token=" + token,
+		"LockedUntil": lockedUntil.Format("02 Jan 2006 15:04"),
+	}
+
+	msg, err := s.mailer.MakeMail(UNLOCK_ACCOUNT_TEMPLATE, emailLocale(ctx, user), data).NewMessage().To(user.Email).Build()
+	if err != nil {
+		logger.FromContext(ctx).Errorf("[login-guard] failed to make unlock email: %v", err)
+		return
+	}
+
+	tx := s.db.WithContext(ctx).Begin()
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+			panic(r)
+		}
+	}()
+
+	// Only the most recent link may be used
+	if err := s.unlockTokenRepo.ConsumeUserAccountUnlockTokens(ctx, tx, user.ID); err != nil {
+		tx.Rollback()
+		logger.FromContext(ctx).Errorf("[login-guard] failed to revoke unlock tokens of %s: %v", user.Email, err)
+		return
+	}
+
+	// The link is useless once the lock has run out
+	if _, err := s.unlockTokenRepo.CreateAccountUnlockToken(ctx, tx, entity.AccountUnlockToken{
+		UserID:    user.ID,
+		TokenHash: helpers.HashToken(token),
+		ExpiresAt: lockedUntil,
+	}); err != nil {
+		tx.Rollback()
+		logger.FromContext(ctx).Errorf("[login-guard] failed to create unlock token: %v", err)
+		return
+	}
+
+	if err := s.emailOutbox.Enqueue(ctx, tx, msg); err != nil {
+		tx.Rollback()
+		logger.FromContext(ctx).Errorf("[login-guard] failed to queue unlock email to %s: %v", user.Email, err)
+		return
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		logger.FromContext(ctx).Errorf("[login-guard] failed to queue unlock email to %s: %v", user.Email, err)
+	}
+}
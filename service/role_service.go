@@ -0,0 +1,396 @@
+package service
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Shabrinashsf/go-gin-gorm-boilerplate/dto"
+	"github.com/Shabrinashsf/go-gin-gorm-boilerplate/entity"
+	"github.com/Shabrinashsf/go-gin-gorm-boilerplate/repository"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type (
+	RoleService interface {
+		GetUserPermissions(ctx context.Context, userId uuid.UUID) ([]string, error)
+		HasPermission(ctx context.Context, userId uuid.UUID, permission string) bool
+		GetAllRoles(ctx context.Context) ([]dto.RoleResponse, error)
+		CreateRole(ctx context.Context, actorId uuid.UUID, req dto.CreateRoleRequest) (dto.RoleResponse, error)
+		UpdateRole(ctx context.Context, actorId uuid.UUID, roleId uuid.UUID, req dto.UpdateRoleRequest) (dto.RoleResponse, error)
+		DeleteRole(ctx context.Context, actorId uuid.UUID, roleId uuid.UUID) error
+		GetAllPermissions(ctx context.Context) ([]dto.PermissionResponse, error)
+		CreatePermission(ctx context.Context, actorId uuid.UUID, req dto.CreatePermissionRequest) (dto.PermissionResponse, error)
+		AssignUserRoles(ctx context.Context, actorId uuid.UUID, userId uuid.UUID, req dto.AssignRolesRequest) (dto.UserRolesResponse, error)
+		InvalidateUser(userId uuid.UUID)
+	}
+
+	permissionCacheEntry struct {
+		permissions []string
+		expiresAt   time.Time
+	}
+
+	roleService struct {
+		roleRepository repository.RoleRepository
+		userRepository repository.UserRepository
+		auditService   AuditService
+		db             *gorm.DB
+
+		mu    sync.RWMutex
+		cache map[uuid.UUID]permissionCacheEntry
+	}
+)
+
+func NewRoleService(rr repository.RoleRepository, ur repository.UserRepository, as AuditService, db *gorm.DB) RoleService {
+	return &roleService{
+		roleRepository: rr,
+		userRepository: ur,
+		auditService:   as,
+		db:             db,
+		cache:          make(map[uuid.UUID]permissionCacheEntry),
+	}
+}
+
+var (
+	PERMISSION_CACHE_TTL = time.Minute * 1
+
+	// Roles mirrored by entity.UserRole, they back OnlyAllow and cannot be deleted
+	BUILT_IN_ROLES = []string{string(entity.RoleAdmin), string(entity.RoleUser)}
+)
+
+// MatchPermission reports whether any granted permission covers required,
+// honouring "*" and "<resource>:*" wildcards.
+func MatchPermission(granted []string, required string) bool {
+	resource, _, _ := strings.Cut(required, ":")
+
+	for _, permission := range granted {
+		if permission == entity.PermissionAll || permission == required || permission == resource+":*" {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *roleService) GetUserPermissions(ctx context.Context, userId uuid.UUID) ([]string, error) {
+	s.mu.RLock()
+	entry, ok := s.cache[userId]
+	s.mu.RUnlock()
+	if ok && time.Now().Before(entry.expiresAt) {
+		return entry.permissions, nil
+	}
+
+	user, err := s.userRepository.GetUserByID(ctx, nil, userId)
+	if err != nil {
+		return nil, dto.ErrUserNotFound
+	}
+
+	permissions, err := s.roleRepository.GetUserPermissionNames(ctx, nil, userId, string(user.Role))
+	if err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	s.cache[userId] = permissionCacheEntry{
+		permissions: permissions,
+		expiresAt:   time.Now().Add(PERMISSION_CACHE_TTL),
+	}
+	s.mu.Unlock()
+
+	return permissions, nil
+}
+
+func (s *roleService) HasPermission(ctx context.Context, userId uuid.UUID, permission string) bool {
+	permissions, err := s.GetUserPermissions(ctx, userId)
+	if err != nil {
+		return false
+	}
+	return MatchPermission(permissions, permission)
+}
+
+func (s *roleService) GetAllRoles(ctx context.Context) ([]dto.RoleResponse, error) {
+	roles, err := s.roleRepository.GetAllRoles(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]dto.RoleResponse, 0, len(roles))
+	for _, role := range roles {
+		result = append(result, toRoleResponse(role))
+	}
+
+	return result, nil
+}
+
+func (s *roleService) CreateRole(ctx context.Context, actorId uuid.UUID, req dto.CreateRoleRequest) (dto.RoleResponse, error) {
+	if existing, _ := s.roleRepository.GetRolesByNames(ctx, nil, []string{req.Name}); len(existing) > 0 {
+		return dto.RoleResponse{}, dto.ErrRoleAlreadyExists
+	}
+
+	permissions, err := s.resolvePermissions(ctx, req.Permissions)
+	if err != nil {
+		return dto.RoleResponse{}, err
+	}
+
+	role, err := s.roleRepository.CreateRole(ctx, nil, entity.Role{
+		Name:        req.Name,
+		Description: req.Description,
+		Permissions: permissions,
+	})
+	if err != nil {
+		return dto.RoleResponse{}, err
+	}
+
+	result := toRoleResponse(role)
+	s.record(ctx, actorId, entity.AuditActionRoleCreate, "role", role.ID, auditChanges(map[string]any{}, map[string]any{
+		"name":        result.Name,
+		"description": result.Description,
+		"permissions": result.Permissions,
+	}))
+
+	return result, nil
+}
+
+func (s *roleService) UpdateRole(ctx context.Context, actorId uuid.UUID, roleId uuid.UUID, req dto.UpdateRoleRequest) (dto.RoleResponse, error) {
+	role, err := s.roleRepository.GetRoleByID(ctx, nil, roleId)
+	if err != nil {
+		return dto.RoleResponse{}, err
+	}
+
+	before := toRoleResponse(role)
+
+	permissions, err := s.resolvePermissions(ctx, req.Permissions)
+	if err != nil {
+		return dto.RoleResponse{}, err
+	}
+
+	role.Description = req.Description
+	role.Permissions = permissions
+
+	updatedRole, err := s.roleRepository.UpdateRole(ctx, nil, role)
+	if err != nil {
+		return dto.RoleResponse{}, err
+	}
+
+	s.invalidateAll()
+
+	result := toRoleResponse(updatedRole)
+	s.record(ctx, actorId, entity.AuditActionRoleUpdate, "role", roleId, auditChanges(map[string]any{
+		"description": before.Description,
+		"permissions": before.Permissions,
+	}, map[string]any{
+		"description": result.Description,
+		"permissions": result.Permissions,
+	}))
+
+	return result, nil
+}
+
+func (s *roleService) DeleteRole(ctx context.Context, actorId uuid.UUID, roleId uuid.UUID) error {
+	role, err := s.roleRepository.GetRoleByID(ctx, nil, roleId)
+	if err != nil {
+		return err
+	}
+
+	for _, builtIn := range BUILT_IN_ROLES {
+		if role.Name == builtIn {
+			return dto.ErrBuiltInRole
+		}
+	}
+
+	tx := s.db.WithContext(ctx).Begin()
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+			panic(r)
+		}
+	}()
+
+	if err := s.roleRepository.DeleteRole(ctx, tx, roleId); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		return err
+	}
+
+	s.invalidateAll()
+
+	deleted := toRoleResponse(role)
+	s.record(ctx, actorId, entity.AuditActionRoleDelete, "role", roleId, auditChanges(map[string]any{
+		"name":        deleted.Name,
+		"permissions": deleted.Permissions,
+	}, map[string]any{
+		"name":        nil,
+		"permissions": nil,
+	}))
+
+	return nil
+}
+
+func (s *roleService) GetAllPermissions(ctx context.Context) ([]dto.PermissionResponse, error) {
+	permissions, err := s.roleRepository.GetAllPermissions(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]dto.PermissionResponse, 0, len(permissions))
+	for _, permission := range permissions {
+		result = append(result, dto.PermissionResponse{
+			ID:          permission.ID.String(),
+			Name:        permission.Name,
+			Description: permission.Description,
+		})
+	}
+
+	return result, nil
+}
+
+func (s *roleService) CreatePermission(ctx context.Context, actorId uuid.UUID, req dto.CreatePermissionRequest) (dto.PermissionResponse, error) {
+	if req.Name != entity.PermissionAll && !strings.Contains(req.Name, ":") {
+		return dto.PermissionResponse{}, dto.ErrInvalidPermission
+	}
+
+	if existing, _ := s.roleRepository.GetPermissionsByNames(ctx, nil, []string{req.Name}); len(existing) > 0 {
+		return dto.PermissionResponse{}, dto.ErrPermissionExists
+	}
+
+	permission, err := s.roleRepository.CreatePermission(ctx, nil, entity.Permission{
+		Name:        req.Name,
+		Description: req.Description,
+	})
+	if err != nil {
+		return dto.PermissionResponse{}, err
+	}
+
+	s.record(ctx, actorId, entity.AuditActionPermissionCreate, "permission", permission.ID, auditChanges(map[string]any{}, map[string]any{
+		"name":        permission.Name,
+		"description": permission.Description,
+	}))
+
+	return dto.PermissionResponse{
+		ID:          permission.ID.String(),
+		Name:        permission.Name,
+		Description: permission.Description,
+	}, nil
+}
+
+func (s *roleService) AssignUserRoles(ctx context.Context, actorId uuid.UUID, userId uuid.UUID, req dto.AssignRolesRequest) (dto.UserRolesResponse, error) {
+	if _, err := s.userRepository.GetUserByID(ctx, nil, userId); err != nil {
+		return dto.UserRolesResponse{}, dto.ErrUserNotFound
+	}
+
+	roles := []entity.Role{}
+	if len(req.Roles) > 0 {
+		found, err := s.roleRepository.GetRolesByNames(ctx, nil, req.Roles)
+		if err != nil {
+			return dto.UserRolesResponse{}, err
+		}
+		if len(found) != len(uniqueStrings(req.Roles)) {
+			return dto.UserRolesResponse{}, dto.ErrUnknownRoleAssigned
+		}
+		roles = found
+	}
+
+	before, err := s.roleRepository.GetUserRoleNames(ctx, nil, userId)
+	if err != nil {
+		return dto.UserRolesResponse{}, err
+	}
+
+	if err := s.roleRepository.ReplaceUserRoles(ctx, nil, userId, roles); err != nil {
+		return dto.UserRolesResponse{}, err
+	}
+
+	s.InvalidateUser(userId)
+
+	names, err := s.roleRepository.GetUserRoleNames(ctx, nil, userId)
+	if err != nil {
+		return dto.UserRolesResponse{}, err
+	}
+
+	s.record(ctx, actorId, entity.AuditActionRoleAssign, "user", userId, auditChanges(map[string]any{"roles": before}, map[string]any{"roles": names}))
+
+	permissions, err := s.GetUserPermissions(ctx, userId)
+	if err != nil {
+		return dto.UserRolesResponse{}, err
+	}
+
+	return dto.UserRolesResponse{
+		UserID:      userId.String(),
+		Roles:       names,
+		Permissions: permissions,
+	}, nil
+}
+
+func (s *roleService) resolvePermissions(ctx context.Context, names []string) ([]entity.Permission, error) {
+	if len(names) == 0 {
+		return []entity.Permission{}, nil
+	}
+
+	permissions, err := s.roleRepository.GetPermissionsByNames(ctx, nil, names)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(permissions) != len(uniqueStrings(names)) {
+		return nil, dto.ErrUnknownPermissionSet
+	}
+
+	return permissions, nil
+}
+
+// record writes the audit entry of a change to roles, permissions or role
+// assignments.
+func (s *roleService) record(ctx context.Context, actorId uuid.UUID, action entity.AuditAction, targetType string, targetId uuid.UUID, changes string) {
+	s.auditService.Record(ctx, entity.AuditLog{
+		ActorID:    auditActor(actorId),
+		Action:     action,
+		TargetType: targetType,
+		TargetID:   targetId.String(),
+		Success:    true,
+		Changes:    changes,
+	})
+}
+
+// InvalidateUser drops the cached permissions of a user whose role or account
+// state changed outside this service.
+func (s *roleService) InvalidateUser(userId uuid.UUID) {
+	s.mu.Lock()
+	delete(s.cache, userId)
+	s.mu.Unlock()
+}
+
+func (s *roleService) invalidateAll() {
+	s.mu.Lock()
+	s.cache = make(map[uuid.UUID]permissionCacheEntry)
+	s.mu.Unlock()
+}
+
+func toRoleResponse(role entity.Role) dto.RoleResponse {
+	permissions := make([]string, 0, len(role.Permissions))
+	for _, permission := range role.Permissions {
+		permissions = append(permissions, permission.Name)
+	}
+
+	return dto.RoleResponse{
+		ID:          role.ID.String(),
+		Name:        role.Name,
+		Description: role.Description,
+		Permissions: permissions,
+	}
+}
+
+func uniqueStrings(values []string) []string {
+	seen := make(map[string]struct{}, len(values))
+	result := make([]string, 0, len(values))
+	for _, v := range values {
+		if _, ok := seen[v]; ok {
+			continue
+		}
+		seen[v] = struct{}{}
+		result = append(result, v)
+	}
+	return result
+}
@@ -0,0 +1,140 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/Shabrinashsf/go-gin-gorm-boilerplate/dto"
+	"github.com/Shabrinashsf/go-gin-gorm-boilerplate/entity"
+	"github.com/Shabrinashsf/go-gin-gorm-boilerplate/helpers"
+	"github.com/Shabrinashsf/go-gin-gorm-boilerplate/repository"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+const refreshTokenTTL = time.Hour * 24 * 7
+
+type (
+	SessionService interface {
+		CreateSession(ctx context.Context, userId uuid.UUID) (dto.SessionToken, error)
+		RotateSession(ctx context.Context, refreshToken string) (dto.SessionToken, error)
+		RevokeSession(ctx context.Context, sessionId uuid.UUID) error
+		RevokeUserSessions(ctx context.Context, userId uuid.UUID, exceptSessionId *uuid.UUID) error
+		IsSessionActive(ctx context.Context, sessionId uuid.UUID) bool
+	}
+
+	sessionService struct {
+		refreshTokenRepo repository.RefreshTokenRepository
+		db               *gorm.DB
+	}
+)
+
+func NewSessionService(rtr repository.RefreshTokenRepository, db *gorm.DB) SessionService {
+	return &sessionService{
+		refreshTokenRepo: rtr,
+		db:               db,
+	}
+}
+
+func (s *sessionService) CreateSession(ctx context.Context, userId uuid.UUID) (dto.SessionToken, error) {
+	return s.issueRefreshToken(ctx, nil, userId, uuid.New())
+}
+
+func (s *sessionService) RotateSession(ctx context.Context, refreshToken string) (dto.SessionToken, error) {
+	tx := s.db.WithContext(ctx).Begin()
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+			panic(r)
+		}
+	}()
+
+	current, err := s.refreshTokenRepo.GetRefreshTokenByHash(ctx, tx, helpers.HashToken(refreshToken))
+	if err != nil {
+		tx.Rollback()
+		return dto.SessionToken{}, dto.ErrRefreshTokenInvalid
+	}
+
+	// A revoked token being presented again means it was copied somewhere;
+	// kill the whole session so neither party can keep using it.
+	if current.RevokedAt != nil {
+		tx.Rollback()
+		if err := s.refreshTokenRepo.RevokeSession(ctx, nil, current.SessionID); err != nil {
+			return dto.SessionToken{}, err
+		}
+		return dto.SessionToken{}, dto.ErrRefreshTokenReused
+	}
+
+	if time.Now().After(current.ExpiresAt) {
+		tx.Rollback()
+		return dto.SessionToken{}, dto.ErrRefreshTokenExpired
+	}
+
+	next, err := s.issueRefreshToken(ctx, tx, current.UserID, current.SessionID)
+	if err != nil {
+		tx.Rollback()
+		return dto.SessionToken{}, err
+	}
+
+	revoked, err := s.refreshTokenRepo.RevokeRefreshToken(ctx, tx, current.ID, &next.TokenID)
+	if err != nil {
+		tx.Rollback()
+		return dto.SessionToken{}, err
+	}
+
+	// A concurrent refresh revoked it first, the same token was used twice
+	if !revoked {
+		tx.Rollback()
+		if err := s.refreshTokenRepo.RevokeSession(ctx, nil, current.SessionID); err != nil {
+			return dto.SessionToken{}, err
+		}
+		return dto.SessionToken{}, dto.ErrRefreshTokenReused
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		return dto.SessionToken{}, err
+	}
+
+	return next, nil
+}
+
+func (s *sessionService) RevokeSession(ctx context.Context, sessionId uuid.UUID) error {
+	return s.refreshTokenRepo.RevokeSession(ctx, nil, sessionId)
+}
+
+func (s *sessionService) RevokeUserSessions(ctx context.Context, userId uuid.UUID, exceptSessionId *uuid.UUID) error {
+	return s.refreshTokenRepo.RevokeUserSessions(ctx, nil, userId, exceptSessionId)
+}
+
+func (s *sessionService) IsSessionActive(ctx context.Context, sessionId uuid.UUID) bool {
+	active, err := s.refreshTokenRepo.IsSessionActive(ctx, nil, sessionId)
+	if err != nil {
+		return false
+	}
+	return active
+}
+
+func (s *sessionService) issueRefreshToken(ctx context.Context, tx *gorm.DB, userId uuid.UUID, sessionId uuid.UUID) (dto.SessionToken, error) {
+	plain, err := helpers.GenerateRandomToken(32)
+	if err != nil {
+		return dto.SessionToken{}, err
+	}
+
+	token, err := s.refreshTokenRepo.CreateRefreshToken(ctx, tx, entity.RefreshToken{
+		UserID:    userId,
+		SessionID: sessionId,
+		TokenHash: helpers.HashToken(plain),
+		ExpiresAt: time.Now().Add(refreshTokenTTL),
+	})
+	if err != nil {
+		return dto.SessionToken{}, err
+	}
+
+	return dto.SessionToken{
+		TokenID:      token.ID,
+		SessionID:    token.SessionID,
+		UserID:       token.UserID,
+		RefreshToken: plain,
+		ExpiresAt:    token.ExpiresAt,
+	}, nil
+}
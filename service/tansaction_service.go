@@ -5,11 +5,14 @@ import (
 	"crypto/hmac"
 	"crypto/sha256"
 	"encoding/hex"
-	"os"
+	"errors"
 	"strings"
 
+	"github.com/Shabrinashsf/go-gin-gorm-boilerplate/config"
 	"github.com/Shabrinashsf/go-gin-gorm-boilerplate/dto"
+	"github.com/Shabrinashsf/go-gin-gorm-boilerplate/entity"
 	"github.com/Shabrinashsf/go-gin-gorm-boilerplate/repository"
+	"github.com/Shabrinashsf/go-gin-gorm-boilerplate/utils/metrics"
 	"github.com/google/uuid"
 	"gorm.io/gorm"
 )
@@ -22,19 +25,27 @@ type (
 
 	transactionService struct {
 		transactionRepo repository.TransactionRepository
+		auditService    AuditService
+		cfg             *config.Config
 		db              *gorm.DB
 	}
 )
 
-func NewTransactionService(transactionRepo repository.TransactionRepository, db *gorm.DB) TransactionService {
+func NewTransactionService(transactionRepo repository.TransactionRepository, as AuditService, cfg *config.Config, db *gorm.DB) TransactionService {
 	return &transactionService{
 		transactionRepo: transactionRepo,
+		auditService:    as,
+		cfg:             cfg,
 		db:              db,
 	}
 }
 
-func (s *transactionService) TripayWebhook(ctx context.Context, rawBody []byte, payload dto.TripayWebhookRequest, callbackSignature string, event string) (dto.TripayWebhookResponse, error) {
-	privateKey := os.Getenv("TRIPAY_PRIVATE_KEY")
+func (s *transactionService) TripayWebhook(ctx context.Context, rawBody []byte, payload dto.TripayWebhookRequest, callbackSignature string, event string) (res dto.TripayWebhookResponse, err error) {
+	defer func() {
+		metrics.TripayWebhook(payload.Status, tripayWebhookOutcome(err))
+	}()
+
+	privateKey := s.cfg.Tripay.PrivateKey
 
 	if event != "payment_status" {
 		return dto.TripayWebhookResponse{}, dto.ErrUnrecognizedCallbackEvent
@@ -61,17 +72,18 @@ func (s *transactionService) TripayWebhook(ctx context.Context, rawBody []byte,
 	}
 
 	// update status transaksi
+	oldStatus := transaction.Status
 	switch strings.ToUpper(payload.Status) {
 	case "PAID":
 		transaction.Status = "PAID"
 		transaction.AmountPaid = payload.TotalAmount
 		if err := s.transactionRepo.UpdateTransaction(ctx, nil, transaction); err != nil {
-			return dto.TripayWebhookResponse{}, dto.ErrFailedToUpdateStatus
+			return dto.TripayWebhookResponse{}, dto.ErrFailedToUpdateStatus.Wrap(err)
 		}
 	case "FAILED":
 		transaction.Status = "FAILED"
 		if err := s.transactionRepo.UpdateTransaction(ctx, nil, transaction); err != nil {
-			return dto.TripayWebhookResponse{}, dto.ErrFailedToUpdateStatus
+			return dto.TripayWebhookResponse{}, dto.ErrFailedToUpdateStatus.Wrap(err)
 		}
 	case "EXPIRED":
 		if transaction.Status == "PAID" {
@@ -82,26 +94,63 @@ func (s *transactionService) TripayWebhook(ctx context.Context, rawBody []byte,
 		}
 		transaction.Status = "EXPIRED"
 		if err := s.transactionRepo.UpdateTransaction(ctx, nil, transaction); err != nil {
-			return dto.TripayWebhookResponse{}, dto.ErrFailedToUpdateStatus
+			return dto.TripayWebhookResponse{}, dto.ErrFailedToUpdateStatus.Wrap(err)
 		}
 
 		if err := s.transactionRepo.SoftDeleteTransaction(ctx, nil, transaction.ID); err != nil {
-			return dto.TripayWebhookResponse{}, dto.ErrFailedToSoftDeleteTransaction
+			return dto.TripayWebhookResponse{}, dto.ErrFailedToSoftDeleteTransaction.Wrap(err)
 		}
 	case "REFUND":
 		transaction.Status = "REFUND"
 		if err := s.transactionRepo.UpdateTransaction(ctx, nil, transaction); err != nil {
-			return dto.TripayWebhookResponse{}, dto.ErrFailedToUpdateStatus
+			return dto.TripayWebhookResponse{}, dto.ErrFailedToUpdateStatus.Wrap(err)
 		}
 	default:
 		return dto.TripayWebhookResponse{}, dto.ErrUnknownStatus
 	}
 
+	// dikirim oleh Tripay, tidak ada actor
+	s.auditService.Record(ctx, entity.AuditLog{
+		Action:     entity.AuditActionTransactionStatus,
+		TargetType: "transaction",
+		TargetID:   transaction.ID.String(),
+		Success:    true,
+		Changes:    auditChanges(map[string]any{"status": oldStatus}, map[string]any{"status": transaction.Status}),
+	})
+
 	return dto.TripayWebhookResponse{
 		Success: true,
 	}, nil
 }
 
+// tripayWebhookOutcome groups webhook errors into a small set of metric labels.
+func tripayWebhookOutcome(err error) string {
+	switch {
+	case err == nil:
+		return "processed"
+	case errors.Is(err, dto.ErrInvalidSignature),
+		errors.Is(err, dto.ErrUnrecognizedCallbackEvent),
+		errors.Is(err, dto.ErrOnlyClosedPaymentSupported),
+		errors.Is(err, dto.ErrUnknownStatus):
+		return "rejected"
+	case errors.Is(err, dto.ErrTransactionNotFound):
+		return "not_found"
+	default:
+		return "failed"
+	}
+}
+
 func (s *transactionService) SoftDeleteTransaction(ctx context.Context, id uuid.UUID) error {
-	return s.transactionRepo.SoftDeleteTransaction(ctx, nil, id)
+	if err := s.transactionRepo.SoftDeleteTransaction(ctx, nil, id); err != nil {
+		return err
+	}
+
+	s.auditService.Record(ctx, entity.AuditLog{
+		Action:     entity.AuditActionTransactionDelete,
+		TargetType: "transaction",
+		TargetID:   id.String(),
+		Success:    true,
+	})
+
+	return nil
 }
@@ -0,0 +1,532 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base32"
+	"encoding/base64"
+	"errors"
+	"image/png"
+	"strings"
+	"time"
+
+	"github.com/Shabrinashsf/go-gin-gorm-boilerplate/config"
+	"github.com/Shabrinashsf/go-gin-gorm-boilerplate/dto"
+	"github.com/Shabrinashsf/go-gin-gorm-boilerplate/entity"
+	"github.com/Shabrinashsf/go-gin-gorm-boilerplate/helpers"
+	"github.com/Shabrinashsf/go-gin-gorm-boilerplate/repository"
+	"github.com/Shabrinashsf/go-gin-gorm-boilerplate/utils"
+	"github.com/Shabrinashsf/go-gin-gorm-boilerplate/utils/logger"
+	"github.com/Shabrinashsf/go-gin-gorm-boilerplate/utils/metrics"
+	"github.com/google/uuid"
+	"github.com/pquerna/otp"
+	"github.com/pquerna/otp/totp"
+	"gorm.io/gorm"
+)
+
+type (
+	TwoFactorService interface {
+		Setup(ctx context.Context, userId uuid.UUID) (dto.TwoFactorSetupResponse, error)
+		Confirm(ctx context.Context, userId uuid.UUID, req dto.TwoFactorConfirmRequest) (dto.TwoFactorConfirmResponse, error)
+		Disable(ctx context.Context, userId uuid.UUID, req dto.TwoFactorDisableRequest) error
+		Verify(ctx context.Context, req dto.TwoFactorVerifyRequest, clientIP string) (dto.UserLoginResponse, error)
+		EnrollSetup(ctx context.Context, req dto.TwoFactorChallengeRequest) (dto.TwoFactorSetupResponse, error)
+		EnrollConfirm(ctx context.Context, req dto.TwoFactorEnrollConfirmRequest, clientIP string) (dto.TwoFactorEnrollConfirmResponse, error)
+	}
+
+	twoFactorService struct {
+		userRepository   repository.UserRepository
+		recoveryCodeRepo repository.RecoveryCodeRepository
+		challengeRepo    repository.TwoFactorChallengeRepository
+		jwtService       JWTService
+		sessionService   SessionService
+		roleService      RoleService
+		loginGuard       LoginGuardService
+		auditService     AuditService
+		cfg              *config.Config
+		db               *gorm.DB
+	}
+)
+
+func NewTwoFactorService(ur repository.UserRepository, rcr repository.RecoveryCodeRepository, tfcr repository.TwoFactorChallengeRepository, jwt JWTService, ss SessionService, rs RoleService, lg LoginGuardService, as AuditService, cfg *config.Config, db *gorm.DB) TwoFactorService {
+	return &twoFactorService{
+		userRepository:   ur,
+		recoveryCodeRepo: rcr,
+		challengeRepo:    tfcr,
+		jwtService:       jwt,
+		sessionService:   ss,
+		roleService:      rs,
+		loginGuard:       lg,
+		auditService:     as,
+		cfg:              cfg,
+		db:               db,
+	}
+}
+
+var (
+	TWO_FACTOR_ISSUER           = "Backend Boilerplate"
+	TWO_FACTOR_CHALLENGE_VERIFY = "2fa_verify"
+	TWO_FACTOR_CHALLENGE_ENROLL = "2fa_enroll"
+	TWO_FACTOR_RECOVERY_CODES   = 10
+
+	// A challenge is burned after this many wrong codes, the account and
+	// client IP are additionally counted by the login guard
+	TWO_FACTOR_MAX_CHALLENGE_FAILURES = 5
+
+	TOTP_PERIOD = time.Second * 30
+)
+
+// issueChallengeToken stores a single-use challenge for a user who passed the
+// password step and returns the token referencing it.
+func issueChallengeToken(ctx context.Context, challengeRepo repository.TwoFactorChallengeRepository, jwtService JWTService, user entity.User, purpose string) (string, error) {
+	challenge, err := challengeRepo.CreateChallenge(ctx, nil, entity.TwoFactorChallenge{
+		UserID:    user.ID,
+		Purpose:   purpose,
+		ExpiresAt: time.Now().Add(challengeTokenTTL),
+	})
+	if err != nil {
+		return "", err
+	}
+
+	return jwtService.GenerateChallengeToken(user.ID.String(), purpose, challenge.ID.String()), nil
+}
+
+// isTwoFactorEnforced reports whether the user must enroll before being able to log in.
+func isTwoFactorEnforced(cfg *config.Config, user entity.User) bool {
+	return user.Role == entity.RoleAdmin && cfg.Security.TwoFactorEnforceAdmin
+}
+
+func (s *twoFactorService) Setup(ctx context.Context, userId uuid.UUID) (dto.TwoFactorSetupResponse, error) {
+	user, err := s.userRepository.GetUserByID(ctx, nil, userId)
+	if err != nil {
+		return dto.TwoFactorSetupResponse{}, dto.ErrUserNotFound
+	}
+
+	return s.setup(ctx, user)
+}
+
+func (s *twoFactorService) Confirm(ctx context.Context, userId uuid.UUID, req dto.TwoFactorConfirmRequest) (dto.TwoFactorConfirmResponse, error) {
+	user, err := s.userRepository.GetUserByID(ctx, nil, userId)
+	if err != nil {
+		return dto.TwoFactorConfirmResponse{}, dto.ErrUserNotFound
+	}
+
+	codes, err := s.confirm(ctx, user, req.Code)
+	if err != nil {
+		return dto.TwoFactorConfirmResponse{}, err
+	}
+
+	return dto.TwoFactorConfirmResponse{
+		RecoveryCodes: codes,
+	}, nil
+}
+
+func (s *twoFactorService) Disable(ctx context.Context, userId uuid.UUID, req dto.TwoFactorDisableRequest) error {
+	user, err := s.userRepository.GetUserByID(ctx, nil, userId)
+	if err != nil {
+		return dto.ErrUserNotFound
+	}
+
+	if !user.TwoFactorEnabled {
+		return dto.ErrTwoFactorNotEnabled
+	}
+
+	if isTwoFactorEnforced(s.cfg, user) {
+		return dto.ErrTwoFactorEnforced
+	}
+
+	checkPassword, err := helpers.CheckPassword(user.Password, []byte(req.Password))
+	if err != nil || !checkPassword {
+		return dto.ErrInvalidCredentials
+	}
+
+	if ok, err := s.useCode(ctx, user, req.Code); err != nil {
+		return err
+	} else if !ok {
+		return dto.ErrTwoFactorCodeInvalid
+	}
+
+	tx := s.db.WithContext(ctx).Begin()
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+			panic(r)
+		}
+	}()
+
+	updates := map[string]interface{}{}
+	updates["two_factor_enabled"] = false
+	updates["two_factor_secret"] = ""
+
+	if _, err := s.userRepository.UpdateUser(ctx, tx, user.ID, updates); err != nil {
+		tx.Rollback()
+		return dto.ErrUpdateUser.Wrap(err)
+	}
+
+	if err := s.recoveryCodeRepo.DeleteRecoveryCodes(ctx, tx, user.ID); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		return err
+	}
+
+	s.recordTwoFactorChange(ctx, user, entity.AuditActionTwoFactorDisable, false)
+	return nil
+}
+
+func (s *twoFactorService) Verify(ctx context.Context, req dto.TwoFactorVerifyRequest, clientIP string) (dto.UserLoginResponse, error) {
+	user, challenge, err := s.userFromChallenge(ctx, req.ChallengeToken, TWO_FACTOR_CHALLENGE_VERIFY)
+	if err != nil {
+		return dto.UserLoginResponse{}, err
+	}
+
+	if !user.TwoFactorEnabled {
+		return dto.UserLoginResponse{}, dto.ErrTwoFactorNotEnabled
+	}
+
+	if err := s.loginGuard.Check(ctx, user.Email, clientIP); err != nil {
+		return dto.UserLoginResponse{}, err
+	}
+
+	if err := s.consumeChallenge(ctx, challenge); err != nil {
+		return dto.UserLoginResponse{}, err
+	}
+
+	var valid bool
+	switch {
+	case req.Code != "":
+		valid, err = s.useCode(ctx, user, req.Code)
+	case req.RecoveryCode != "":
+		valid, err = s.recoveryCodeRepo.UseRecoveryCode(ctx, nil, user.ID, helpers.HashToken(normalizeRecoveryCode(req.RecoveryCode)))
+	}
+	if err != nil {
+		return dto.UserLoginResponse{}, err
+	}
+	if !valid {
+		s.registerFailure(ctx, user, challenge, clientIP)
+		return dto.UserLoginResponse{}, dto.ErrTwoFactorCodeInvalid
+	}
+
+	login, err := issueLoginTokens(ctx, s.jwtService, s.sessionService, s.roleService, user)
+	if err != nil {
+		return dto.UserLoginResponse{}, err
+	}
+
+	s.loginGuard.RegisterSuccess(ctx, user.Email, clientIP)
+	recordLogin(ctx, s.auditService, user, clientIP)
+	return login, nil
+}
+
+func (s *twoFactorService) EnrollSetup(ctx context.Context, req dto.TwoFactorChallengeRequest) (dto.TwoFactorSetupResponse, error) {
+	user, _, err := s.userFromChallenge(ctx, req.ChallengeToken, TWO_FACTOR_CHALLENGE_ENROLL)
+	if err != nil {
+		return dto.TwoFactorSetupResponse{}, err
+	}
+
+	return s.setup(ctx, user)
+}
+
+func (s *twoFactorService) EnrollConfirm(ctx context.Context, req dto.TwoFactorEnrollConfirmRequest, clientIP string) (dto.TwoFactorEnrollConfirmResponse, error) {
+	user, challenge, err := s.userFromChallenge(ctx, req.ChallengeToken, TWO_FACTOR_CHALLENGE_ENROLL)
+	if err != nil {
+		return dto.TwoFactorEnrollConfirmResponse{}, err
+	}
+
+	if err := s.loginGuard.Check(ctx, user.Email, clientIP); err != nil {
+		return dto.TwoFactorEnrollConfirmResponse{}, err
+	}
+
+	if err := canConfirm(user); err != nil {
+		return dto.TwoFactorEnrollConfirmResponse{}, err
+	}
+
+	if err := s.consumeChallenge(ctx, challenge); err != nil {
+		return dto.TwoFactorEnrollConfirmResponse{}, err
+	}
+
+	codes, err := s.confirm(ctx, user, req.Code)
+	if errors.Is(err, dto.ErrTwoFactorCodeInvalid) {
+		s.registerFailure(ctx, user, challenge, clientIP)
+	}
+	if err != nil {
+		return dto.TwoFactorEnrollConfirmResponse{}, err
+	}
+
+	login, err := issueLoginTokens(ctx, s.jwtService, s.sessionService, s.roleService, user)
+	if err != nil {
+		return dto.TwoFactorEnrollConfirmResponse{}, err
+	}
+
+	s.loginGuard.RegisterSuccess(ctx, user.Email, clientIP)
+	recordLogin(ctx, s.auditService, user, clientIP)
+
+	return dto.TwoFactorEnrollConfirmResponse{
+		UserLoginResponse: login,
+		RecoveryCodes:     codes,
+	}, nil
+}
+
+// userFromChallenge resolves a challenge token to its user and the stored
+// challenge, which must still be open.
+func (s *twoFactorService) userFromChallenge(ctx context.Context, challengeToken string, purpose string) (entity.User, entity.TwoFactorChallenge, error) {
+	userId, challengeId, err := s.jwtService.ValidateChallengeToken(challengeToken, purpose)
+	if err != nil {
+		return entity.User{}, entity.TwoFactorChallenge{}, dto.ErrChallengeTokenInvalid
+	}
+
+	id, err := uuid.Parse(userId)
+	if err != nil {
+		return entity.User{}, entity.TwoFactorChallenge{}, dto.ErrChallengeTokenInvalid
+	}
+
+	cid, err := uuid.Parse(challengeId)
+	if err != nil {
+		return entity.User{}, entity.TwoFactorChallenge{}, dto.ErrChallengeTokenInvalid
+	}
+
+	challenge, err := s.challengeRepo.GetChallengeByID(ctx, nil, cid)
+	if err != nil {
+		return entity.User{}, entity.TwoFactorChallenge{}, err
+	}
+
+	if challenge.UserID != id || challenge.Purpose != purpose || challenge.ConsumedAt != nil || time.Now().After(challenge.ExpiresAt) {
+		return entity.User{}, entity.TwoFactorChallenge{}, dto.ErrChallengeTokenInvalid
+	}
+
+	user, err := s.userRepository.GetUserByID(ctx, nil, id)
+	if err != nil {
+		return entity.User{}, entity.TwoFactorChallenge{}, dto.ErrUserNotFound
+	}
+
+	if user.IsSuspended {
+		return entity.User{}, entity.TwoFactorChallenge{}, dto.ErrAccountSuspended
+	}
+
+	return user, challenge, nil
+}
+
+// registerFailure counts a wrong code against the account and client IP, and
+// against the challenge consumed to check it, which is reopened until
+// TWO_FACTOR_MAX_CHALLENGE_FAILURES and then stays burned.
+func (s *twoFactorService) registerFailure(ctx context.Context, user entity.User, challenge entity.TwoFactorChallenge, clientIP string) {
+	s.loginGuard.RegisterFailure(ctx, user.Email, clientIP)
+	metrics.Login(false)
+	s.auditService.Record(ctx, entity.AuditLog{
+		ActorID:    auditActor(user.ID),
+		Action:     entity.AuditActionLoginFailed,
+		TargetType: "user",
+		TargetID:   user.ID.String(),
+		Success:    false,
+		IPAddress:  clientIP,
+	})
+
+	failed, err := s.challengeRepo.FailChallenge(ctx, nil, challenge.ID, TWO_FACTOR_MAX_CHALLENGE_FAILURES)
+	if err != nil {
+		logger.FromContext(ctx).Errorf("[2fa] failed to record failure for challenge %s: %v", challenge.ID, err)
+		return
+	}
+
+	if failed.ConsumedAt != nil {
+		logger.FromContext(ctx).Warnf("[2fa] challenge %s of user %s burned after %d wrong codes", challenge.ID, user.ID, failed.Failures)
+	}
+}
+
+// consumeChallenge claims the challenge before its code is checked, a
+// concurrent request that got there first makes this one fail.
+func (s *twoFactorService) consumeChallenge(ctx context.Context, challenge entity.TwoFactorChallenge) error {
+	consumed, err := s.challengeRepo.ConsumeChallenge(ctx, nil, challenge.ID)
+	if err != nil {
+		return err
+	}
+	if !consumed {
+		return dto.ErrChallengeTokenInvalid
+	}
+
+	return nil
+}
+
+// setup generates a fresh secret. It is stored right away but only takes
+// effect once confirm has seen a valid code generated from it.
+func (s *twoFactorService) setup(ctx context.Context, user entity.User) (dto.TwoFactorSetupResponse, error) {
+	if user.TwoFactorEnabled {
+		return dto.TwoFactorSetupResponse{}, dto.ErrTwoFactorAlreadyEnabled
+	}
+
+	key, err := totp.Generate(totp.GenerateOpts{
+		Issuer:      TWO_FACTOR_ISSUER,
+		AccountName: user.Email,
+	})
+	if err != nil {
+		return dto.TwoFactorSetupResponse{}, err
+	}
+
+	encryptedSecret, err := utils.AESEncrypt(s.cfg.AES.Key, key.Secret())
+	if err != nil {
+		return dto.TwoFactorSetupResponse{}, err
+	}
+
+	updates := map[string]interface{}{}
+	updates["two_factor_secret"] = encryptedSecret
+
+	if _, err := s.userRepository.UpdateUser(ctx, nil, user.ID, updates); err != nil {
+		return dto.TwoFactorSetupResponse{}, dto.ErrUpdateUser.Wrap(err)
+	}
+
+	img, err := key.Image(256, 256)
+	if err != nil {
+		return dto.TwoFactorSetupResponse{}, err
+	}
+
+	var qr bytes.Buffer
+	if err := png.Encode(&qr, img); err != nil {
+		return dto.TwoFactorSetupResponse{}, err
+	}
+
+	return dto.TwoFactorSetupResponse{
+		Secret:     key.Secret(),
+		OtpauthURL: key.URL(),
+		QRCode:     "data:image/png;base64," + base64.StdEncoding.EncodeToString(qr.Bytes()),
+	}, nil
+}
+
+// canConfirm reports why the user can't confirm a setup, if anything.
+func canConfirm(user entity.User) error {
+	if user.TwoFactorEnabled {
+		return dto.ErrTwoFactorAlreadyEnabled
+	}
+
+	if user.TwoFactorSecret == "" {
+		return dto.ErrTwoFactorNotSetup
+	}
+
+	return nil
+}
+
+func (s *twoFactorService) confirm(ctx context.Context, user entity.User, code string) ([]string, error) {
+	if err := canConfirm(user); err != nil {
+		return nil, err
+	}
+
+	if ok, err := s.useCode(ctx, user, code); err != nil {
+		return nil, err
+	} else if !ok {
+		return nil, dto.ErrTwoFactorCodeInvalid
+	}
+
+	codes, hashes, err := generateRecoveryCodes(TWO_FACTOR_RECOVERY_CODES)
+	if err != nil {
+		return nil, err
+	}
+
+	tx := s.db.WithContext(ctx).Begin()
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+			panic(r)
+		}
+	}()
+
+	updates := map[string]interface{}{}
+	updates["two_factor_enabled"] = true
+
+	if _, err := s.userRepository.UpdateUser(ctx, tx, user.ID, updates); err != nil {
+		tx.Rollback()
+		return nil, dto.ErrUpdateUser.Wrap(err)
+	}
+
+	if err := s.recoveryCodeRepo.ReplaceRecoveryCodes(ctx, tx, user.ID, hashes); err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		return nil, err
+	}
+
+	s.recordTwoFactorChange(ctx, user, entity.AuditActionTwoFactorEnable, true)
+	return codes, nil
+}
+
+// recordTwoFactorChange audits the user turning two-factor authentication on
+// or off for their own account.
+func (s *twoFactorService) recordTwoFactorChange(ctx context.Context, user entity.User, action entity.AuditAction, enabled bool) {
+	s.auditService.Record(ctx, entity.AuditLog{
+		ActorID:    auditActor(user.ID),
+		Action:     action,
+		TargetType: "user",
+		TargetID:   user.ID.String(),
+		Success:    true,
+		Changes:    auditChanges(map[string]any{"two_factor_enabled": !enabled}, map[string]any{"two_factor_enabled": enabled}),
+	})
+}
+
+// useCode checks a TOTP code and records its time step, so the same code is
+// rejected if it is presented again while still inside its window.
+func (s *twoFactorService) useCode(ctx context.Context, user entity.User, code string) (bool, error) {
+	if user.TwoFactorSecret == "" {
+		return false, nil
+	}
+
+	secret, err := utils.AESDecrypt(s.cfg.AES.Key, user.TwoFactorSecret)
+	if err != nil || secret == "" {
+		return false, nil
+	}
+
+	step, ok := matchTOTPStep(secret, code, time.Now())
+	if !ok || step <= user.TwoFactorLastStep {
+		return false, nil
+	}
+
+	return s.userRepository.UseTwoFactorStep(ctx, nil, user.ID, step)
+}
+
+// matchTOTPStep returns the time step code was generated for, allowing one
+// step of clock drift either way like totp.Validate.
+func matchTOTPStep(secret string, code string, now time.Time) (int64, bool) {
+	code = strings.TrimSpace(code)
+	period := int64(TOTP_PERIOD / time.Second)
+
+	for _, skew := range []int64{-1, 0, 1} {
+		step := now.Unix()/period + skew
+		expected, err := totp.GenerateCodeCustom(secret, time.Unix(step*period, 0), totp.ValidateOpts{
+			Period:    uint(period),
+			Digits:    otp.DigitsSix,
+			Algorithm: otp.AlgorithmSHA1,
+		})
+		if err != nil {
+			return 0, false
+		}
+		if subtle.ConstantTimeCompare([]byte(expected), []byte(code)) == 1 {
+			return step, true
+		}
+	}
+
+	return 0, false
+}
+
+// generateRecoveryCodes returns the plain codes to show once and the hashes to store.
+func generateRecoveryCodes(n int) ([]string, []string, error) {
+	codes := make([]string, 0, n)
+	hashes := make([]string, 0, n)
+
+	for i := 0; i < n; i++ {
+		b := make([]byte, 5)
+		if _, err := rand.Read(b); err != nil {
+			return nil, nil, err
+		}
+
+		raw := strings.ToLower(base32.StdEncoding.WithPadding(base32.NoPadding).EncodeToString(b))
+		codes = append(codes, raw[:4]+"-"+raw[4:])
+		hashes = append(hashes, helpers.HashToken(raw))
+	}
+
+	return codes, hashes, nil
+}
+
+func normalizeRecoveryCode(code string) string {
+	return strings.ToLower(strings.ReplaceAll(strings.TrimSpace(code), "-", ""))
+}
@@ -0,0 +1,537 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Shabrinashsf/go-gin-gorm-boilerplate/config"
+	"github.com/Shabrinashsf/go-gin-gorm-boilerplate/dto"
+	"github.com/Shabrinashsf/go-gin-gorm-boilerplate/entity"
+	"github.com/Shabrinashsf/go-gin-gorm-boilerplate/repository"
+	"github.com/Shabrinashsf/go-gin-gorm-boilerplate/utils"
+	"github.com/google/uuid"
+	"github.com/pquerna/otp"
+	"github.com/pquerna/otp/totp"
+	"gorm.io/gorm"
+)
+
+const (
+	testAESKey     = "000102030405060708090a0b0c0d0e0f"
+	testTOTPSecret = "JBSWY3DPEHPK3PXP"
+)
+
+// The fakes embed the interfaces they stand in for, so a call to a method
+// a test didn't expect panics instead of passing silently.
+type (
+	fakeUserRepository struct {
+		repository.UserRepository
+		user      entity.User
+		usedSteps []int64
+		stepTaken bool // UseTwoFactorStep loses the race
+	}
+
+	fakeRecoveryCodeRepository struct {
+		repository.RecoveryCodeRepository
+		hashes map[string]bool
+	}
+
+	fakeChallengeRepository struct {
+		repository.TwoFactorChallengeRepository
+		challenge entity.TwoFactorChallenge
+		failures  int
+		raced     bool // another request consumes the challenge first
+	}
+
+	fakeJWTService struct {
+		JWTService
+		userId      string
+		challengeId string
+		purpose     string
+	}
+
+	fakeSessionService struct {
+		SessionService
+	}
+
+	fakeRoleService struct {
+		RoleService
+	}
+
+	fakeLoginGuard struct {
+		LoginGuardService
+		failures  int
+		successes int
+	}
+
+	fakeAuditService struct {
+		AuditService
+		actions []entity.AuditAction
+	}
+)
+
+func (r *fakeUserRepository) GetUserByID(_ context.Context, _ *gorm.DB, id uuid.UUID) (entity.User, error) {
+	if id != r.user.ID {
+		return entity.User{}, gorm.ErrRecordNotFound
+	}
+	return r.user, nil
+}
+
+func (r *fakeUserRepository) UseTwoFactorStep(_ context.Context, _ *gorm.DB, _ uuid.UUID, step int64) (bool, error) {
+	r.usedSteps = append(r.usedSteps, step)
+	return !r.stepTaken, nil
+}
+
+func (r *fakeRecoveryCodeRepository) UseRecoveryCode(_ context.Context, _ *gorm.DB, _ uuid.UUID, codeHash string) (bool, error) {
+	if !r.hashes[codeHash] {
+		return false, nil
+	}
+	delete(r.hashes, codeHash)
+	return true, nil
+}
+
+func (r *fakeChallengeRepository) GetChallengeByID(_ context.Context, _ *gorm.DB, id uuid.UUID) (entity.TwoFactorChallenge, error) {
+	if id != r.challenge.ID {
+		return entity.TwoFactorChallenge{}, dto.ErrChallengeTokenInvalid
+	}
+	return r.challenge, nil
+}
+
+func (r *fakeChallengeRepository) ConsumeChallenge(_ context.Context, _ *gorm.DB, _ uuid.UUID) (bool, error) {
+	if r.raced || r.challenge.ConsumedAt != nil {
+		return false, nil
+	}
+	now := time.Now()
+	r.challenge.ConsumedAt = &now
+	return true, nil
+}
+
+func (r *fakeChallengeRepository) FailChallenge(_ context.Context, _ *gorm.DB, _ uuid.UUID, maxFailures int) (entity.TwoFactorChallenge, error) {
+	if r.challenge.ConsumedAt == nil {
+		return entity.TwoFactorChallenge{}, nil
+	}
+	r.failures++
+	r.challenge.Failures = r.failures
+	if r.failures < maxFailures {
+		r.challenge.ConsumedAt = nil
+	}
+	return r.challenge, nil
+}
+
+func (j *fakeJWTService) ValidateChallengeToken(token string, purpose string) (string, string, error) {
+	if token != "challenge-token" || purpose != j.purpose {
+		return "", "", errors.New("invalid token")
+	}
+	return j.userId, j.challengeId, nil
+}
+
+func (j *fakeJWTService) GenerateToken(string, string, string, []string) string {
+	return "access-token"
+}
+
+func (s *fakeSessionService) CreateSession(_ context.Context, userId uuid.UUID) (dto.SessionToken, error) {
+	return dto.SessionToken{SessionID: uuid.New(), UserID: userId, RefreshToken: "refresh-token"}, nil
+}
+
+func (s *fakeRoleService) GetUserPermissions(context.Context, uuid.UUID) ([]string, error) {
+	return nil, nil
+}
+
+func (g *fakeLoginGuard) Check(context.Context, string, string) error { return nil }
+
+func (g *fakeLoginGuard) RegisterFailure(context.Context, string, string) { g.failures++ }
+
+func (g *fakeLoginGuard) RegisterSuccess(context.Context, string, string) { g.successes++ }
+
+func (a *fakeAuditService) Record(_ context.Context, auditLog entity.AuditLog) {
+	a.actions = append(a.actions, auditLog.Action)
+}
+
+type twoFactorFixture struct {
+	service       *twoFactorService
+	users         *fakeUserRepository
+	recoveryCodes *fakeRecoveryCodeRepository
+	challenges    *fakeChallengeRepository
+	jwt           *fakeJWTService
+	loginGuard    *fakeLoginGuard
+	audit         *fakeAuditService
+}
+
+// newTwoFactorFixture returns a service with an enabled user and an open
+// verify challenge for it.
+func newTwoFactorFixture(t *testing.T) *twoFactorFixture {
+	t.Helper()
+
+	secret, err := utils.AESEncrypt(testAESKey, testTOTPSecret)
+	if err != nil {
+		t.Fatalf("encrypt secret: %v", err)
+	}
+
+	user := entity.User{
+		ID:               uuid.New(),
+		Email:            "user@example.com",
+		TwoFactorEnabled: true,
+		TwoFactorSecret:  secret,
+	}
+	challenge := entity.TwoFactorChallenge{
+		ID:        uuid.New(),
+		UserID:    user.ID,
+		Purpose:   TWO_FACTOR_CHALLENGE_VERIFY,
+		ExpiresAt: time.Now().Add(time.Minute),
+	}
+
+	f := &twoFactorFixture{
+		users:         &fakeUserRepository{user: user},
+		recoveryCodes: &fakeRecoveryCodeRepository{hashes: map[string]bool{}},
+		challenges:    &fakeChallengeRepository{challenge: challenge},
+		jwt:           &fakeJWTService{userId: user.ID.String(), challengeId: challenge.ID.String(), purpose: TWO_FACTOR_CHALLENGE_VERIFY},
+		loginGuard:    &fakeLoginGuard{},
+		audit:         &fakeAuditService{},
+	}
+	f.service = &twoFactorService{
+		userRepository:   f.users,
+		recoveryCodeRepo: f.recoveryCodes,
+		challengeRepo:    f.challenges,
+		jwtService:       f.jwt,
+		sessionService:   &fakeSessionService{},
+		roleService:      &fakeRoleService{},
+		loginGuard:       f.loginGuard,
+		auditService:     f.audit,
+		cfg:              &config.Config{AES: config.AESConfig{Key: testAESKey}},
+	}
+	return f
+}
+
+func totpCode(t *testing.T, secret string, at time.Time) string {
+	t.Helper()
+
+	code, err := totp.GenerateCodeCustom(secret, at, totp.ValidateOpts{
+		Period:    uint(TOTP_PERIOD / time.Second),
+		Digits:    otp.DigitsSix,
+		Algorithm: otp.AlgorithmSHA1,
+	})
+	if err != nil {
+		t.Fatalf("generate code: %v", err)
+	}
+	return code
+}
+
+func TestMatchTOTPStep(t *testing.T) {
+	now := time.Unix(1_700_000_000, 0)
+	step := now.Unix() / int64(TOTP_PERIOD/time.Second)
+
+	tests := []struct {
+		name     string
+		secret   string
+		code     string
+		wantStep int64
+		wantOK   bool
+	}{
+		{name: "current step", secret: testTOTPSecret, code: totpCode(t, testTOTPSecret, now), wantStep: step, wantOK: true},
+		{name: "previous step", secret: testTOTPSecret, code: totpCode(t, testTOTPSecret, now.Add(-TOTP_PERIOD)), wantStep: step - 1, wantOK: true},
+		{name: "next step", secret: testTOTPSecret, code: totpCode(t, testTOTPSecret, now.Add(TOTP_PERIOD)), wantStep: step + 1, wantOK: true},
+		{name: "surrounding whitespace", secret: testTOTPSecret, code: " " + totpCode(t, testTOTPSecret, now) + "\n", wantStep: step, wantOK: true},
+		{name: "two steps old", secret: testTOTPSecret, code: totpCode(t, testTOTPSecret, now.Add(-2*TOTP_PERIOD))},
+		{name: "two steps ahead", secret: testTOTPSecret, code: totpCode(t, testTOTPSecret, now.Add(2*TOTP_PERIOD))},
+		{name: "other secret", secret: testTOTPSecret, code: totpCode(t, "KRSXG5CTMVRXEZLU", now)},
+		{name: "empty code", secret: testTOTPSecret, code: ""},
+		{name: "invalid secret", secret: "not base32!", code: "123456"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotStep, gotOK := matchTOTPStep(tt.secret, tt.code, now)
+			if gotOK != tt.wantOK || gotStep != tt.wantStep {
+				t.Errorf("matchTOTPStep() = (%d, %v), want (%d, %v)", gotStep, gotOK, tt.wantStep, tt.wantOK)
+			}
+		})
+	}
+}
+
+func TestUseCode(t *testing.T) {
+	now := time.Now()
+	step := now.Unix() / int64(TOTP_PERIOD/time.Second)
+	code := totpCode(t, testTOTPSecret, now)
+
+	tests := []struct {
+		name      string
+		mutate    func(f *twoFactorFixture)
+		code      string
+		want      bool
+		wantSaved bool
+	}{
+		{name: "valid code", code: code, want: true, wantSaved: true},
+		{
+			name:   "replayed step",
+			mutate: func(f *twoFactorFixture) { f.users.user.TwoFactorLastStep = step },
+			code:   code,
+		},
+		{
+			name:   "later step already used",
+			mutate: func(f *twoFactorFixture) { f.users.user.TwoFactorLastStep = step + 1 },
+			code:   code,
+		},
+		{
+			name:      "step taken by a concurrent request",
+			mutate:    func(f *twoFactorFixture) { f.users.stepTaken = true },
+			code:      code,
+			wantSaved: true,
+		},
+		{name: "wrong code", code: totpCode(t, testTOTPSecret, now.Add(-5*TOTP_PERIOD))},
+		{
+			name:   "no secret",
+			mutate: func(f *twoFactorFixture) { f.users.user.TwoFactorSecret = "" },
+			code:   code,
+		},
+		{
+			name:   "undecryptable secret",
+			mutate: func(f *twoFactorFixture) { f.users.user.TwoFactorSecret = "garbage" },
+			code:   code,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := newTwoFactorFixture(t)
+			if tt.mutate != nil {
+				tt.mutate(f)
+			}
+
+			got, err := f.service.useCode(context.Background(), f.users.user, tt.code)
+			if err != nil {
+				t.Fatalf("useCode() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("useCode() = %v, want %v", got, tt.want)
+			}
+			if saved := len(f.users.usedSteps) > 0; saved != tt.wantSaved {
+				t.Errorf("time step saved = %v, want %v", saved, tt.wantSaved)
+			}
+		})
+	}
+}
+
+func TestVerifyRecoveryCode(t *testing.T) {
+	codes, hashes, err := generateRecoveryCodes(2)
+	if err != nil {
+		t.Fatalf("generate recovery codes: %v", err)
+	}
+
+	tests := []struct {
+		name    string
+		code    string
+		used    bool // the code was spent on an earlier login
+		wantErr error
+	}{
+		{name: "as shown", code: codes[0]},
+		{name: "upper case without dash", code: "  " + strings.ToUpper(strings.ReplaceAll(codes[1], "-", "")) + " "},
+		{name: "already used", code: codes[0], used: true, wantErr: dto.ErrTwoFactorCodeInvalid},
+		{name: "unknown code", code: "aaaa-bbbb", wantErr: dto.ErrTwoFactorCodeInvalid},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := newTwoFactorFixture(t)
+			for _, hash := range hashes {
+				f.recoveryCodes.hashes[hash] = true
+			}
+			if tt.used {
+				delete(f.recoveryCodes.hashes, hashes[0])
+			}
+
+			login, err := f.service.Verify(context.Background(), dto.TwoFactorVerifyRequest{
+				ChallengeToken: "challenge-token",
+				RecoveryCode:   tt.code,
+			}, "127.0.0.1")
+
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Verify() error = %v, want %v", err, tt.wantErr)
+			}
+
+			if tt.wantErr != nil {
+				if f.loginGuard.failures != 1 || f.challenges.failures != 1 {
+					t.Errorf("failures counted by guard/challenge = %d/%d, want 1/1", f.loginGuard.failures, f.challenges.failures)
+				}
+				if f.challenges.challenge.ConsumedAt != nil {
+					t.Error("challenge consumed after a single wrong code")
+				}
+				return
+			}
+
+			if login.Token != "access-token" {
+				t.Errorf("Verify() token = %q, want access-token", login.Token)
+			}
+			if len(f.recoveryCodes.hashes) != len(hashes)-1 {
+				t.Errorf("recovery codes left = %d, want %d", len(f.recoveryCodes.hashes), len(hashes)-1)
+			}
+			if f.challenges.challenge.ConsumedAt == nil {
+				t.Error("challenge not consumed after a successful login")
+			}
+			if f.loginGuard.successes != 1 {
+				t.Errorf("login guard successes = %d, want 1", f.loginGuard.successes)
+			}
+			if len(f.audit.actions) != 1 || f.audit.actions[0] != entity.AuditActionLogin {
+				t.Errorf("audit actions = %v, want [%s]", f.audit.actions, entity.AuditActionLogin)
+			}
+
+			// the same code and challenge can't be used twice
+			if _, err := f.service.Verify(context.Background(), dto.TwoFactorVerifyRequest{
+				ChallengeToken: "challenge-token",
+				RecoveryCode:   tt.code,
+			}, "127.0.0.1"); !errors.Is(err, dto.ErrChallengeTokenInvalid) {
+				t.Errorf("second Verify() error = %v, want %v", err, dto.ErrChallengeTokenInvalid)
+			}
+		})
+	}
+}
+
+func TestVerifyBurnsChallenge(t *testing.T) {
+	f := newTwoFactorFixture(t)
+	req := dto.TwoFactorVerifyRequest{ChallengeToken: "challenge-token", RecoveryCode: "aaaa-bbbb"}
+
+	for i := 0; i < TWO_FACTOR_MAX_CHALLENGE_FAILURES; i++ {
+		if _, err := f.service.Verify(context.Background(), req, "127.0.0.1"); !errors.Is(err, dto.ErrTwoFactorCodeInvalid) {
+			t.Fatalf("attempt %d: Verify() error = %v, want %v", i+1, err, dto.ErrTwoFactorCodeInvalid)
+		}
+	}
+
+	_, err := f.service.Verify(context.Background(), req, "127.0.0.1")
+	if !errors.Is(err, dto.ErrChallengeTokenInvalid) {
+		t.Errorf("Verify() after %d failures error = %v, want %v", TWO_FACTOR_MAX_CHALLENGE_FAILURES, err, dto.ErrChallengeTokenInvalid)
+	}
+}
+
+func TestVerifyClaimsChallengeFirst(t *testing.T) {
+	codes, hashes, err := generateRecoveryCodes(1)
+	if err != nil {
+		t.Fatalf("generate recovery codes: %v", err)
+	}
+
+	f := newTwoFactorFixture(t)
+	f.recoveryCodes.hashes[hashes[0]] = true
+	f.challenges.raced = true
+
+	_, err = f.service.Verify(context.Background(), dto.TwoFactorVerifyRequest{
+		ChallengeToken: "challenge-token",
+		RecoveryCode:   codes[0],
+	}, "127.0.0.1")
+	if !errors.Is(err, dto.ErrChallengeTokenInvalid) {
+		t.Fatalf("Verify() error = %v, want %v", err, dto.ErrChallengeTokenInvalid)
+	}
+
+	// the request that lost the challenge never gets to spend or guess a code
+	if !f.recoveryCodes.hashes[hashes[0]] {
+		t.Error("recovery code spent by a request that lost the challenge")
+	}
+	if f.loginGuard.failures != 0 || f.challenges.failures != 0 {
+		t.Errorf("failures counted by guard/challenge = %d/%d, want 0/0", f.loginGuard.failures, f.challenges.failures)
+	}
+}
+
+func TestEnrollConfirmWrongCode(t *testing.T) {
+	f := newTwoFactorFixture(t)
+	f.users.user.TwoFactorEnabled = false
+	f.challenges.challenge.Purpose = TWO_FACTOR_CHALLENGE_ENROLL
+	f.jwt.purpose = TWO_FACTOR_CHALLENGE_ENROLL
+
+	req := dto.TwoFactorEnrollConfirmRequest{
+		ChallengeToken: "challenge-token",
+		Code:           totpCode(t, testTOTPSecret, time.Now().Add(-5*TOTP_PERIOD)),
+	}
+
+	for i := 0; i < TWO_FACTOR_MAX_CHALLENGE_FAILURES; i++ {
+		if _, err := f.service.EnrollConfirm(context.Background(), req, "127.0.0.1"); !errors.Is(err, dto.ErrTwoFactorCodeInvalid) {
+			t.Fatalf("attempt %d: EnrollConfirm() error = %v, want %v", i+1, err, dto.ErrTwoFactorCodeInvalid)
+		}
+		if open := f.challenges.challenge.ConsumedAt == nil; open != (i+1 < TWO_FACTOR_MAX_CHALLENGE_FAILURES) {
+			t.Errorf("attempt %d: challenge open = %v", i+1, open)
+		}
+	}
+
+	if f.loginGuard.failures != TWO_FACTOR_MAX_CHALLENGE_FAILURES {
+		t.Errorf("login guard failures = %d, want %d", f.loginGuard.failures, TWO_FACTOR_MAX_CHALLENGE_FAILURES)
+	}
+	if _, err := f.service.EnrollConfirm(context.Background(), req, "127.0.0.1"); !errors.Is(err, dto.ErrChallengeTokenInvalid) {
+		t.Errorf("EnrollConfirm() on a burned challenge error = %v, want %v", err, dto.ErrChallengeTokenInvalid)
+	}
+}
+
+func TestUserFromChallenge(t *testing.T) {
+	tests := []struct {
+		name    string
+		purpose string
+		mutate  func(f *twoFactorFixture)
+		wantErr error
+	}{
+		{name: "open challenge", purpose: TWO_FACTOR_CHALLENGE_VERIFY},
+		{
+			name:    "token for another purpose",
+			purpose: TWO_FACTOR_CHALLENGE_ENROLL,
+			wantErr: dto.ErrChallengeTokenInvalid,
+		},
+		{
+			name:    "stored challenge for another purpose",
+			purpose: TWO_FACTOR_CHALLENGE_VERIFY,
+			mutate:  func(f *twoFactorFixture) { f.challenges.challenge.Purpose = TWO_FACTOR_CHALLENGE_ENROLL },
+			wantErr: dto.ErrChallengeTokenInvalid,
+		},
+		{
+			name:    "challenge of another user",
+			purpose: TWO_FACTOR_CHALLENGE_VERIFY,
+			mutate:  func(f *twoFactorFixture) { f.challenges.challenge.UserID = uuid.New() },
+			wantErr: dto.ErrChallengeTokenInvalid,
+		},
+		{
+			name:    "consumed challenge",
+			purpose: TWO_FACTOR_CHALLENGE_VERIFY,
+			mutate: func(f *twoFactorFixture) {
+				consumedAt := time.Now()
+				f.challenges.challenge.ConsumedAt = &consumedAt
+			},
+			wantErr: dto.ErrChallengeTokenInvalid,
+		},
+		{
+			name:    "expired challenge",
+			purpose: TWO_FACTOR_CHALLENGE_VERIFY,
+			mutate:  func(f *twoFactorFixture) { f.challenges.challenge.ExpiresAt = time.Now().Add(-time.Second) },
+			wantErr: dto.ErrChallengeTokenInvalid,
+		},
+		{
+			name:    "token without stored challenge",
+			purpose: TWO_FACTOR_CHALLENGE_VERIFY,
+			mutate:  func(f *twoFactorFixture) { f.jwt.challengeId = uuid.NewString() },
+			wantErr: dto.ErrChallengeTokenInvalid,
+		},
+		{
+			name:    "malformed challenge ID",
+			purpose: TWO_FACTOR_CHALLENGE_VERIFY,
+			mutate:  func(f *twoFactorFixture) { f.jwt.challengeId = "" },
+			wantErr: dto.ErrChallengeTokenInvalid,
+		},
+		{
+			name:    "suspended user",
+			purpose: TWO_FACTOR_CHALLENGE_VERIFY,
+			mutate:  func(f *twoFactorFixture) { f.users.user.IsSuspended = true },
+			wantErr: dto.ErrAccountSuspended,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := newTwoFactorFixture(t)
+			if tt.mutate != nil {
+				tt.mutate(f)
+			}
+
+			user, challenge, err := f.service.userFromChallenge(context.Background(), "challenge-token", tt.purpose)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("userFromChallenge() error = %v, want %v", err, tt.wantErr)
+			}
+			if tt.wantErr == nil && (user.ID != f.users.user.ID || challenge.ID != f.challenges.challenge.ID) {
+				t.Errorf("userFromChallenge() = (%s, %s), want (%s, %s)", user.ID, challenge.ID, f.users.user.ID, f.challenges.challenge.ID)
+			}
+		})
+	}
+}
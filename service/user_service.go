@@ -2,17 +2,19 @@ package service
 
 import (
 	"context"
-	"os"
 	"strings"
 	"sync"
 	"time"
 
+	"github.com/Shabrinashsf/go-gin-gorm-boilerplate/config"
 	"github.com/Shabrinashsf/go-gin-gorm-boilerplate/dto"
 	"github.com/Shabrinashsf/go-gin-gorm-boilerplate/entity"
 	"github.com/Shabrinashsf/go-gin-gorm-boilerplate/helpers"
 	"github.com/Shabrinashsf/go-gin-gorm-boilerplate/repository"
 	"github.com/Shabrinashsf/go-gin-gorm-boilerplate/utils"
+	"github.com/Shabrinashsf/go-gin-gorm-boilerplate/utils/i18n"
 	"github.com/Shabrinashsf/go-gin-gorm-boilerplate/utils/mailer"
+	"github.com/Shabrinashsf/go-gin-gorm-boilerplate/utils/metrics"
 	"github.com/google/uuid"
 	"gorm.io/gorm"
 )
@@ -20,39 +22,68 @@ import (
 type (
 	UserService interface {
 		RegisterUser(ctx context.Context, req dto.UserRegistrationRequest) (dto.UserResponse, error)
-		Login(ctx context.Context, req dto.UserLoginRequest) (dto.UserLoginResponse, error)
+		Login(ctx context.Context, req dto.UserLoginRequest, clientIP string) (dto.UserLoginResponse, error)
+		UnlockAccount(ctx context.Context, token string) error
+		RefreshToken(ctx context.Context, req dto.RefreshTokenRequest) (dto.UserLoginResponse, error)
+		Logout(ctx context.Context, userId uuid.UUID, sessionId uuid.UUID) error
 		SendVerificationEmail(ctx context.Context, req dto.SendVerificationEmailRequest) error
 		VerifyEmail(ctx context.Context, req dto.VerifyEmailRequest) (dto.VerifyEmailResponse, error)
 		ForgotPassword(ctx context.Context, req dto.ForgotPasswordRequest) error
 		ResetPassword(ctx context.Context, token string, newPassword string) error
 		GetUserByID(ctx context.Context, userId uuid.UUID) (dto.UserResponse, error)
 		UpdateUser(ctx context.Context, userId uuid.UUID, req dto.UserUpdateRequest) (dto.UserResponse, error)
+		ChangePassword(ctx context.Context, userId uuid.UUID, sessionId uuid.UUID, req dto.ChangePasswordRequest) error
+		ChangeEmail(ctx context.Context, userId uuid.UUID, req dto.ChangeEmailRequest) (dto.ChangeEmailResponse, error)
+		ConfirmEmailChange(ctx context.Context, token string) (dto.UserResponse, error)
 	}
 
 	userService struct {
-		userRepository repository.UserRepository
-		jwtService     JWTService
-		mailer         mailer.Mailer
-		db             *gorm.DB
+		userRepository    repository.UserRepository
+		passwordResetRepo repository.PasswordResetTokenRepository
+		challengeRepo     repository.TwoFactorChallengeRepository
+		jwtService        JWTService
+		sessionService    SessionService
+		roleService       RoleService
+		loginGuard        LoginGuardService
+		auditService      AuditService
+		emailOutbox       EmailOutboxService
+		mailer            mailer.Mailer
+		cfg               *config.Config
+		db                *gorm.DB
 	}
 )
 
-func NewUserService(ur repository.UserRepository, jwt JWTService, mailer mailer.Mailer, db *gorm.DB) UserService {
+func NewUserService(ur repository.UserRepository, prr repository.PasswordResetTokenRepository, tfcr repository.TwoFactorChallengeRepository, jwt JWTService, ss SessionService, rs RoleService, lg LoginGuardService, as AuditService, eos EmailOutboxService, mailer mailer.Mailer, cfg *config.Config, db *gorm.DB) UserService {
 	return &userService{
-		userRepository: ur,
-		jwtService:     jwt,
-		mailer:         mailer,
-		db:             db,
+		userRepository:    ur,
+		passwordResetRepo: prr,
+		challengeRepo:     tfcr,
+		jwtService:        jwt,
+		sessionService:    ss,
+		roleService:       rs,
+		loginGuard:        lg,
+		auditService:      as,
+		emailOutbox:       eos,
+		mailer:            mailer,
+		cfg:               cfg,
+		db:                db,
 	}
 }
 
 var (
 	mu sync.Mutex
 
-	VERIFY_EMAIL_TEMPLATE = "utils/mailer/template/verification_email.html"
+	VERIFY_EMAIL_TEMPLATE = "verification_email"
 	VERIFY_EMAIL_PATH     = "verify-email"
-	FORGET_EMAIL_TEMPLATE = "utils/mailer/template/forgot_password_email.html"
+	FORGET_EMAIL_TEMPLATE = "forgot_password_email"
 	FORGET_EMAIL_PATH     = "reset-password"
+
+	RESET_PASSWORD_TOKEN_TTL = time.Hour * 1
+
+	CHANGE_EMAIL_TEMPLATE  = "change_email_email"
+	CHANGE_EMAIL_PATH      = "confirm-email"
+	EMAIL_CHANGED_TEMPLATE = "email_changed_email"
+	CHANGE_EMAIL_TOKEN_TTL = time.Hour * 24
 )
 
 func (s *userService) RegisterUser(ctx context.Context, req dto.UserRegistrationRequest) (dto.UserResponse, error) {
@@ -81,6 +112,7 @@ func (s *userService) RegisterUser(ctx context.Context, req dto.UserRegistration
 		NoTelp:     req.NoTelp,
 		Role:       entity.RoleUser,
 		IsVerified: false,
+		Locale:     i18n.FromContext(ctx),
 	}
 
 	newUser, err := s.userRepository.RegisterUser(ctx, tx, user)
@@ -89,31 +121,23 @@ func (s *userService) RegisterUser(ctx context.Context, req dto.UserRegistration
 		return dto.UserResponse{}, err
 	}
 
-	if err := tx.Commit().Error; err != nil {
+	if err := s.enqueueVerificationEmail(ctx, tx, newUser); err != nil {
+		tx.Rollback()
 		return dto.UserResponse{}, err
 	}
 
-	expired := time.Now().Add(time.Hour * 24).Format("2006-01-02 15:04:05")
-	plainText := user.Email + "_" + expired
-	token, err := utils.AESEncrypt(plainText)
-	if err != nil {
+	if err := tx.Commit().Error; err != nil {
 		return dto.UserResponse{}, err
 	}
 
-	verifyLink := os.Getenv("APP_URL") + "/" + VERIFY_EMAIL_PATH + "?token=" + token
-	data := map[string]any{
-		"Email":  user.Email,
-		"Verify": verifyLink,
-	}
-
-	mail := s.mailer.MakeMail(VERIFY_EMAIL_TEMPLATE, data)
-	if mail.Error != nil {
-		return dto.UserResponse{}, dto.ErrMakeMail
-	}
-
-	if err := mail.SendEmail(user.Email, "Backend Boilerplate - Verification Email").Error; err != nil {
-		return dto.UserResponse{}, dto.ErrSendMail
-	}
+	metrics.UserRegistered()
+	s.auditService.Record(ctx, entity.AuditLog{
+		ActorID:    auditActor(newUser.ID),
+		Action:     entity.AuditActionRegister,
+		TargetType: "user",
+		TargetID:   newUser.ID.String(),
+		Success:    true,
+	})
 
 	return dto.UserResponse{
 		ID:         newUser.ID.String(),
@@ -123,32 +147,218 @@ func (s *userService) RegisterUser(ctx context.Context, req dto.UserRegistration
 		NoTelp:     newUser.NoTelp,
 		Role:       string(newUser.Role),
 		IsVerified: newUser.IsVerified,
+		Locale:     newUser.Locale,
 	}, nil
 }
 
-func (s *userService) Login(ctx context.Context, req dto.UserLoginRequest) (dto.UserLoginResponse, error) {
+func (s *userService) Login(ctx context.Context, req dto.UserLoginRequest, clientIP string) (dto.UserLoginResponse, error) {
+	if err := s.loginGuard.Check(ctx, req.Email, clientIP); err != nil {
+		metrics.Login(false)
+		return dto.UserLoginResponse{}, err
+	}
+
 	user, flag, err := s.userRepository.GetUserByEmail(ctx, nil, req.Email)
 	if err != nil || !flag {
+		s.loginGuard.RegisterFailure(ctx, req.Email, clientIP)
+		s.recordLoginFailure(ctx, uuid.Nil, req.Email, clientIP)
 		return dto.UserLoginResponse{}, dto.ErrInvalidCredentials
 	}
 
 	if !user.IsVerified {
+		s.loginGuard.RegisterFailure(ctx, req.Email, clientIP)
+		s.recordLoginFailure(ctx, user.ID, req.Email, clientIP)
 		return dto.UserLoginResponse{}, dto.ErrInvalidCredentials
 	}
 
 	checkPassword, err := helpers.CheckPassword(user.Password, []byte(req.Password))
 	if err != nil || !checkPassword {
+		s.loginGuard.RegisterFailure(ctx, req.Email, clientIP)
+		s.recordLoginFailure(ctx, user.ID, req.Email, clientIP)
 		return dto.UserLoginResponse{}, dto.ErrInvalidCredentials
 	}
 
-	token := s.jwtService.GenerateToken(user.ID.String(), string(user.Role))
+	if user.IsSuspended {
+		s.recordLoginFailure(ctx, user.ID, req.Email, clientIP)
+		return dto.UserLoginResponse{}, dto.ErrAccountSuspended
+	}
+
+	if user.TwoFactorEnabled {
+		challengeToken, err := issueChallengeToken(ctx, s.challengeRepo, s.jwtService, user, TWO_FACTOR_CHALLENGE_VERIFY)
+		if err != nil {
+			return dto.UserLoginResponse{}, err
+		}
+		s.recordTwoFactorChallenge(ctx, user, clientIP)
+
+		return dto.UserLoginResponse{
+			Role:                    string(user.Role),
+			TwoFactorRequired:       true,
+			TwoFactorChallengeToken: challengeToken,
+		}, nil
+	}
+
+	if isTwoFactorEnforced(s.cfg, user) {
+		challengeToken, err := issueChallengeToken(ctx, s.challengeRepo, s.jwtService, user, TWO_FACTOR_CHALLENGE_ENROLL)
+		if err != nil {
+			return dto.UserLoginResponse{}, err
+		}
+		s.recordTwoFactorChallenge(ctx, user, clientIP)
+
+		return dto.UserLoginResponse{
+			Role:                    string(user.Role),
+			TwoFactorSetupRequired:  true,
+			TwoFactorChallengeToken: challengeToken,
+		}, nil
+	}
+
+	login, err := issueLoginTokens(ctx, s.jwtService, s.sessionService, s.roleService, user)
+	if err != nil {
+		return dto.UserLoginResponse{}, err
+	}
+
+	// Only a completed login clears the lockout counter, a correct password
+	// followed by a failed second factor must not
+	s.loginGuard.RegisterSuccess(ctx, user.Email, clientIP)
+	recordLogin(ctx, s.auditService, user, clientIP)
+	return login, nil
+}
+
+// recordTwoFactorChallenge logs a correct password that still waits for the
+// second factor, it is not a login yet.
+func (s *userService) recordTwoFactorChallenge(ctx context.Context, user entity.User, clientIP string) {
+	s.auditService.Record(ctx, entity.AuditLog{
+		ActorID:    auditActor(user.ID),
+		Action:     entity.AuditActionTwoFactorChallenge,
+		TargetType: "user",
+		TargetID:   user.ID.String(),
+		Success:    true,
+		IPAddress:  clientIP,
+	})
+}
+
+// recordLoginFailure keeps the attempted email so failures against unknown
+// accounts can still be traced.
+func (s *userService) recordLoginFailure(ctx context.Context, userId uuid.UUID, email string, clientIP string) {
+	metrics.Login(false)
+
+	auditLog := entity.AuditLog{
+		ActorID:    auditActor(userId),
+		Action:     entity.AuditActionLoginFailed,
+		TargetType: "user",
+		TargetID:   email,
+		Success:    false,
+		IPAddress:  clientIP,
+	}
+	if userId != uuid.Nil {
+		auditLog.TargetID = userId.String()
+	}
+
+	s.auditService.Record(ctx, auditLog)
+}
+
+// recordLogin counts a login that ended with tokens being issued, with or
+// without a second factor.
+func recordLogin(ctx context.Context, auditService AuditService, user entity.User, clientIP string) {
+	metrics.Login(true)
+	auditService.Record(ctx, entity.AuditLog{
+		ActorID:    auditActor(user.ID),
+		Action:     entity.AuditActionLogin,
+		TargetType: "user",
+		TargetID:   user.ID.String(),
+		Success:    true,
+		IPAddress:  clientIP,
+	})
+}
+
+// issueLoginTokens starts a new session for a fully authenticated user.
+func issueLoginTokens(ctx context.Context, jwtService JWTService, sessionService SessionService, roleService RoleService, user entity.User) (dto.UserLoginResponse, error) {
+	permissions, err := roleService.GetUserPermissions(ctx, user.ID)
+	if err != nil {
+		return dto.UserLoginResponse{}, err
+	}
+
+	session, err := sessionService.CreateSession(ctx, user.ID)
+	if err != nil {
+		return dto.UserLoginResponse{}, err
+	}
+
+	token := jwtService.GenerateToken(user.ID.String(), string(user.Role), session.SessionID.String(), permissions)
 
 	return dto.UserLoginResponse{
-		Token: token,
-		Role:  string(user.Role),
+		Token:            token,
+		RefreshToken:     session.RefreshToken,
+		RefreshExpiresAt: &session.ExpiresAt,
+		Role:             string(user.Role),
 	}, nil
 }
 
+func (s *userService) UnlockAccount(ctx context.Context, token string) error {
+	userId, err := s.loginGuard.Unlock(ctx, token)
+
+	auditLog := entity.AuditLog{
+		Action:     entity.AuditActionAccountUnlock,
+		TargetType: "user",
+		Success:    err == nil,
+	}
+	if userId != uuid.Nil {
+		auditLog.TargetID = userId.String()
+	}
+
+	s.auditService.Record(ctx, auditLog)
+
+	return err
+}
+
+func (s *userService) RefreshToken(ctx context.Context, req dto.RefreshTokenRequest) (dto.UserLoginResponse, error) {
+	session, err := s.sessionService.RotateSession(ctx, req.RefreshToken)
+	if err != nil {
+		return dto.UserLoginResponse{}, err
+	}
+
+	user, err := s.userRepository.GetUserByID(ctx, nil, session.UserID)
+	if err != nil {
+		return dto.UserLoginResponse{}, dto.ErrUserNotFound
+	}
+
+	// Permissions are re-read on every refresh so role changes reach the token
+	permissions, err := s.roleService.GetUserPermissions(ctx, user.ID)
+	if err != nil {
+		return dto.UserLoginResponse{}, err
+	}
+
+	token := s.jwtService.GenerateToken(user.ID.String(), string(user.Role), session.SessionID.String(), permissions)
+
+	s.auditService.Record(ctx, entity.AuditLog{
+		ActorID:    auditActor(user.ID),
+		Action:     entity.AuditActionTokenRefresh,
+		TargetType: "session",
+		TargetID:   session.SessionID.String(),
+		Success:    true,
+	})
+
+	return dto.UserLoginResponse{
+		Token:            token,
+		RefreshToken:     session.RefreshToken,
+		RefreshExpiresAt: &session.ExpiresAt,
+		Role:             string(user.Role),
+	}, nil
+}
+
+func (s *userService) Logout(ctx context.Context, userId uuid.UUID, sessionId uuid.UUID) error {
+	if err := s.sessionService.RevokeSession(ctx, sessionId); err != nil {
+		return err
+	}
+
+	s.auditService.Record(ctx, entity.AuditLog{
+		ActorID:    auditActor(userId),
+		Action:     entity.AuditActionLogout,
+		TargetType: "session",
+		TargetID:   sessionId.String(),
+		Success:    true,
+	})
+
+	return nil
+}
+
 func (s *userService) SendVerificationEmail(ctx context.Context, req dto.SendVerificationEmailRequest) error {
 	user, _, err := s.userRepository.GetUserByEmail(ctx, nil, req.Email)
 	if err != nil {
@@ -159,33 +369,39 @@ func (s *userService) SendVerificationEmail(ctx context.Context, req dto.SendVer
 		return dto.ErrAccountAlreadyVerified
 	}
 
+	return s.enqueueVerificationEmail(ctx, nil, user)
+}
+
+// enqueueVerificationEmail queues the link that verifies user, valid for 24 hours.
+func (s *userService) enqueueVerificationEmail(ctx context.Context, tx *gorm.DB, user entity.User) error {
 	expired := time.Now().Add(time.Hour * 24).Format("2006-01-02 15:04:05")
 	plainText := user.Email + "_" + expired
-	token, err := utils.AESEncrypt(plainText)
+	token, err := utils.AESEncrypt(s.cfg.AES.Key, plainText)
 	if err != nil {
 		return err
 	}
 
-	verifyLink := os.Getenv("APP_URL") + "/" + VERIFY_EMAIL_PATH + "?token=" + token
+	verifyLink := s.cfg.App.URL + "/" + VERIFY_EMAIL_PATH + "?token=" + token
 	data := map[string]any{
 		"Email":  user.Email,
 		"Verify": verifyLink,
 	}
 
-	mail := s.mailer.MakeMail(VERIFY_EMAIL_TEMPLATE, data)
+	mail := s.mailer.MakeMail(VERIFY_EMAIL_TEMPLATE, emailLocale(ctx, user), data)
 	if mail.Error != nil {
-		return dto.ErrMakeMail
+		return dto.ErrMakeMail.Wrap(mail.Error)
 	}
 
-	if err := mail.SendEmail(user.Email, "Backend Boilerplate - Verification Email").Error; err != nil {
-		return dto.ErrSendMail
+	msg, err := mail.NewMessage().To(user.Email).Build()
+	if err != nil {
+		return dto.ErrMakeMail.Wrap(err)
 	}
 
-	return nil
+	return s.emailOutbox.Enqueue(ctx, tx, msg)
 }
 
 func (s *userService) VerifyEmail(ctx context.Context, req dto.VerifyEmailRequest) (dto.VerifyEmailResponse, error) {
-	decryptedToken, err := utils.AESDecrypt(req.Token)
+	decryptedToken, err := utils.AESDecrypt(s.cfg.AES.Key, req.Token)
 	if err != nil {
 		return dto.VerifyEmailResponse{}, dto.ErrTokenInvalid
 	}
@@ -234,13 +450,22 @@ func (s *userService) VerifyEmail(ctx context.Context, req dto.VerifyEmailReques
 	updatedUser, err := s.userRepository.UpdateUser(ctx, tx, user.ID, updates)
 	if err != nil {
 		tx.Rollback()
-		return dto.VerifyEmailResponse{}, dto.ErrUpdateUser
+		return dto.VerifyEmailResponse{}, dto.ErrUpdateUser.Wrap(err)
 	}
 
 	if err := tx.Commit().Error; err != nil {
 		return dto.VerifyEmailResponse{}, err
 	}
 
+	s.auditService.Record(ctx, entity.AuditLog{
+		ActorID:    auditActor(user.ID),
+		Action:     entity.AuditActionVerifyEmail,
+		TargetType: "user",
+		TargetID:   user.ID.String(),
+		Success:    true,
+		Changes:    auditChanges(map[string]any{"is_verified": false}, map[string]any{"is_verified": true}),
+	})
+
 	return dto.VerifyEmailResponse{
 		Email:      email,
 		IsVerified: updatedUser.IsVerified,
@@ -253,81 +478,150 @@ func (s *userService) ForgotPassword(ctx context.Context, req dto.ForgotPassword
 		return dto.ErrEmailNotFound
 	}
 
-	expired := time.Now().Add(time.Hour * 24).Format("2006-01-02 15:04:05")
-	plainText := user.Email + "_" + expired
-	token, err := utils.AESEncrypt(plainText)
+	token, err := helpers.GenerateRandomToken(32)
 	if err != nil {
 		return err
 	}
 
-	verifyLink := os.Getenv("APP_URL") + "/" + FORGET_EMAIL_PATH + "?token=" + token
+	tx := s.db.WithContext(ctx).Begin()
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+			panic(r)
+		}
+	}()
+
+	// Only the most recent link may be used
+	if err := s.passwordResetRepo.ConsumeUserPasswordResetTokens(ctx, tx, user.ID); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	if _, err := s.passwordResetRepo.CreatePasswordResetToken(ctx, tx, entity.PasswordResetToken{
+		UserID:    user.ID,
+		TokenHash: helpers.HashToken(token),
+		ExpiresAt: time.Now().Add(RESET_PASSWORD_TOKEN_TTL),
+	}); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	verifyLink := s.cfg.App.URL + "/" + FORGET_EMAIL_PATH + "?token=" + token
 	data := map[string]any{
 		"Email":  user.Email,
 		"Verify": verifyLink,
 	}
 
-	mail := s.mailer.MakeMail(FORGET_EMAIL_TEMPLATE, data)
+	mail := s.mailer.MakeMail(FORGET_EMAIL_TEMPLATE, emailLocale(ctx, user), data)
 	if mail.Error != nil {
-		return dto.ErrMakeMail
+		tx.Rollback()
+		return dto.ErrMakeMail.Wrap(mail.Error)
 	}
 
-	if err := mail.SendEmail(user.Email, "Backend Boilerplate - Reset Password").Error; err != nil {
-		return dto.ErrSendMail
+	msg, err := mail.NewMessage().To(user.Email).Build()
+	if err != nil {
+		tx.Rollback()
+		return dto.ErrMakeMail.Wrap(err)
 	}
 
+	if err := s.emailOutbox.Enqueue(ctx, tx, msg); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		return err
+	}
+
+	// Requested anonymously, so the user is only the target
+	s.auditService.Record(ctx, entity.AuditLog{
+		Action:     entity.AuditActionForgotPassword,
+		TargetType: "user",
+		TargetID:   user.ID.String(),
+		Success:    true,
+	})
+
 	return nil
 }
 
 func (s *userService) ResetPassword(ctx context.Context, token string, newPassword string) error {
-	decryptedToken, err := utils.AESDecrypt(token)
-	if err != nil {
+	if token == "" {
 		return dto.ErrTokenInvalid
 	}
 
-	tokenParts := strings.Split(decryptedToken, "_")
-	if len(tokenParts) < 2 {
+	tx := s.db.WithContext(ctx).Begin()
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+			panic(r)
+		}
+	}()
+
+	resetToken, err := s.passwordResetRepo.GetPasswordResetTokenByHash(ctx, tx, helpers.HashToken(token))
+	if err != nil {
+		tx.Rollback()
 		return dto.ErrTokenInvalid
 	}
 
-	email := tokenParts[0]
-	expirationDate := tokenParts[1]
-	expirationTime, err := time.Parse("2006-01-02 15:04:05", expirationDate)
-
-	if err != nil {
+	if resetToken.ConsumedAt != nil {
+		tx.Rollback()
 		return dto.ErrTokenInvalid
 	}
 
-	if time.Now().After(expirationTime) {
+	if time.Now().After(resetToken.ExpiresAt) {
+		tx.Rollback()
 		return dto.ErrTokenExpired
 	}
 
-	tx := s.db.WithContext(ctx).Begin()
-	defer func() {
-		if r := recover(); r != nil {
-			tx.Rollback()
-			panic(r)
-		}
-	}()
+	consumed, err := s.passwordResetRepo.ConsumePasswordResetToken(ctx, tx, resetToken.ID)
+	if err != nil || !consumed {
+		tx.Rollback()
+		return dto.ErrTokenInvalid
+	}
 
 	hashedPassword, err := helpers.HashPassword(newPassword)
 	if err != nil {
 		tx.Rollback()
-		return dto.ErrHashPasswordFailed
+		return dto.ErrHashPasswordFailed.Wrap(err)
 	}
 
-	err = s.userRepository.ResetPassword(ctx, email, hashedPassword)
-	if err != nil {
+	updates := map[string]interface{}{}
+	updates["password"] = hashedPassword
+
+	if _, err := s.userRepository.UpdateUser(ctx, tx, resetToken.UserID, updates); err != nil {
 		tx.Rollback()
-		return dto.ErrUpdateUser
+		return dto.ErrUpdateUser.Wrap(err)
 	}
 
 	if err := tx.Commit().Error; err != nil {
 		return err
 	}
 
+	s.auditService.Record(ctx, entity.AuditLog{
+		ActorID:    auditActor(resetToken.UserID),
+		Action:     entity.AuditActionResetPassword,
+		TargetType: "user",
+		TargetID:   resetToken.UserID.String(),
+		Success:    true,
+	})
+
+	// Whoever requested the reset may not be the one holding the old sessions
+	if err := s.sessionService.RevokeUserSessions(ctx, resetToken.UserID, nil); err != nil {
+		return err
+	}
+
 	return nil
 }
 
+// emailLocale is the language the user chose, or the one of the current
+// request for accounts that never picked one.
+func emailLocale(ctx context.Context, user entity.User) string {
+	if i18n.Supported(user.Locale) {
+		return user.Locale
+	}
+	return i18n.FromContext(ctx)
+}
+
 func (s *userService) GetUserByID(ctx context.Context, userId uuid.UUID) (dto.UserResponse, error) {
 	user, err := s.userRepository.GetUserByID(ctx, nil, userId)
 	if err != nil {
@@ -342,6 +636,7 @@ func (s *userService) GetUserByID(ctx context.Context, userId uuid.UUID) (dto.Us
 		NoTelp:     user.NoTelp,
 		Role:       string(user.Role),
 		IsVerified: user.IsVerified,
+		Locale:     user.Locale,
 	}, nil
 }
 
@@ -371,6 +666,9 @@ func (s *userService) UpdateUser(ctx context.Context, userId uuid.UUID, req dto.
 	if req.NoTelp != "" && req.NoTelp != user.NoTelp {
 		updates["no_telp"] = req.NoTelp
 	}
+	if req.Locale != "" && req.Locale != user.Locale {
+		updates["locale"] = req.Locale
+	}
 
 	if len(updates) == 0 {
 		tx.Rollback()
@@ -380,13 +678,28 @@ func (s *userService) UpdateUser(ctx context.Context, userId uuid.UUID, req dto.
 	userUpdate, err := s.userRepository.UpdateUser(ctx, tx, userId, updates)
 	if err != nil {
 		tx.Rollback()
-		return dto.UserResponse{}, dto.ErrUpdateUser
+		return dto.UserResponse{}, dto.ErrUpdateUser.Wrap(err)
 	}
 
 	if err := tx.Commit().Error; err != nil {
 		return dto.UserResponse{}, err
 	}
 
+	before := map[string]any{
+		"name":     user.Name,
+		"instansi": user.Instansi,
+		"no_telp":  user.NoTelp,
+		"locale":   user.Locale,
+	}
+	s.auditService.Record(ctx, entity.AuditLog{
+		ActorID:    auditActor(userId),
+		Action:     entity.AuditActionUpdateProfile,
+		TargetType: "user",
+		TargetID:   userId.String(),
+		Success:    true,
+		Changes:    auditChanges(before, updates),
+	})
+
 	return dto.UserResponse{
 		ID:         userUpdate.ID.String(),
 		Name:       userUpdate.Name,
@@ -395,5 +708,222 @@ func (s *userService) UpdateUser(ctx context.Context, userId uuid.UUID, req dto.
 		NoTelp:     userUpdate.NoTelp,
 		Role:       string(user.Role),
 		IsVerified: user.IsVerified,
+		Locale:     userUpdate.Locale,
+	}, nil
+}
+
+func (s *userService) ChangePassword(ctx context.Context, userId uuid.UUID, sessionId uuid.UUID, req dto.ChangePasswordRequest) error {
+	user, err := s.userRepository.GetUserByID(ctx, nil, userId)
+	if err != nil {
+		return dto.ErrUserNotFound
+	}
+
+	checkPassword, err := helpers.CheckPassword(user.Password, []byte(req.CurrentPassword))
+	if err != nil || !checkPassword {
+		s.auditService.Record(ctx, entity.AuditLog{
+			ActorID:    auditActor(userId),
+			Action:     entity.AuditActionChangePassword,
+			TargetType: "user",
+			TargetID:   userId.String(),
+			Success:    false,
+		})
+		return dto.ErrWrongPassword
+	}
+
+	if req.CurrentPassword == req.NewPassword {
+		return dto.ErrSamePassword
+	}
+
+	hashedPassword, err := helpers.HashPassword(req.NewPassword)
+	if err != nil {
+		return dto.ErrHashPasswordFailed.Wrap(err)
+	}
+
+	updates := map[string]interface{}{}
+	updates["password"] = hashedPassword
+
+	if _, err := s.userRepository.UpdateUser(ctx, nil, userId, updates); err != nil {
+		return dto.ErrUpdateUser.Wrap(err)
+	}
+
+	s.auditService.Record(ctx, entity.AuditLog{
+		ActorID:    auditActor(userId),
+		Action:     entity.AuditActionChangePassword,
+		TargetType: "user",
+		TargetID:   userId.String(),
+		Success:    true,
+	})
+
+	// Keep the session that made the change, sign out everywhere else
+	return s.sessionService.RevokeUserSessions(ctx, userId, &sessionId)
+}
+
+func (s *userService) ChangeEmail(ctx context.Context, userId uuid.UUID, req dto.ChangeEmailRequest) (dto.ChangeEmailResponse, error) {
+	user, err := s.userRepository.GetUserByID(ctx, nil, userId)
+	if err != nil {
+		return dto.ChangeEmailResponse{}, dto.ErrUserNotFound
+	}
+
+	checkPassword, err := helpers.CheckPassword(user.Password, []byte(req.Password))
+	if err != nil || !checkPassword {
+		return dto.ChangeEmailResponse{}, dto.ErrWrongPassword
+	}
+
+	if strings.EqualFold(req.NewEmail, user.Email) {
+		return dto.ChangeEmailResponse{}, dto.ErrSameEmail
+	}
+
+	if _, flag, _ := s.userRepository.GetUserByEmail(ctx, nil, req.NewEmail); flag {
+		return dto.ChangeEmailResponse{}, dto.ErrorEmailAlreadyExists
+	}
+
+	token, err := helpers.GenerateRandomToken(32)
+	if err != nil {
+		return dto.ChangeEmailResponse{}, err
+	}
+
+	confirmLink := s.cfg.App.URL + "/" + CHANGE_EMAIL_PATH + "?token=" + token
+	data := map[string]any{
+		"Email":  req.NewEmail,
+		"Verify": confirmLink,
+	}
+
+	mail := s.mailer.MakeMail(CHANGE_EMAIL_TEMPLATE, emailLocale(ctx, user), data)
+	if mail.Error != nil {
+		return dto.ChangeEmailResponse{}, dto.ErrMakeMail.Wrap(mail.Error)
+	}
+
+	tx := s.db.WithContext(ctx).Begin()
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+			panic(r)
+		}
+	}()
+
+	expiresAt := time.Now().Add(CHANGE_EMAIL_TOKEN_TTL)
+	updates := map[string]interface{}{}
+	updates["pending_email"] = req.NewEmail
+	updates["pending_email_token_hash"] = helpers.HashToken(token)
+	updates["pending_email_expires_at"] = expiresAt
+
+	if _, err := s.userRepository.UpdateUser(ctx, tx, userId, updates); err != nil {
+		tx.Rollback()
+		return dto.ChangeEmailResponse{}, dto.ErrUpdateUser.Wrap(err)
+	}
+
+	msg, err := mail.NewMessage().To(req.NewEmail).Build()
+	if err != nil {
+		tx.Rollback()
+		return dto.ChangeEmailResponse{}, dto.ErrMakeMail.Wrap(err)
+	}
+
+	if err := s.emailOutbox.Enqueue(ctx, tx, msg); err != nil {
+		tx.Rollback()
+		return dto.ChangeEmailResponse{}, err
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		return dto.ChangeEmailResponse{}, err
+	}
+
+	s.auditService.Record(ctx, entity.AuditLog{
+		ActorID:    auditActor(userId),
+		Action:     entity.AuditActionChangeEmail,
+		TargetType: "user",
+		TargetID:   userId.String(),
+		Success:    true,
+		Changes:    auditChanges(map[string]any{"pending_email": user.PendingEmail}, map[string]any{"pending_email": req.NewEmail}),
+	})
+
+	return dto.ChangeEmailResponse{
+		PendingEmail: req.NewEmail,
+	}, nil
+}
+
+func (s *userService) ConfirmEmailChange(ctx context.Context, token string) (dto.UserResponse, error) {
+	tx := s.db.WithContext(ctx).Begin()
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+			panic(r)
+		}
+	}()
+
+	user, err := s.userRepository.GetUserByPendingEmailToken(ctx, tx, helpers.HashToken(token))
+	if err != nil || user.PendingEmail == "" {
+		tx.Rollback()
+		return dto.UserResponse{}, dto.ErrTokenInvalid
+	}
+
+	if user.PendingEmailExpiresAt == nil || time.Now().After(*user.PendingEmailExpiresAt) {
+		tx.Rollback()
+		return dto.UserResponse{}, dto.ErrTokenExpired
+	}
+
+	// The address may have been registered by someone else in the meantime
+	if _, flag, _ := s.userRepository.GetUserByEmail(ctx, tx, user.PendingEmail); flag {
+		tx.Rollback()
+		return dto.UserResponse{}, dto.ErrorEmailAlreadyExists
+	}
+
+	oldEmail := user.Email
+	updates := map[string]interface{}{}
+	updates["email"] = user.PendingEmail
+	updates["pending_email"] = ""
+	updates["pending_email_token_hash"] = ""
+	updates["pending_email_expires_at"] = nil
+
+	updatedUser, err := s.userRepository.UpdateUser(ctx, tx, user.ID, updates)
+	if err != nil {
+		tx.Rollback()
+		return dto.UserResponse{}, dto.ErrUpdateUser.Wrap(err)
+	}
+
+	// The old address is told about the change, in case it was not its owner
+	data := map[string]any{
+		"Email":    oldEmail,
+		"NewEmail": updatedUser.Email,
+	}
+
+	mail := s.mailer.MakeMail(EMAIL_CHANGED_TEMPLATE, emailLocale(ctx, user), data)
+	if mail.Error != nil {
+		tx.Rollback()
+		return dto.UserResponse{}, dto.ErrMakeMail.Wrap(mail.Error)
+	}
+
+	msg, err := mail.NewMessage().To(oldEmail).Build()
+	if err != nil {
+		tx.Rollback()
+		return dto.UserResponse{}, dto.ErrMakeMail.Wrap(err)
+	}
+
+	if err := s.emailOutbox.Enqueue(ctx, tx, msg); err != nil {
+		tx.Rollback()
+		return dto.UserResponse{}, err
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		return dto.UserResponse{}, err
+	}
+
+	s.auditService.Record(ctx, entity.AuditLog{
+		ActorID:    auditActor(user.ID),
+		Action:     entity.AuditActionConfirmEmailChange,
+		TargetType: "user",
+		TargetID:   user.ID.String(),
+		Success:    true,
+		Changes:    auditChanges(map[string]any{"email": oldEmail}, map[string]any{"email": updatedUser.Email}),
+	})
+
+	return dto.UserResponse{
+		ID:         updatedUser.ID.String(),
+		Name:       updatedUser.Name,
+		Email:      updatedUser.Email,
+		Instansi:   updatedUser.Instansi,
+		NoTelp:     updatedUser.NoTelp,
+		Role:       string(updatedUser.Role),
+		IsVerified: updatedUser.IsVerified,
+		Locale:     updatedUser.Locale,
 	}, nil
 }
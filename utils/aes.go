@@ -8,15 +8,14 @@ import (
 	"errors"
 	"fmt"
 	"io"
-	"os"
 )
 
-func AESEncrypt(stringToEncrypt string) (encryptedString string, err error) {
+// AESEncrypt encrypts with AES-GCM using the hex encoded KEY (config AES.Key).
+func AESEncrypt(KEY string, stringToEncrypt string) (encryptedString string, err error) {
 	//Since the key is in string, we need to convert decode it to bytes
 	// if err := Init(); err != nil {
 	// 	return "", err
 	// }
-	KEY := os.Getenv("AES_KEY")
 	key, _ := hex.DecodeString(KEY)
 	plaintext := []byte(stringToEncrypt)
 
@@ -45,7 +44,7 @@ func AESEncrypt(stringToEncrypt string) (encryptedString string, err error) {
 	return fmt.Sprintf("%x", ciphertext), nil
 }
 
-func AESDecrypt(encryptedString string) (decryptedString string, err error) {
+func AESDecrypt(KEY string, encryptedString string) (decryptedString string, err error) {
 	// if err := Init(); err != nil {
 	// 	return "", err
 	// }
@@ -56,7 +55,6 @@ func AESDecrypt(encryptedString string) (decryptedString string, err error) {
 		}
 	}()
 
-	KEY := os.Getenv("AES_KEY")
 	key, _ := hex.DecodeString(KEY)
 	enc, _ := hex.DecodeString(encryptedString)
 
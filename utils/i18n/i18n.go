@@ -0,0 +1,96 @@
+package i18n
+
+import (
+	"context"
+	"embed"
+	"encoding/json"
+	"strconv"
+	"strings"
+)
+
+const (
+	LOCALE_ID      = "id"
+	LOCALE_EN      = "en"
+	DEFAULT_LOCALE = LOCALE_ID
+)
+
+// LOCALES lists the supported locales, each has a file in locales/.
+var LOCALES = []string{LOCALE_ID, LOCALE_EN}
+
+//go:embed locales/*.json
+var localeFiles embed.FS
+
+// catalog maps locale to message ID to text.
+var catalog = map[string]map[string]string{}
+
+func init() {
+	for _, locale := range LOCALES {
+		content, err := localeFiles.ReadFile("locales/" + locale + ".json")
+		if err != nil {
+			panic("i18n: " + err.Error())
+		}
+
+		messages := map[string]string{}
+		if err := json.Unmarshal(content, &messages); err != nil {
+			panic("i18n: locales/" + locale + ".json: " + err.Error())
+		}
+		catalog[locale] = messages
+	}
+}
+
+type ctxKey struct{}
+
+// WithLocale stores the negotiated locale on the request context.
+func WithLocale(ctx context.Context, locale string) context.Context {
+	return context.WithValue(ctx, ctxKey{}, locale)
+}
+
+// FromContext returns the locale set by middleware.Locale, or DEFAULT_LOCALE.
+func FromContext(ctx context.Context) string {
+	if ctx != nil {
+		if locale, ok := ctx.Value(ctxKey{}).(string); ok && locale != "" {
+			return locale
+		}
+	}
+	return DEFAULT_LOCALE
+}
+
+func Supported(locale string) bool {
+	_, ok := catalog[locale]
+	return ok
+}
+
+// Negotiate picks the first supported language from an Accept-Language
+// header, e.g. "en-US,en;q=0.9,id;q=0.8" gives "en".
+func Negotiate(acceptLanguage string) string {
+	for _, part := range strings.Split(acceptLanguage, ",") {
+		tag := strings.TrimSpace(strings.SplitN(part, ";", 2)[0])
+		primary := strings.ToLower(strings.SplitN(tag, "-", 2)[0])
+		if Supported(primary) {
+			return primary
+		}
+	}
+	return DEFAULT_LOCALE
+}
+
+// Lookup returns the text for id in locale, falling back to DEFAULT_LOCALE.
+func Lookup(locale string, id string) (string, bool) {
+	if text, ok := catalog[locale][id]; ok {
+		return text, true
+	}
+	text, ok := catalog[DEFAULT_LOCALE][id]
+	return text, ok
+}
+
+// T translates id, replacing {0}, {1}, ... with args. Unknown IDs are
+// returned unchanged so plain strings pass through untouched.
+func T(locale string, id string, args ...string) string {
+	text, ok := Lookup(locale, id)
+	if !ok {
+		return id
+	}
+	for i, arg := range args {
+		text = strings.ReplaceAll(text, "{"+strconv.Itoa(i)+"}", arg)
+	}
+	return text
+}
@@ -0,0 +1,227 @@
+package jwk
+
+import (
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/golang-jwt/jwt/v4"
+)
+
+type (
+	// Key is a single signing or verification key identified by its kid.
+	// Verify-only keys (retired keys kept around during rotation) have a nil SignKey.
+	// A key with NotAfter set is refused once that time has passed.
+	Key struct {
+		ID        string
+		Method    jwt.SigningMethod
+		SignKey   any
+		VerifyKey any
+		NotAfter  time.Time
+	}
+
+	KeySet struct {
+		active *Key
+		keys   map[string]*Key
+	}
+
+	JSONWebKey struct {
+		Kty string `json:"kty"`
+		Kid string `json:"kid"`
+		Use string `json:"use"`
+		Alg string `json:"alg"`
+		N   string `json:"n,omitempty"`
+		E   string `json:"e,omitempty"`
+		Crv string `json:"crv,omitempty"`
+		X   string `json:"x,omitempty"`
+	}
+
+	JSONWebKeySet struct {
+		Keys []JSONWebKey `json:"keys"`
+	}
+)
+
+var (
+	ErrNoSigningKey = errors.New("no active signing key configured")
+	ErrUnknownKey   = errors.New("unknown key id")
+	ErrKeyRetired   = errors.New("key no longer accepted")
+)
+
+// NewHMACKeySet returns a key set with a single shared secret. Tokens signed by
+// it carry no kid, which keeps them compatible with tokens issued before rotation
+// was introduced.
+func NewHMACKeySet(secret string) *KeySet {
+	key := &Key{
+		Method:    jwt.SigningMethodHS256,
+		SignKey:   []byte(secret),
+		VerifyKey: []byte(secret),
+	}
+
+	return &KeySet{
+		active: key,
+		keys:   map[string]*Key{"": key},
+	}
+}
+
+// LoadKeySet reads every *.pem file in dir. The file name without extension is
+// used as the kid. Private keys can sign and verify, public keys only verify.
+// activeKid selects which private key signs new tokens.
+func LoadKeySet(dir string, activeKid string) (*KeySet, error) {
+	paths, err := filepath.Glob(filepath.Join(dir, "*.pem"))
+	if err != nil {
+		return nil, err
+	}
+
+	ks := &KeySet{keys: map[string]*Key{}}
+	for _, path := range paths {
+		raw, err := os.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+
+		kid := strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+		key, err := parsePEM(kid, raw)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", path, err)
+		}
+		ks.keys[kid] = key
+	}
+
+	active, ok := ks.keys[activeKid]
+	if !ok || active.SignKey == nil {
+		return nil, fmt.Errorf("%w: kid %q has no private key in %s", ErrNoSigningKey, activeKid, dir)
+	}
+	ks.active = active
+
+	return ks, nil
+}
+
+// AddVerifyKey registers an extra verification-only key, e.g. the previous
+// HMAC secret while migrating to asymmetric keys.
+func (ks *KeySet) AddVerifyKey(key *Key) {
+	ks.keys[key.ID] = &Key{
+		ID:        key.ID,
+		Method:    key.Method,
+		VerifyKey: key.VerifyKey,
+		NotAfter:  key.NotAfter,
+	}
+}
+
+// Sign signs claims with the active key and sets the kid header.
+func (ks *KeySet) Sign(claims jwt.Claims) (string, error) {
+	if ks.active == nil {
+		return "", ErrNoSigningKey
+	}
+
+	token := jwt.NewWithClaims(ks.active.Method, claims)
+	if ks.active.ID != "" {
+		token.Header["kid"] = ks.active.ID
+	}
+
+	return token.SignedString(ks.active.SignKey)
+}
+
+// Keyfunc resolves the verification key from the token kid and rejects tokens
+// whose alg does not match the key type.
+func (ks *KeySet) Keyfunc(t *jwt.Token) (any, error) {
+	kid, _ := t.Header["kid"].(string)
+
+	key, ok := ks.keys[kid]
+	if !ok {
+		return nil, fmt.Errorf("%w %q", ErrUnknownKey, kid)
+	}
+
+	if t.Method.Alg() != key.Method.Alg() {
+		return nil, fmt.Errorf("unexpected signing method %v", t.Header["alg"])
+	}
+
+	if !key.NotAfter.IsZero() && time.Now().After(key.NotAfter) {
+		return nil, fmt.Errorf("%w %q", ErrKeyRetired, kid)
+	}
+
+	return key.VerifyKey, nil
+}
+
+// JWKS returns the public part of every asymmetric key. Shared secrets are never exposed.
+func (ks *KeySet) JWKS() JSONWebKeySet {
+	set := JSONWebKeySet{Keys: []JSONWebKey{}}
+
+	for _, key := range ks.keys {
+		switch pub := key.VerifyKey.(type) {
+		case *rsa.PublicKey:
+			set.Keys = append(set.Keys, JSONWebKey{
+				Kty: "RSA",
+				Kid: key.ID,
+				Use: "sig",
+				Alg: key.Method.Alg(),
+				N:   base64.RawURLEncoding.EncodeToString(pub.N.Bytes()),
+				E:   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(pub.E)).Bytes()),
+			})
+		case ed25519.PublicKey:
+			set.Keys = append(set.Keys, JSONWebKey{
+				Kty: "OKP",
+				Kid: key.ID,
+				Use: "sig",
+				Alg: key.Method.Alg(),
+				Crv: "Ed25519",
+				X:   base64.RawURLEncoding.EncodeToString(pub),
+			})
+		}
+	}
+
+	sort.Slice(set.Keys, func(i, j int) bool {
+		return set.Keys[i].Kid < set.Keys[j].Kid
+	})
+
+	return set
+}
+
+func parsePEM(kid string, raw []byte) (*Key, error) {
+	block, _ := pem.Decode(raw)
+	if block == nil {
+		return nil, errors.New("invalid PEM data")
+	}
+
+	var (
+		parsed any
+		err    error
+	)
+	switch block.Type {
+	case "PRIVATE KEY":
+		parsed, err = x509.ParsePKCS8PrivateKey(block.Bytes)
+	case "RSA PRIVATE KEY":
+		parsed, err = x509.ParsePKCS1PrivateKey(block.Bytes)
+	case "PUBLIC KEY":
+		parsed, err = x509.ParsePKIXPublicKey(block.Bytes)
+	case "RSA PUBLIC KEY":
+		parsed, err = x509.ParsePKCS1PublicKey(block.Bytes)
+	default:
+		return nil, fmt.Errorf("unsupported PEM block %q", block.Type)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	switch k := parsed.(type) {
+	case *rsa.PrivateKey:
+		return &Key{ID: kid, Method: jwt.SigningMethodRS256, SignKey: k, VerifyKey: &k.PublicKey}, nil
+	case *rsa.PublicKey:
+		return &Key{ID: kid, Method: jwt.SigningMethodRS256, VerifyKey: k}, nil
+	case ed25519.PrivateKey:
+		return &Key{ID: kid, Method: jwt.SigningMethodEdDSA, SignKey: k, VerifyKey: k.Public().(ed25519.PublicKey)}, nil
+	case ed25519.PublicKey:
+		return &Key{ID: kid, Method: jwt.SigningMethodEdDSA, VerifyKey: k}, nil
+	default:
+		return nil, fmt.Errorf("unsupported key type %T", parsed)
+	}
+}
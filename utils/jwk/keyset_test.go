@@ -0,0 +1,279 @@
+package jwk
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"errors"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v4"
+)
+
+type testKeys struct {
+	dir     string
+	rsa     *rsa.PrivateKey    // rsa-2025.pem, private
+	ed      ed25519.PrivateKey // ed-2026.pem, private
+	retired *rsa.PrivateKey    // retired.pem, public only
+}
+
+// writeTestKeys creates a key directory with an RSA and an Ed25519 signing
+// key and the public half of a retired RSA key.
+func writeTestKeys(t *testing.T) testKeys {
+	t.Helper()
+
+	keys := testKeys{dir: t.TempDir()}
+
+	var err error
+	if keys.rsa, err = rsa.GenerateKey(rand.Reader, 2048); err != nil {
+		t.Fatalf("generate RSA key: %v", err)
+	}
+	if _, keys.ed, err = ed25519.GenerateKey(rand.Reader); err != nil {
+		t.Fatalf("generate Ed25519 key: %v", err)
+	}
+	if keys.retired, err = rsa.GenerateKey(rand.Reader, 2048); err != nil {
+		t.Fatalf("generate RSA key: %v", err)
+	}
+
+	writePEM(t, filepath.Join(keys.dir, "rsa-2025.pem"), "PRIVATE KEY", mustMarshal(t, x509.MarshalPKCS8PrivateKey, any(keys.rsa)))
+	writePEM(t, filepath.Join(keys.dir, "ed-2026.pem"), "PRIVATE KEY", mustMarshal(t, x509.MarshalPKCS8PrivateKey, any(keys.ed)))
+	writePEM(t, filepath.Join(keys.dir, "retired.pem"), "PUBLIC KEY", mustMarshal(t, x509.MarshalPKIXPublicKey, any(&keys.retired.PublicKey)))
+
+	return keys
+}
+
+func mustMarshal(t *testing.T, marshal func(any) ([]byte, error), key any) []byte {
+	t.Helper()
+
+	der, err := marshal(key)
+	if err != nil {
+		t.Fatalf("marshal key: %v", err)
+	}
+	return der
+}
+
+func writePEM(t *testing.T, path string, blockType string, der []byte) {
+	t.Helper()
+
+	if err := os.WriteFile(path, pem.EncodeToMemory(&pem.Block{Type: blockType, Bytes: der}), 0o600); err != nil {
+		t.Fatalf("write %s: %v", path, err)
+	}
+}
+
+func signWith(t *testing.T, method jwt.SigningMethod, kid string, key any) string {
+	t.Helper()
+
+	token := jwt.NewWithClaims(method, jwt.RegisteredClaims{
+		ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Minute)),
+	})
+	if kid != "" {
+		token.Header["kid"] = kid
+	}
+
+	signed, err := token.SignedString(key)
+	if err != nil {
+		t.Fatalf("sign token: %v", err)
+	}
+	return signed
+}
+
+func TestLoadKeySet(t *testing.T) {
+	keys := writeTestKeys(t)
+
+	tests := []struct {
+		name       string
+		activeKid  string
+		wantMethod string
+		wantErr    error
+	}{
+		{name: "RSA key active", activeKid: "rsa-2025", wantMethod: "RS256"},
+		{name: "Ed25519 key active", activeKid: "ed-2026", wantMethod: "EdDSA"},
+		{name: "public key only", activeKid: "retired", wantErr: ErrNoSigningKey},
+		{name: "unknown kid", activeKid: "missing", wantErr: ErrNoSigningKey},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ks, err := LoadKeySet(keys.dir, tt.activeKid)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("LoadKeySet() error = %v, want %v", err, tt.wantErr)
+			}
+			if tt.wantErr != nil {
+				return
+			}
+
+			signed, err := ks.Sign(jwt.RegisteredClaims{Subject: "user"})
+			if err != nil {
+				t.Fatalf("Sign() error = %v", err)
+			}
+
+			token, err := jwt.Parse(signed, ks.Keyfunc)
+			if err != nil {
+				t.Fatalf("parse signed token: %v", err)
+			}
+			if kid := token.Header["kid"]; kid != tt.activeKid {
+				t.Errorf("kid header = %v, want %q", kid, tt.activeKid)
+			}
+			if alg := token.Method.Alg(); alg != tt.wantMethod {
+				t.Errorf("alg = %q, want %q", alg, tt.wantMethod)
+			}
+		})
+	}
+}
+
+func TestLoadKeySetRejectsInvalidPEM(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "broken.pem"), []byte("not a key"), 0o600); err != nil {
+		t.Fatalf("write key: %v", err)
+	}
+
+	if _, err := LoadKeySet(dir, "broken"); err == nil {
+		t.Fatal("LoadKeySet() accepted an invalid PEM file")
+	}
+}
+
+func TestKeyfunc(t *testing.T) {
+	keys := writeTestKeys(t)
+	ks, err := LoadKeySet(keys.dir, "rsa-2025")
+	if err != nil {
+		t.Fatalf("LoadKeySet() error = %v", err)
+	}
+	ks.AddVerifyKey(&Key{ID: "legacy", Method: jwt.SigningMethodHS256, VerifyKey: []byte("secret"), NotAfter: time.Now().Add(time.Hour)})
+	ks.AddVerifyKey(&Key{ID: "expired", Method: jwt.SigningMethodHS256, VerifyKey: []byte("secret"), NotAfter: time.Now().Add(-time.Hour)})
+
+	rsaPublicDER := mustMarshal(t, x509.MarshalPKIXPublicKey, any(&keys.rsa.PublicKey))
+	none, err := jwt.NewWithClaims(jwt.SigningMethodNone, jwt.RegisteredClaims{}).SignedString(jwt.UnsafeAllowNoneSignatureType)
+	if err != nil {
+		t.Fatalf("sign none token: %v", err)
+	}
+	noneWithKid := jwt.NewWithClaims(jwt.SigningMethodNone, jwt.RegisteredClaims{})
+	noneWithKid.Header["kid"] = "rsa-2025"
+	noneWithKidSigned, err := noneWithKid.SignedString(jwt.UnsafeAllowNoneSignatureType)
+	if err != nil {
+		t.Fatalf("sign none token: %v", err)
+	}
+
+	tests := []struct {
+		name    string
+		token   string
+		wantErr error // when nil an invalid token only has to fail parsing
+		valid   bool
+	}{
+		{name: "active key", token: signWith(t, jwt.SigningMethodRS256, "rsa-2025", keys.rsa), valid: true},
+		{name: "other private key", token: signWith(t, jwt.SigningMethodEdDSA, "ed-2026", keys.ed), valid: true},
+		{name: "retired public key", token: signWith(t, jwt.SigningMethodRS256, "retired", keys.retired), valid: true},
+		{name: "legacy secret before its deadline", token: signWith(t, jwt.SigningMethodHS256, "legacy", []byte("secret")), valid: true},
+		{name: "legacy secret after its deadline", token: signWith(t, jwt.SigningMethodHS256, "expired", []byte("secret")), wantErr: ErrKeyRetired},
+		{name: "unknown kid", token: signWith(t, jwt.SigningMethodRS256, "stolen", keys.rsa), wantErr: ErrUnknownKey},
+		{name: "missing kid", token: signWith(t, jwt.SigningMethodRS256, "", keys.rsa), wantErr: ErrUnknownKey},
+		{name: "kid signed by another key", token: signWith(t, jwt.SigningMethodRS256, "retired", keys.rsa)},
+		{name: "alg none without kid", token: none, wantErr: ErrUnknownKey},
+		{name: "alg none with kid", token: noneWithKidSigned},
+		{name: "HS256 with the RSA public key as secret", token: signWith(t, jwt.SigningMethodHS256, "rsa-2025", rsaPublicDER)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			token, err := jwt.Parse(tt.token, ks.Keyfunc)
+			if tt.valid {
+				if err != nil || !token.Valid {
+					t.Fatalf("Parse() error = %v, want a valid token", err)
+				}
+				return
+			}
+
+			if err == nil {
+				t.Fatal("Parse() accepted the token")
+			}
+			if tt.wantErr != nil && !errors.Is(err, tt.wantErr) {
+				t.Errorf("Parse() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestHMACKeySet(t *testing.T) {
+	ks := NewHMACKeySet("secret")
+
+	signed, err := ks.Sign(jwt.RegisteredClaims{Subject: "user"})
+	if err != nil {
+		t.Fatalf("Sign() error = %v", err)
+	}
+
+	token, err := jwt.Parse(signed, ks.Keyfunc)
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	if _, ok := token.Header["kid"]; ok {
+		t.Errorf("HMAC token has a kid header %v", token.Header["kid"])
+	}
+
+	if _, err := jwt.Parse(signWith(t, jwt.SigningMethodHS256, "", []byte("other")), ks.Keyfunc); err == nil {
+		t.Error("Parse() accepted a token signed with another secret")
+	}
+	if jwks := ks.JWKS(); len(jwks.Keys) != 0 {
+		t.Errorf("JWKS() exposes the shared secret: %+v", jwks.Keys)
+	}
+}
+
+func TestJWKS(t *testing.T) {
+	keys := writeTestKeys(t)
+	ks, err := LoadKeySet(keys.dir, "rsa-2025")
+	if err != nil {
+		t.Fatalf("LoadKeySet() error = %v", err)
+	}
+	ks.AddVerifyKey(&Key{ID: "legacy", Method: jwt.SigningMethodHS256, VerifyKey: []byte("secret")})
+
+	jwks := ks.JWKS()
+
+	wantKids := []string{"ed-2026", "retired", "rsa-2025"}
+	if len(jwks.Keys) != len(wantKids) {
+		t.Fatalf("JWKS() has %d keys, want %d: %+v", len(jwks.Keys), len(wantKids), jwks.Keys)
+	}
+	for i, kid := range wantKids {
+		if jwks.Keys[i].Kid != kid {
+			t.Errorf("key %d kid = %q, want %q", i, jwks.Keys[i].Kid, kid)
+		}
+		if jwks.Keys[i].Use != "sig" {
+			t.Errorf("key %q use = %q, want sig", kid, jwks.Keys[i].Use)
+		}
+	}
+
+	ed := jwks.Keys[0]
+	if ed.Kty != "OKP" || ed.Crv != "Ed25519" || ed.Alg != "EdDSA" {
+		t.Errorf("Ed25519 key = %+v", ed)
+	}
+	if x := decodeBase64URL(t, ed.X); string(x) != string(keys.ed.Public().(ed25519.PublicKey)) {
+		t.Error("Ed25519 x does not match the public key")
+	}
+
+	rsaKey := jwks.Keys[2]
+	if rsaKey.Kty != "RSA" || rsaKey.Alg != "RS256" {
+		t.Errorf("RSA key = %+v", rsaKey)
+	}
+	n := new(big.Int).SetBytes(decodeBase64URL(t, rsaKey.N))
+	e := new(big.Int).SetBytes(decodeBase64URL(t, rsaKey.E))
+	if n.Cmp(keys.rsa.N) != 0 || e.Int64() != int64(keys.rsa.E) {
+		t.Error("RSA n/e do not match the public key")
+	}
+	if rsaKey.X != "" || rsaKey.Crv != "" {
+		t.Errorf("RSA key carries OKP fields: %+v", rsaKey)
+	}
+}
+
+func decodeBase64URL(t *testing.T, s string) []byte {
+	t.Helper()
+
+	b, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		t.Fatalf("decode %q: %v", s, err)
+	}
+	return b
+}
@@ -1,21 +1,110 @@
 package logger
 
-import "github.com/sirupsen/logrus"
+import (
+	"context"
+	"os"
 
-func Infof(format string, args ...any) {
-	logrus.SetFormatter(FormatConfig())
-	logrus.Infof(format, args...)
+	"github.com/Shabrinashsf/go-gin-gorm-boilerplate/config"
+	"github.com/sirupsen/logrus"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Fields are the structured key/value pairs attached to a log line.
+type Fields = logrus.Fields
+
+type ctxKey struct{}
+
+var base = logrus.New()
+
+func init() {
+	base.SetOutput(os.Stdout)
+	base.SetFormatter(textFormatter())
 }
 
-func Errorf(format string, args ...any) {
-	logrus.SetFormatter(FormatConfig())
-	logrus.Errorf(format, args...)
+// Setup applies LOG_LEVEL and LOG_FORMAT. It runs once, right after the
+// configuration is loaded; lines logged before that use text at info level.
+func Setup(cfg config.LogConfig) error {
+	level, err := logrus.ParseLevel(cfg.Level)
+	if err != nil {
+		return err
+	}
+	base.SetLevel(level)
+
+	if cfg.Format == "json" {
+		base.SetFormatter(&logrus.JSONFormatter{
+			TimestampFormat: "2006-01-02T15:04:05.000Z07:00",
+			FieldMap: logrus.FieldMap{
+				logrus.FieldKeyTime: "time",
+				logrus.FieldKeyMsg:  "message",
+			},
+		})
+	} else {
+		base.SetFormatter(textFormatter())
+	}
+
+	return nil
 }
 
-func FormatConfig() logrus.Formatter {
+func textFormatter() logrus.Formatter {
 	return &logrus.TextFormatter{
-		ForceColors:     true,
 		FullTimestamp:   true,
 		TimestampFormat: "2006-01-02 15:04:05",
 	}
 }
+
+// WithFields returns a context whose logger carries fields in addition to
+// the ones already attached, e.g. request_id and user_id set by middleware.
+func WithFields(ctx context.Context, fields Fields) context.Context {
+	return context.WithValue(ctx, ctxKey{}, entry(ctx).WithFields(fields))
+}
+
+// FromContext returns the request-scoped logger, falling back to the global
+// one. The active trace and span IDs are added so logs and traces link up.
+func FromContext(ctx context.Context) *logrus.Entry {
+	e := entry(ctx)
+
+	if span := trace.SpanContextFromContext(ctx); span.IsValid() {
+		e = e.WithFields(Fields{
+			"trace_id": span.TraceID().String(),
+			"span_id":  span.SpanID().String(),
+		})
+	}
+
+	return e
+}
+
+func entry(ctx context.Context) *logrus.Entry {
+	if ctx != nil {
+		if e, ok := ctx.Value(ctxKey{}).(*logrus.Entry); ok {
+			return e
+		}
+	}
+	return logrus.NewEntry(base)
+}
+
+func Debugf(format string, args ...any) {
+	base.Debugf(format, args...)
+}
+
+func Infof(format string, args ...any) {
+	base.Infof(format, args...)
+}
+
+func Warnf(format string, args ...any) {
+	base.Warnf(format, args...)
+}
+
+func Errorf(format string, args ...any) {
+	base.Errorf(format, args...)
+}
+
+// Fatalf logs at error level and exits with status 1.
+func Fatalf(format string, args ...any) {
+	base.Fatalf(format, args...)
+}
+
+// DebugEnabled reports whether LOG_LEVEL lets debug lines through, for
+// callers that only build verbose fields when they will be written.
+func DebugEnabled() bool {
+	return base.IsLevelEnabled(logrus.DebugLevel)
+}
@@ -0,0 +1,53 @@
+package mailer
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"time"
+)
+
+// fileTransport writes every message as an .eml file into a maildir, so it
+// can be opened with any mail client or `mutt -f <dir>`.
+type fileTransport struct {
+	dir     string
+	counter atomic.Uint64
+}
+
+func NewFileTransport(dir string) Transport {
+	return &fileTransport{dir: dir}
+}
+
+func (t *fileTransport) Name() string { return TRANSPORT_FILE }
+
+// Send follows the maildir delivery steps: write into tmp/, then rename
+// into new/ so readers never see a partial file.
+func (t *fileTransport) Send(ctx context.Context, msg Message) error {
+	for _, sub := range []string{"tmp", "new", "cur"} {
+		if err := os.MkdirAll(filepath.Join(t.dir, sub), 0o755); err != nil {
+			return err
+		}
+	}
+
+	hostname, _ := os.Hostname()
+	name := fmt.Sprintf("%d.%d_%d.%s.eml", time.Now().UnixNano(), os.Getpid(), t.counter.Add(1), hostname)
+	tmpPath := filepath.Join(t.dir, "tmp", name)
+
+	file, err := os.OpenFile(tmpPath, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0o644)
+	if err != nil {
+		return err
+	}
+	if _, err := gomailMessage(msg).WriteTo(file); err != nil {
+		file.Close()
+		os.Remove(tmpPath)
+		return err
+	}
+	if err := file.Close(); err != nil {
+		os.Remove(tmpPath)
+		return err
+	}
+
+	return os.Rename(tmpPath, filepath.Join(t.dir, "new", name))
+}
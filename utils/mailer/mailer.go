@@ -1,65 +1,141 @@
 package mailer
 
 import (
-	"os"
-	"strconv"
+	"context"
 
-	"gopkg.in/gomail.v2"
+	"github.com/Shabrinashsf/go-gin-gorm-boilerplate/config"
+	"github.com/Shabrinashsf/go-gin-gorm-boilerplate/utils/metrics"
+	"github.com/Shabrinashsf/go-gin-gorm-boilerplate/utils/tracing"
+	"go.opentelemetry.io/otel/attribute"
 )
 
 type (
 	Config struct {
-		Host         string
-		Port         int
-		AuthEmail    string
-		AuthPassword string
+		SenderName  string
+		SenderEmail string
+	}
+
+	// Email is a rendered template: Body is the HTML part and Text the
+	// plain-text alternative.
+	Email struct {
+		Subject string
+		Body    string
+		Text    string
 	}
 
 	Mailer struct {
 		emailConfig *Config
-		Body        string
-		Error       error
+		transport   Transport
+		Email
+		Error error
 	}
 )
 
-func NewMailer() Mailer {
-	port, err := strconv.Atoi(os.Getenv("SMTP_PORT"))
-	if err != nil {
-		panic("invalid smtp port")
+// NewMailer sends through the transport selected by MAIL_TRANSPORT.
+func NewMailer(mailCfg config.MailConfig, smtpCfg config.SMTPConfig) Mailer {
+	var transport Transport
+	switch mailCfg.Transport {
+	case TRANSPORT_FILE:
+		transport = NewFileTransport(mailCfg.FileDir)
+	case TRANSPORT_STDOUT:
+		transport = NewStdoutTransport()
+	case TRANSPORT_MEMORY:
+		transport = NewMemoryTransport()
+	default:
+		transport = NewSMTPTransport(smtpCfg)
+	}
+
+	return NewMailerWithTransport(smtpCfg, transport)
+}
+
+// NewMailerWithTransport is NewMailer with a transport of the caller's choice,
+// e.g. a memory transport in tests.
+func NewMailerWithTransport(smtpCfg config.SMTPConfig, transport Transport) Mailer {
+	senderEmail := smtpCfg.SenderEmail
+	if senderEmail == "" {
+		senderEmail = smtpCfg.AuthEmail
 	}
 
 	emailConfig := &Config{
-		Host:         os.Getenv("SMTP_HOST"),
-		Port:         port,
-		AuthEmail:    os.Getenv("SMTP_AUTH_EMAIL"),
-		AuthPassword: os.Getenv("SMTP_AUTH_PASSWORD"),
+		SenderName:  smtpCfg.SenderName,
+		SenderEmail: senderEmail,
 	}
 
 	return Mailer{
 		emailConfig,
-		"",
+		transport,
+		Email{},
 		nil,
 	}
 }
 
-func (m Mailer) SendEmail(toEmail, subject string) Mailer {
-	mailer := gomail.NewMessage()
-	mailer.SetHeader("From", os.Getenv("SMTP_SENDER_NAME")+" <"+m.emailConfig.AuthEmail+">")
-	mailer.SetHeader("To", toEmail)
-	mailer.SetHeader("Subject", subject)
-	mailer.SetBody("text/html", m.Body)
-
-	dialer := gomail.NewDialer(
-		m.emailConfig.Host,
-		m.emailConfig.Port,
-		m.emailConfig.AuthEmail,
-		m.emailConfig.AuthPassword,
+// Transport is the transport mail is sent through.
+func (m Mailer) Transport() Transport {
+	return m.transport
+}
+
+// Mailbox returns the captured messages of transports that keep them.
+func (m Mailer) Mailbox() (Mailbox, bool) {
+	mailbox, ok := m.transport.(Mailbox)
+	return mailbox, ok
+}
+
+// SendEmail sends the rendered template of m to a single recipient. Use
+// NewMessage for more recipients, attachments or custom headers.
+func (m Mailer) SendEmail(ctx context.Context, toEmail, subject string) Mailer {
+	m.Error = m.NewMessage().To(toEmail).Subject(subject).Send(ctx)
+	return m
+}
+
+// Send hands a built message to the transport.
+func (m Mailer) Send(ctx context.Context, msg Message) (err error) {
+	ctx, span := tracing.Start(ctx, "mail.Send",
+		attribute.String("mail.transport", m.transport.Name()),
+		attribute.String("email.subject", msg.Subject),
+		attribute.Int("mail.recipients", len(msg.Recipients())),
+		attribute.Int("mail.attachments", len(msg.Attachments)+len(msg.Inline)),
+	)
+	defer func() { tracing.End(span, err) }()
+
+	err = m.transport.Send(ctx, msg)
+	metrics.EmailSent(err == nil)
+	return err
+}
+
+// SendBatch sends the messages over one connection when the transport
+// supports it, one by one otherwise. It returns one error per message.
+func (m Mailer) SendBatch(ctx context.Context, msgs []Message) []error {
+	ctx, span := tracing.Start(ctx, "mail.SendBatch",
+		attribute.String("mail.transport", m.transport.Name()),
+		attribute.Int("mail.batch_size", len(msgs)),
 	)
 
-	if err := dialer.DialAndSend(mailer); err != nil {
-		m.Error = err
-		return m
+	var errs []error
+	if batch, ok := m.transport.(BatchTransport); ok {
+		errs = batch.SendBatch(ctx, msgs)
+	} else {
+		errs = make([]error, len(msgs))
+		for i, msg := range msgs {
+			errs[i] = m.transport.Send(ctx, msg)
+		}
 	}
 
-	return m
+	var failed error
+	for _, err := range errs {
+		metrics.EmailSent(err == nil)
+		if err != nil && failed == nil {
+			failed = err
+		}
+	}
+	tracing.End(span, failed)
+	return errs
+}
+
+// Sender is the From address NewMessage puts on every message.
+func (m Mailer) Sender() string {
+	return m.from()
+}
+
+func (m Mailer) from() string {
+	return m.emailConfig.SenderName + " <" + m.emailConfig.SenderEmail + ">"
 }
@@ -1,32 +1,13 @@
 package mailer
 
-import (
-	"bytes"
-	"os"
-	"text/template"
-)
-
-// MakeMail function to generate email body from template file and data
-func (m Mailer) MakeMail(path string, data any) Mailer {
-	readHtml, err := os.ReadFile(path)
-	if err != nil {
-		m.Error = err
-		return m
-	}
-
-	tmpl, err := template.New("custom").Parse(string(readHtml))
+// MakeMail renders the template name in locale into the mailer, see Render.
+func (m Mailer) MakeMail(name string, locale string, data any) Mailer {
+	email, err := Render(name, locale, data)
 	if err != nil {
 		m.Error = err
 		return m
 	}
 
-	var strMail bytes.Buffer
-	if err := tmpl.Execute(&strMail, data); err != nil {
-		m.Error = err
-		return m
-	}
-
-	m.Body = strMail.String()
-
+	m.Email = email
 	return m
 }
@@ -0,0 +1,73 @@
+package mailer
+
+import (
+	"context"
+	"sync"
+
+	"github.com/google/uuid"
+)
+
+// MEMORY_MAILBOX_SIZE caps how many messages the memory transport keeps,
+// the oldest are dropped first.
+var MEMORY_MAILBOX_SIZE = 200
+
+// CapturedMessage is a message kept by the memory transport.
+type CapturedMessage struct {
+	ID string
+	Message
+}
+
+type memoryTransport struct {
+	mu       sync.RWMutex
+	messages []CapturedMessage
+}
+
+// NewMemoryTransport keeps sent messages in memory. The returned value also
+// implements Mailbox.
+func NewMemoryTransport() Transport {
+	return &memoryTransport{}
+}
+
+func (t *memoryTransport) Name() string { return TRANSPORT_MEMORY }
+
+func (t *memoryTransport) Send(ctx context.Context, msg Message) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.messages = append(t.messages, CapturedMessage{ID: uuid.NewString(), Message: msg})
+	if overflow := len(t.messages) - MEMORY_MAILBOX_SIZE; overflow > 0 {
+		t.messages = append([]CapturedMessage(nil), t.messages[overflow:]...)
+	}
+	return nil
+}
+
+// Messages returns the captured messages, newest first.
+func (t *memoryTransport) Messages() []CapturedMessage {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	messages := make([]CapturedMessage, 0, len(t.messages))
+	for i := len(t.messages) - 1; i >= 0; i-- {
+		messages = append(messages, t.messages[i])
+	}
+	return messages
+}
+
+func (t *memoryTransport) Message(id string) (CapturedMessage, bool) {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	for _, msg := range t.messages {
+		if msg.ID == id {
+			return msg, true
+		}
+	}
+	return CapturedMessage{}, false
+}
+
+func (t *memoryTransport) Clear() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.messages = nil
+}
@@ -0,0 +1,208 @@
+package mailer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/mail"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// MAX_ATTACHMENTS_SIZE caps the combined size of the files of one message,
+// most providers reject messages above 25MB once base64 encoded.
+var MAX_ATTACHMENTS_SIZE = 18 << 20
+
+var (
+	ErrNoRecipients        = errors.New("mailer: message has no recipients")
+	ErrAttachmentsTooLarge = errors.New("mailer: attachments exceed the size limit")
+)
+
+type (
+	// MessageBuilder assembles a Message. The first error is kept and
+	// returned by Build or Send, so calls can be chained without checks.
+	MessageBuilder struct {
+		mailer Mailer
+		msg    Message
+		size   int
+		err    error
+	}
+
+	// FileGetter fetches a stored object, storage.AwsS3 satisfies it.
+	FileGetter interface {
+		GetFile(ctx context.Context, objectKey string) (io.ReadCloser, string, string, error)
+	}
+)
+
+// NewMessage starts a message from the sender config and the rendered
+// template of m, if any.
+func (m Mailer) NewMessage() *MessageBuilder {
+	return &MessageBuilder{
+		mailer: m,
+		msg: Message{
+			From:    m.from(),
+			Subject: m.Subject,
+			HTML:    m.Body,
+			Text:    m.Text,
+		},
+		err: m.Error,
+	}
+}
+
+func (b *MessageBuilder) To(addresses ...string) *MessageBuilder {
+	b.msg.To = b.appendAddresses(b.msg.To, addresses)
+	return b
+}
+
+func (b *MessageBuilder) Cc(addresses ...string) *MessageBuilder {
+	b.msg.Cc = b.appendAddresses(b.msg.Cc, addresses)
+	return b
+}
+
+func (b *MessageBuilder) Bcc(addresses ...string) *MessageBuilder {
+	b.msg.Bcc = b.appendAddresses(b.msg.Bcc, addresses)
+	return b
+}
+
+func (b *MessageBuilder) ReplyTo(address string) *MessageBuilder {
+	if addresses := b.appendAddresses(nil, []string{address}); len(addresses) > 0 {
+		b.msg.ReplyTo = addresses[0]
+	}
+	return b
+}
+
+func (b *MessageBuilder) Subject(subject string) *MessageBuilder {
+	b.msg.Subject = subject
+	return b
+}
+
+// Body replaces the HTML and text parts, e.g. with the result of MakeMail.
+func (b *MessageBuilder) Body(email Email) *MessageBuilder {
+	b.msg.HTML = email.Body
+	b.msg.Text = email.Text
+	return b
+}
+
+// Header sets a custom header. From, To, Cc, Bcc, Reply-To, Subject and
+// Date are set by the builder and can't be overridden here.
+func (b *MessageBuilder) Header(name string, values ...string) *MessageBuilder {
+	if b.msg.Headers == nil {
+		b.msg.Headers = map[string][]string{}
+	}
+	b.msg.Headers[name] = values
+	return b
+}
+
+// ListUnsubscribe sets the List-Unsubscribe header to the given https: or
+// mailto: links. With an https link the one-click header of RFC 8058 is
+// added too, the link must then accept a POST without a session.
+func (b *MessageBuilder) ListUnsubscribe(links ...string) *MessageBuilder {
+	values := make([]string, 0, len(links))
+	oneClick := false
+	for _, link := range links {
+		values = append(values, "<"+link+">")
+		oneClick = oneClick || strings.HasPrefix(link, "https://")
+	}
+
+	b.Header("List-Unsubscribe", strings.Join(values, ", "))
+	if oneClick {
+		b.Header("List-Unsubscribe-Post", "List-Unsubscribe=One-Click")
+	}
+	return b
+}
+
+// Attach reads r into the message so it can be retried or captured. An
+// empty contentType is guessed from the file extension.
+func (b *MessageBuilder) Attach(name, contentType string, r io.Reader) *MessageBuilder {
+	if attachment, ok := b.read(name, contentType, r); ok {
+		b.msg.Attachments = append(b.msg.Attachments, attachment)
+	}
+	return b
+}
+
+// Embed adds an inline image, reference it from the HTML as cid:<name>.
+func (b *MessageBuilder) Embed(name, contentType string, r io.Reader) *MessageBuilder {
+	if attachment, ok := b.read(name, contentType, r); ok {
+		b.msg.Inline = append(b.msg.Inline, attachment)
+	}
+	return b
+}
+
+// AttachFile attaches an object from storage under its own file name.
+func (b *MessageBuilder) AttachFile(ctx context.Context, files FileGetter, objectKey string) *MessageBuilder {
+	if b.err != nil {
+		return b
+	}
+
+	body, contentType, filename, err := files.GetFile(ctx, objectKey)
+	if err != nil {
+		b.err = fmt.Errorf("mailer: attach %s: %w", objectKey, err)
+		return b
+	}
+	defer body.Close()
+
+	return b.Attach(filename, contentType, body)
+}
+
+// Build returns the message or the first error met while building it.
+func (b *MessageBuilder) Build() (Message, error) {
+	if b.err != nil {
+		return Message{}, b.err
+	}
+	if len(b.msg.Recipients()) == 0 {
+		return Message{}, ErrNoRecipients
+	}
+
+	msg := b.msg
+	msg.Date = time.Now()
+	return msg, nil
+}
+
+// Send builds the message and sends it through the mailer's transport.
+func (b *MessageBuilder) Send(ctx context.Context) error {
+	msg, err := b.Build()
+	if err != nil {
+		return err
+	}
+	return b.mailer.Send(ctx, msg)
+}
+
+func (b *MessageBuilder) appendAddresses(list []string, addresses []string) []string {
+	for _, address := range addresses {
+		if b.err != nil {
+			return list
+		}
+		if _, err := mail.ParseAddress(address); err != nil {
+			b.err = fmt.Errorf("mailer: invalid address %q: %w", address, err)
+			return list
+		}
+		list = append(list, address)
+	}
+	return list
+}
+
+func (b *MessageBuilder) read(name, contentType string, r io.Reader) (Attachment, bool) {
+	if b.err != nil {
+		return Attachment{}, false
+	}
+
+	// one byte over the remaining budget is enough to tell it doesn't fit
+	content, err := io.ReadAll(io.LimitReader(r, int64(MAX_ATTACHMENTS_SIZE-b.size)+1))
+	if err != nil {
+		b.err = fmt.Errorf("mailer: read %s: %w", name, err)
+		return Attachment{}, false
+	}
+	if b.size+len(content) > MAX_ATTACHMENTS_SIZE {
+		b.err = ErrAttachmentsTooLarge
+		return Attachment{}, false
+	}
+	b.size += len(content)
+
+	if contentType == "" {
+		contentType = mime.TypeByExtension(filepath.Ext(name))
+	}
+	return Attachment{Name: filepath.Base(name), ContentType: contentType, Content: content}, true
+}
@@ -0,0 +1,204 @@
+package mailer
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"net/mail"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/Shabrinashsf/go-gin-gorm-boilerplate/config"
+)
+
+func newTestMailer(transport Transport) Mailer {
+	return NewMailerWithTransport(config.SMTPConfig{SenderName: "App", SenderEmail: "noreply@example.com"}, transport)
+}
+
+// withAttachmentsLimit lowers MAX_ATTACHMENTS_SIZE for one test.
+func withAttachmentsLimit(t *testing.T, limit int) {
+	t.Helper()
+
+	saved := MAX_ATTACHMENTS_SIZE
+	MAX_ATTACHMENTS_SIZE = limit
+	t.Cleanup(func() { MAX_ATTACHMENTS_SIZE = saved })
+}
+
+func TestMessageBuilderAddresses(t *testing.T) {
+	tests := []struct {
+		name    string
+		build   func(b *MessageBuilder) *MessageBuilder
+		wantErr string
+		check   func(t *testing.T, msg Message)
+	}{
+		{
+			name: "every kind of recipient",
+			build: func(b *MessageBuilder) *MessageBuilder {
+				return b.To("ann@example.com", "Bob <bob@example.com>").Cc("cc@example.com").Bcc("audit@example.com").ReplyTo("support@example.com")
+			},
+			check: func(t *testing.T, msg Message) {
+				if got := strings.Join(msg.Recipients(), " "); got != "ann@example.com Bob <bob@example.com> cc@example.com audit@example.com" {
+					t.Errorf("Recipients() = %s", got)
+				}
+				if msg.ReplyTo != "support@example.com" || msg.From != "App <noreply@example.com>" {
+					t.Errorf("ReplyTo/From = %q/%q", msg.ReplyTo, msg.From)
+				}
+				if msg.Date.IsZero() {
+					t.Error("Build() left Date empty")
+				}
+			},
+		},
+		{
+			name:  "bcc only is enough",
+			build: func(b *MessageBuilder) *MessageBuilder { return b.Bcc("audit@example.com") },
+		},
+		{name: "no recipients", build: func(b *MessageBuilder) *MessageBuilder { return b.Subject("hi") }, wantErr: ErrNoRecipients.Error()},
+		{name: "invalid to", build: func(b *MessageBuilder) *MessageBuilder { return b.To("not an address") }, wantErr: `invalid address "not an address"`},
+		{name: "invalid cc", build: func(b *MessageBuilder) *MessageBuilder { return b.To("ann@example.com").Cc("@example.com") }, wantErr: `invalid address "@example.com"`},
+		{name: "invalid bcc", build: func(b *MessageBuilder) *MessageBuilder { return b.To("ann@example.com").Bcc("audit@") }, wantErr: `invalid address "audit@"`},
+		{name: "invalid reply-to", build: func(b *MessageBuilder) *MessageBuilder { return b.To("ann@example.com").ReplyTo("support") }, wantErr: `invalid address "support"`},
+		{
+			// later calls neither clear nor replace the first error
+			name: "first error kept",
+			build: func(b *MessageBuilder) *MessageBuilder {
+				return b.To("first", "ann@example.com").Cc("second").To("bob@example.com")
+			},
+			wantErr: `invalid address "first"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			msg, err := tt.build(newTestMailer(NewMemoryTransport()).NewMessage()).Build()
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("Build() error = %v, want one containing %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Build() error = %v", err)
+			}
+			if tt.check != nil {
+				tt.check(t, msg)
+			}
+		})
+	}
+}
+
+func TestMessageBuilderAttachmentsLimit(t *testing.T) {
+	withAttachmentsLimit(t, 10)
+
+	tests := []struct {
+		name    string
+		files   []string // attached in order, "inline:" ones are embedded
+		wantErr error
+	}{
+		{name: "exactly at the limit", files: []string{"0123456789"}},
+		{name: "one file over the limit", files: []string{"0123456789A"}, wantErr: ErrAttachmentsTooLarge},
+		{name: "files over the limit together", files: []string{"012345", "6789A"}, wantErr: ErrAttachmentsTooLarge},
+		{name: "inline images count too", files: []string{"012345", "inline:6789A"}, wantErr: ErrAttachmentsTooLarge},
+		{name: "files after the error are ignored", files: []string{"0123456789A", "0"}, wantErr: ErrAttachmentsTooLarge},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := newTestMailer(NewMemoryTransport()).NewMessage().To("ann@example.com")
+			for i, file := range tt.files {
+				if content, ok := strings.CutPrefix(file, "inline:"); ok {
+					b.Embed("logo.png", "", strings.NewReader(content))
+				} else {
+					b.Attach(filepath.Join("reports", string(rune('a'+i))+".pdf"), "", strings.NewReader(file))
+				}
+			}
+
+			msg, err := b.Build()
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Build() error = %v, want %v", err, tt.wantErr)
+			}
+			if tt.wantErr != nil {
+				return
+			}
+
+			attachment := msg.Attachments[0]
+			if attachment.Name != "a.pdf" || attachment.ContentType != "application/pdf" || string(attachment.Content) != tt.files[0] {
+				t.Errorf("attachment = %s %s %q, want a.pdf application/pdf with its content", attachment.Name, attachment.ContentType, attachment.Content)
+			}
+		})
+	}
+}
+
+func TestMessageBuilderStreamsOnlyTheBudget(t *testing.T) {
+	withAttachmentsLimit(t, 10)
+
+	// a huge file is not read past the limit
+	r := &countingReader{r: io.LimitReader(zeros{}, 1<<30)}
+	_, err := newTestMailer(NewMemoryTransport()).NewMessage().To("ann@example.com").Attach("big.bin", "", r).Build()
+	if !errors.Is(err, ErrAttachmentsTooLarge) {
+		t.Fatalf("Build() error = %v, want %v", err, ErrAttachmentsTooLarge)
+	}
+	if r.n > 11 {
+		t.Errorf("read %d bytes of an attachment over a 10 byte limit", r.n)
+	}
+}
+
+func TestBccNotInHeaders(t *testing.T) {
+	dir := t.TempDir()
+	m := newTestMailer(NewFileTransport(dir))
+
+	err := m.NewMessage().
+		To("ann@example.com").
+		Cc("cc@example.com").
+		Bcc("hidden@example.com").
+		Subject("Report").
+		Body(Email{Body: "<p>Hi</p>", Text: "Hi\n"}).
+		Attach("report.txt", "text/plain", strings.NewReader("numbers")).
+		Send(context.Background())
+	if err != nil {
+		t.Fatalf("Send() error = %v", err)
+	}
+
+	files, err := filepath.Glob(filepath.Join(dir, "new", "*.eml"))
+	if err != nil || len(files) != 1 {
+		t.Fatalf("written messages = %v, %v, want one", files, err)
+	}
+	raw, err := os.ReadFile(files[0])
+	if err != nil {
+		t.Fatalf("read message: %v", err)
+	}
+
+	parsed, err := mail.ReadMessage(bytes.NewReader(raw))
+	if err != nil {
+		t.Fatalf("parse written message: %v", err)
+	}
+	if parsed.Header.Get("To") != "ann@example.com" || parsed.Header.Get("Cc") != "cc@example.com" {
+		t.Errorf("To/Cc headers = %q/%q", parsed.Header.Get("To"), parsed.Header.Get("Cc"))
+	}
+	if bcc := parsed.Header.Get("Bcc"); bcc != "" {
+		t.Errorf("Bcc header written: %q", bcc)
+	}
+	if bytes.Contains(raw, []byte("hidden@example.com")) {
+		t.Error("the Bcc address appears in the written message")
+	}
+}
+
+type zeros struct{}
+
+func (zeros) Read(p []byte) (int, error) {
+	clear(p)
+	return len(p), nil
+}
+
+type countingReader struct {
+	r io.Reader
+	n int
+}
+
+func (c *countingReader) Read(p []byte) (int, error) {
+	n, err := c.r.Read(p)
+	c.n += n
+	return n, err
+}
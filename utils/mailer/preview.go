@@ -0,0 +1,27 @@
+package mailer
+
+// PREVIEW_DATA is the sample data `go run main.go --mail-preview <name>`
+// renders each template with. Add an entry next to every new template.
+var PREVIEW_DATA = map[string]map[string]any{
+	"verification_email": {
+		"Email":  "jane.doe@example.com",
+		"Verify": "https://example.com/verify-email?token=sample-token",
+	},
+	"forgot_password_email": {
+		"Email":  "jane.doe@example.com",
+		"Verify": "https://example.com/reset-password?token=sample-token",
+	},
+	"change_email_email": {
+		"Email":  "jane.new@example.com",
+		"Verify": "https://example.com/confirm-email?token=sample-token",
+	},
+	"email_changed_email": {
+		"Email":    "jane.doe@example.com",
+		"NewEmail": "jane.new@example.com",
+	},
+	"unlock_account_email": {
+		"Email":       "jane.doe@example.com",
+		"Verify":      "https://example.com/unlock-account?token=sample-token",
+		"LockedUntil": "17 Oct 2026 10:15",
+	},
+}
@@ -0,0 +1,90 @@
+package mailer
+
+import (
+	"context"
+	"net/mail"
+
+	"github.com/Shabrinashsf/go-gin-gorm-boilerplate/config"
+	"gopkg.in/gomail.v2"
+)
+
+type smtpTransport struct {
+	dialer *gomail.Dialer
+}
+
+// NewSMTPTransport dials per message on Send and once per batch on
+// SendBatch.
+func NewSMTPTransport(cfg config.SMTPConfig) Transport {
+	return &smtpTransport{
+		dialer: gomail.NewDialer(cfg.Host, cfg.Port, cfg.AuthEmail, cfg.AuthPassword),
+	}
+}
+
+func (t *smtpTransport) Name() string { return TRANSPORT_SMTP }
+
+func (t *smtpTransport) Send(ctx context.Context, msg Message) error {
+	return t.dialer.DialAndSend(gomailMessage(msg))
+}
+
+// SendBatch reuses one connection for the whole batch. A failed message
+// can leave the SMTP session mid-transaction, so the connection is dropped
+// and the next message dials again.
+func (t *smtpTransport) SendBatch(ctx context.Context, msgs []Message) []error {
+	errs := make([]error, len(msgs))
+
+	var sender gomail.SendCloser
+	defer func() {
+		if sender != nil {
+			sender.Close()
+		}
+	}()
+
+	for i, msg := range msgs {
+		if err := ctx.Err(); err != nil {
+			errs[i] = err
+			continue
+		}
+
+		if sender == nil {
+			s, err := t.dialer.Dial()
+			if err != nil {
+				errs[i] = err
+				continue
+			}
+			sender = s
+		}
+
+		from, to, err := envelope(msg)
+		if err != nil {
+			errs[i] = err
+			continue
+		}
+
+		if err := sender.Send(from, to, gomailMessage(msg)); err != nil {
+			errs[i] = err
+			sender.Close()
+			sender = nil
+		}
+	}
+
+	return errs
+}
+
+// envelope is the bare SMTP sender and recipient addresses of msg.
+func envelope(msg Message) (string, []string, error) {
+	from, err := mail.ParseAddress(msg.From)
+	if err != nil {
+		return "", nil, err
+	}
+
+	recipients := msg.Recipients()
+	to := make([]string, 0, len(recipients))
+	for _, recipient := range recipients {
+		addr, err := mail.ParseAddress(recipient)
+		if err != nil {
+			return "", nil, err
+		}
+		to = append(to, addr.Address)
+	}
+	return from.Address, to, nil
+}
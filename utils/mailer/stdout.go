@@ -0,0 +1,40 @@
+package mailer
+
+import (
+	"context"
+	"strings"
+
+	"github.com/Shabrinashsf/go-gin-gorm-boilerplate/utils/logger"
+)
+
+// stdoutTransport only logs the message, the text part is included at debug level.
+type stdoutTransport struct{}
+
+func NewStdoutTransport() Transport {
+	return stdoutTransport{}
+}
+
+func (stdoutTransport) Name() string { return TRANSPORT_STDOUT }
+
+func (stdoutTransport) Send(ctx context.Context, msg Message) error {
+	fields := logger.Fields{
+		"from":    msg.From,
+		"to":      strings.Join(msg.To, ", "),
+		"subject": msg.Subject,
+	}
+	if len(msg.Cc) > 0 {
+		fields["cc"] = strings.Join(msg.Cc, ", ")
+	}
+	if len(msg.Bcc) > 0 {
+		fields["bcc"] = strings.Join(msg.Bcc, ", ")
+	}
+	if files := len(msg.Attachments) + len(msg.Inline); files > 0 {
+		fields["attachments"] = files
+	}
+	if logger.DebugEnabled() {
+		fields["text"] = msg.Text
+	}
+
+	logger.FromContext(ctx).WithFields(fields).Info("email captured by stdout transport")
+	return nil
+}
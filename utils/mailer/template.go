@@ -0,0 +1,152 @@
+package mailer
+
+import (
+	"bytes"
+	"embed"
+	"fmt"
+	"html"
+	"html/template"
+	"io/fs"
+	"path"
+	"sort"
+	"strings"
+	"sync"
+)
+
+//go:embed template
+var templateFS embed.FS
+
+const (
+	TEMPLATE_LAYOUT = "layout"
+
+	templateGlob = "template/*.html"
+	layoutGlob   = "template/layouts/*.html"
+	partialGlob  = "template/partials/*.html"
+)
+
+var (
+	templatesOnce sync.Once
+	templates     map[string]*template.Template
+	templatesErr  error
+
+	templateFuncs = template.FuncMap{
+		"dict": dict,
+	}
+)
+
+// Render executes a template from the template directory. name is the file
+// name without extension, e.g. "verification_email", and locale picks the
+// name.<locale>.html variant when there is one. Every page defines
+// "subject" and "content" and is wrapped in the layout, the plain-text part
+// is generated from the resulting HTML.
+func Render(name string, locale string, data any) (Email, error) {
+	tmpl, err := lookupTemplate(name, locale)
+	if err != nil {
+		return Email{}, err
+	}
+
+	var subject, body bytes.Buffer
+	if err := tmpl.ExecuteTemplate(&subject, "subject", data); err != nil {
+		return Email{}, err
+	}
+	if err := tmpl.ExecuteTemplate(&body, TEMPLATE_LAYOUT, data); err != nil {
+		return Email{}, err
+	}
+
+	text, err := HTMLToText(body.String())
+	if err != nil {
+		return Email{}, err
+	}
+
+	return Email{
+		// the subject is a header, not markup
+		Subject: strings.Join(strings.Fields(html.UnescapeString(subject.String())), " "),
+		Body:    body.String(),
+		Text:    text,
+	}, nil
+}
+
+// TemplateNames lists the templates Render accepts, without locale variants.
+func TemplateNames() ([]string, error) {
+	pages, err := loadTemplates()
+	if err != nil {
+		return nil, err
+	}
+
+	var names []string
+	for name := range pages {
+		if !strings.Contains(name, ".") {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+func lookupTemplate(name string, locale string) (*template.Template, error) {
+	pages, err := loadTemplates()
+	if err != nil {
+		return nil, err
+	}
+
+	if tmpl, ok := pages[name+"."+locale]; ok {
+		return tmpl, nil
+	}
+	if tmpl, ok := pages[name]; ok {
+		return tmpl, nil
+	}
+	return nil, fmt.Errorf("email template %q not found", name)
+}
+
+// loadTemplates parses every page once, each into its own set together with
+// the layouts and partials so pages can override the blocks they share.
+func loadTemplates() (map[string]*template.Template, error) {
+	templatesOnce.Do(func() {
+		templates, templatesErr = parseTemplates(templateFS)
+	})
+	return templates, templatesErr
+}
+
+func parseTemplates(fsys fs.FS) (map[string]*template.Template, error) {
+	files, err := fs.Glob(fsys, templateGlob)
+	if err != nil {
+		return nil, err
+	}
+
+	pages := make(map[string]*template.Template, len(files))
+	for _, file := range files {
+		name := strings.TrimSuffix(path.Base(file), path.Ext(file))
+
+		tmpl, err := template.New(path.Base(file)).Funcs(templateFuncs).ParseFS(fsys, layoutGlob, partialGlob, file)
+		if err != nil {
+			return nil, fmt.Errorf("parse email template %s: %w", name, err)
+		}
+		for _, required := range []string{TEMPLATE_LAYOUT, "subject", "content"} {
+			if tmpl.Lookup(required) == nil {
+				return nil, fmt.Errorf("email template %s does not define %q", name, required)
+			}
+		}
+
+		pages[name] = tmpl
+	}
+
+	return pages, nil
+}
+
+// dict builds a map from key/value pairs so partials can take several
+// arguments: {{ template "button" dict "URL" .Verify "Label" "Verify" }}.
+func dict(pairs ...any) (map[string]any, error) {
+	if len(pairs)%2 != 0 {
+		return nil, fmt.Errorf("dict expects key/value pairs, got %d arguments", len(pairs))
+	}
+
+	values := make(map[string]any, len(pairs)/2)
+	for i := 0; i < len(pairs); i += 2 {
+		key, ok := pairs[i].(string)
+		if !ok {
+			return nil, fmt.Errorf("dict key %v is not a string", pairs[i])
+		}
+		values[key] = pairs[i+1]
+	}
+	return values, nil
+}
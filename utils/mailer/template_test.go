@@ -0,0 +1,146 @@
+package mailer
+
+import (
+	"strings"
+	"testing"
+	"testing/fstest"
+)
+
+func TestRender(t *testing.T) {
+	data := map[string]any{
+		"Email":  "user@example.com",
+		"Verify": "https://example.com/verify?token=abc",
+	}
+
+	tests := []struct {
+		name        string
+		locale      string
+		wantSubject string
+		wantLang    string
+		wantLink    string
+	}{
+		{name: "locale variant", locale: "en", wantSubject: "Backend Boilerplate - Verification Email", wantLang: `lang="en"`, wantLink: "Verify Your Account: https://example.com/verify?token=abc"},
+		{name: "default template", locale: "id", wantSubject: "Backend Boilerplate - Verifikasi Email", wantLang: `lang="id"`},
+		{name: "unknown locale falls back", locale: "fr", wantSubject: "Backend Boilerplate - Verifikasi Email", wantLang: `lang="id"`},
+		{name: "no locale falls back", locale: "", wantSubject: "Backend Boilerplate - Verifikasi Email", wantLang: `lang="id"`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			email, err := Render("verification_email", tt.locale, data)
+			if err != nil {
+				t.Fatalf("Render() error = %v", err)
+			}
+
+			if email.Subject != tt.wantSubject {
+				t.Errorf("subject = %q, want %q", email.Subject, tt.wantSubject)
+			}
+			if !strings.Contains(email.Body, tt.wantLang) {
+				t.Errorf("body does not contain %s", tt.wantLang)
+			}
+			if !strings.Contains(email.Body, "user@example.com") {
+				t.Error("body does not contain the data")
+			}
+			if tt.wantLink != "" && !strings.Contains(email.Text, tt.wantLink) {
+				t.Errorf("text part = %q, want it to contain %q", email.Text, tt.wantLink)
+			}
+			if strings.Contains(email.Text, "<") || strings.Contains(email.Text, "font-family") {
+				t.Errorf("text part contains markup or styles: %q", email.Text)
+			}
+		})
+	}
+
+	if _, err := Render("missing_email", "en", data); err == nil {
+		t.Error("Render() of an unknown template returned no error")
+	}
+}
+
+func TestTemplateNames(t *testing.T) {
+	names, err := TemplateNames()
+	if err != nil {
+		t.Fatalf("TemplateNames() error = %v", err)
+	}
+
+	for _, name := range names {
+		if strings.Contains(name, ".") {
+			t.Errorf("TemplateNames() lists locale variant %q", name)
+		}
+	}
+	if !strings.Contains(strings.Join(names, " "), "verification_email") {
+		t.Errorf("TemplateNames() = %v, want verification_email among them", names)
+	}
+}
+
+func TestParseTemplates(t *testing.T) {
+	const (
+		layout  = `{{ define "layout" }}<html>{{ template "content" . }}</html>{{ end }}`
+		partial = `{{ define "button" }}<a href="{{ .URL }}">{{ .Label }}</a>{{ end }}`
+		page    = `{{ define "subject" }}Hi{{ end }}{{ define "content" }}<p>Hello</p>{{ end }}`
+	)
+
+	tests := []struct {
+		name    string
+		layout  string
+		page    string
+		wantErr string
+	}{
+		{name: "complete page", layout: layout, page: page},
+		{name: "page without subject", layout: layout, page: `{{ define "content" }}x{{ end }}`, wantErr: `does not define "subject"`},
+		{name: "page without content", layout: `{{ define "layout" }}<html></html>{{ end }}`, page: `{{ define "subject" }}Hi{{ end }}`, wantErr: `does not define "content"`},
+		{name: "no layout", layout: `{{ define "other" }}{{ end }}`, page: page, wantErr: `does not define "layout"`},
+		{name: "syntax error", layout: layout, page: `{{ define "subject" }}`, wantErr: "parse email template welcome"},
+		{name: "unknown function", layout: layout, page: `{{ define "subject" }}{{ upper "hi" }}{{ end }}`, wantErr: "parse email template welcome"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pages, err := parseTemplates(fstest.MapFS{
+				"template/layouts/base.html":       {Data: []byte(tt.layout)},
+				"template/partials/button.html":    {Data: []byte(partial)},
+				"template/welcome.html":            {Data: []byte(tt.page)},
+				"template/welcome.en.html":         {Data: []byte(page)},
+				"template/nested/ignored.txt.html": {Data: []byte("not a page")},
+			})
+
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("parseTemplates() error = %v, want one containing %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("parseTemplates() error = %v", err)
+			}
+
+			if len(pages) != 2 || pages["welcome"] == nil || pages["welcome.en"] == nil {
+				t.Errorf("parseTemplates() pages = %v, want welcome and welcome.en", pages)
+			}
+		})
+	}
+}
+
+func TestDict(t *testing.T) {
+	values, err := dict("URL", "https://example.com", "Label", "Open", "Count", 2)
+	if err != nil {
+		t.Fatalf("dict() error = %v", err)
+	}
+	if values["URL"] != "https://example.com" || values["Label"] != "Open" || values["Count"] != 2 {
+		t.Errorf("dict() = %v", values)
+	}
+
+	tests := []struct {
+		name  string
+		pairs []any
+	}{
+		{name: "odd number of arguments", pairs: []any{"URL", "https://example.com", "Label"}},
+		{name: "key is not a string", pairs: []any{1, "one"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := dict(tt.pairs...); err == nil {
+				t.Errorf("dict(%v) returned no error", tt.pairs)
+			}
+		})
+	}
+}
@@ -0,0 +1,101 @@
+package mailer
+
+import (
+	"regexp"
+	"strings"
+
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+)
+
+var (
+	blankLines = regexp.MustCompile(`\n{3,}`)
+
+	// textBlockElements start on a new line in the plain-text part.
+	textBlockElements = map[atom.Atom]bool{
+		atom.P: true, atom.Div: true, atom.Br: true, atom.Li: true, atom.Tr: true, atom.Table: true,
+		atom.H1: true, atom.H2: true, atom.H3: true, atom.H4: true, atom.H5: true, atom.H6: true,
+		atom.Ul: true, atom.Ol: true, atom.Hr: true,
+	}
+
+	// textSkippedElements have no readable content.
+	textSkippedElements = map[atom.Atom]bool{
+		atom.Head: true, atom.Style: true, atom.Script: true, atom.Img: true, atom.Title: true,
+	}
+)
+
+// HTMLToText turns a rendered email into its plain-text alternative: block
+// elements become paragraphs and links keep their URL next to the label.
+func HTMLToText(source string) (string, error) {
+	doc, err := html.Parse(strings.NewReader(source))
+	if err != nil {
+		return "", err
+	}
+
+	var b strings.Builder
+	writeText(&b, doc)
+
+	lines := strings.Split(b.String(), "\n")
+	for i, line := range lines {
+		lines[i] = strings.TrimSpace(line)
+	}
+	text := blankLines.ReplaceAllString(strings.Join(lines, "\n"), "\n\n")
+	return strings.TrimSpace(text) + "\n", nil
+}
+
+func writeText(b *strings.Builder, n *html.Node) {
+	switch n.Type {
+	case html.TextNode:
+		b.WriteString(strings.Join(strings.Fields(n.Data), " "))
+		if strings.TrimSpace(n.Data) != "" && strings.HasSuffix(n.Data, " ") {
+			b.WriteString(" ")
+		}
+		return
+	case html.ElementNode:
+		if textSkippedElements[n.DataAtom] {
+			return
+		}
+	}
+
+	block := n.Type == html.ElementNode && textBlockElements[n.DataAtom]
+	if block {
+		b.WriteString("\n\n")
+	}
+
+	if n.Type == html.ElementNode && n.DataAtom == atom.A {
+		writeLink(b, n)
+	} else {
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			writeText(b, c)
+		}
+	}
+
+	if block {
+		b.WriteString("\n\n")
+	}
+}
+
+// writeLink prints "label: url", or only the url when the label is the url.
+func writeLink(b *strings.Builder, n *html.Node) {
+	var label strings.Builder
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		writeText(&label, c)
+	}
+
+	href := ""
+	for _, attr := range n.Attr {
+		if attr.Key == "href" {
+			href = strings.TrimSpace(attr.Val)
+		}
+	}
+
+	text := strings.TrimSpace(label.String())
+	switch {
+	case href == "" || href == text:
+		b.WriteString(text)
+	case text == "":
+		b.WriteString(href)
+	default:
+		b.WriteString(text + ": " + href)
+	}
+}
@@ -0,0 +1,42 @@
+package mailer
+
+import "testing"
+
+func TestHTMLToText(t *testing.T) {
+	tests := []struct {
+		name string
+		html string
+		want string
+	}{
+		{name: "link with label", html: `<p>Click <a href="https://example.com/verify?token=abc">Verify</a></p>`, want: "Click Verify: https://example.com/verify?token=abc\n"},
+		{name: "link labelled with its url", html: `<a href=" https://example.com ">https://example.com</a>`, want: "https://example.com\n"},
+		{name: "link without href", html: `<a>Verify</a>`, want: "Verify\n"},
+		{name: "link without label", html: `<a href="https://example.com"><img src="logo.png"></a>`, want: "https://example.com\n"},
+		{name: "paragraphs", html: `<p>One</p><p>Two</p>`, want: "One\n\nTwo\n"},
+		{name: "line break", html: `Line<br>Next`, want: "Line\n\nNext\n"},
+		{name: "list items", html: `<ul><li>a</li><li>b</li></ul>`, want: "a\n\nb\n"},
+		{name: "heading and table", html: `<h1>Title</h1><table><tr><td>cell</td></tr></table>`, want: "Title\n\ncell\n"},
+		{name: "inline elements keep spacing", html: `<p>Hello, <b>Ann</b>!</p>`, want: "Hello, Ann!\n"},
+		{name: "whitespace collapsed", html: "<p>\n    Hello,\n    world   </p>", want: "Hello, world\n"},
+		{name: "nested blocks", html: `<div><div><p>Deep</p></div></div><p>Next</p>`, want: "Deep\n\nNext\n"},
+		{
+			name: "skipped elements",
+			html: `<html><head><title>Subject</title><style>p { color: red }</style></head>` +
+				`<body><script>track()</script><img src="header.png" alt="Header"><p>Body</p></body></html>`,
+			want: "Body\n",
+		},
+		{name: "empty", html: ``, want: "\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := HTMLToText(tt.html)
+			if err != nil {
+				t.Fatalf("HTMLToText() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("HTMLToText() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,144 @@
+package mailer
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"mime"
+	"time"
+
+	"gopkg.in/gomail.v2"
+)
+
+const (
+	TRANSPORT_SMTP   = "smtp"
+	TRANSPORT_FILE   = "file"
+	TRANSPORT_STDOUT = "stdout"
+	TRANSPORT_MEMORY = "memory"
+)
+
+type (
+	// Message is a rendered email ready to hand to a Transport. Build one
+	// with Mailer.NewMessage rather than by hand.
+	Message struct {
+		From        string
+		To          []string
+		Cc          []string
+		Bcc         []string
+		ReplyTo     string
+		Subject     string
+		HTML        string
+		Text        string
+		Headers     map[string][]string
+		Attachments []Attachment
+		Inline      []Attachment
+		Date        time.Time
+	}
+
+	// Attachment is a file sent with a message. Inline attachments are
+	// referenced from the HTML as cid:<Name>.
+	Attachment struct {
+		Name        string
+		ContentType string
+		Content     []byte
+	}
+
+	// Transport moves a message out of the application. SMTP is the only
+	// one that delivers, the others keep mail local for development.
+	Transport interface {
+		Name() string
+		Send(ctx context.Context, msg Message) error
+	}
+
+	// BatchTransport is implemented by transports that can send several
+	// messages over one connection. The returned slice holds one error per
+	// message, nil for the ones that were sent.
+	BatchTransport interface {
+		Transport
+		SendBatch(ctx context.Context, msgs []Message) []error
+	}
+
+	// Mailbox is implemented by transports that keep what they send, it
+	// backs the /dev/mailbox endpoint.
+	Mailbox interface {
+		Messages() []CapturedMessage
+		Message(id string) (CapturedMessage, bool)
+		Clear()
+	}
+)
+
+// Recipients is every address the message is delivered to, Bcc included.
+func (msg Message) Recipients() []string {
+	recipients := make([]string, 0, len(msg.To)+len(msg.Cc)+len(msg.Bcc))
+	recipients = append(recipients, msg.To...)
+	recipients = append(recipients, msg.Cc...)
+	return append(recipients, msg.Bcc...)
+}
+
+// gomailMessage builds the MIME message shared by the SMTP and file
+// transports. With a text part the message is multipart/alternative, plain
+// text first so clients prefer the HTML. Bcc is used for the envelope only,
+// gomail never writes it into the headers.
+func gomailMessage(msg Message) *gomail.Message {
+	m := gomail.NewMessage()
+	m.SetHeaders(msg.Headers)
+	m.SetHeader("From", msg.From)
+	m.SetHeader("To", msg.To...)
+	if len(msg.Cc) > 0 {
+		m.SetHeader("Cc", msg.Cc...)
+	}
+	if len(msg.Bcc) > 0 {
+		m.SetHeader("Bcc", msg.Bcc...)
+	}
+	if msg.ReplyTo != "" {
+		m.SetHeader("Reply-To", msg.ReplyTo)
+	}
+	m.SetHeader("Subject", msg.Subject)
+	m.SetDateHeader("Date", msg.Date)
+	if msg.Text != "" {
+		m.SetBody("text/plain", msg.Text)
+		m.AddAlternative("text/html", msg.HTML)
+	} else {
+		m.SetBody("text/html", msg.HTML)
+	}
+
+	for _, attachment := range msg.Attachments {
+		m.Attach(attachment.Name, attachment.fileSettings()...)
+	}
+	for _, inline := range msg.Inline {
+		m.Embed(inline.Name, inline.fileSettings()...)
+	}
+	return m
+}
+
+func (a Attachment) fileSettings() []gomail.FileSetting {
+	settings := []gomail.FileSetting{
+		gomail.Rename(a.Name),
+		gomail.SetCopyFunc(func(w io.Writer) error {
+			_, err := io.Copy(w, bytes.NewReader(a.Content))
+			return err
+		}),
+	}
+	if contentType := a.contentType(); contentType != "" {
+		settings = append(settings, gomail.SetHeader(map[string][]string{
+			"Content-Type": {contentType},
+		}))
+	}
+	return settings
+}
+
+// contentType adds the file name to ContentType, quoted and encoded as the
+// name requires. It is empty when ContentType is missing or malformed, gomail
+// then falls back to the type of the file extension.
+func (a Attachment) contentType() string {
+	if a.ContentType == "" {
+		return ""
+	}
+
+	mediaType, params, err := mime.ParseMediaType(a.ContentType)
+	if err != nil {
+		return ""
+	}
+	params["name"] = a.Name
+	return mime.FormatMediaType(mediaType, params)
+}
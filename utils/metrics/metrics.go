@@ -0,0 +1,151 @@
+package metrics
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+const NAMESPACE = "boilerplate"
+
+var (
+	httpRequestsTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: NAMESPACE,
+		Subsystem: "http",
+		Name:      "requests_total",
+		Help:      "HTTP requests by route, method and status code.",
+	}, []string{"method", "route", "status"})
+
+	httpRequestDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: NAMESPACE,
+		Subsystem: "http",
+		Name:      "request_duration_seconds",
+		Help:      "HTTP request latency by route and method.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"method", "route"})
+
+	httpRequestsInFlight = promauto.NewGauge(prometheus.GaugeOpts{
+		Namespace: NAMESPACE,
+		Subsystem: "http",
+		Name:      "requests_in_flight",
+		Help:      "HTTP requests currently being served.",
+	})
+
+	dbQueryDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: NAMESPACE,
+		Subsystem: "db",
+		Name:      "query_duration_seconds",
+		Help:      "GORM statement latency by operation and table.",
+		Buckets:   []float64{.001, .0025, .005, .01, .025, .05, .1, .25, .5, 1, 2.5},
+	}, []string{"operation", "table"})
+
+	dbQueryErrors = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: NAMESPACE,
+		Subsystem: "db",
+		Name:      "query_errors_total",
+		Help:      "GORM statements that returned an error other than record not found.",
+	}, []string{"operation", "table"})
+
+	registrationsTotal = promauto.NewCounter(prometheus.CounterOpts{
+		Namespace: NAMESPACE,
+		Name:      "user_registrations_total",
+		Help:      "Completed user registrations.",
+	})
+
+	loginsTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: NAMESPACE,
+		Name:      "logins_total",
+		Help:      "Password login attempts by result.",
+	}, []string{"result"})
+
+	emailsTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: NAMESPACE,
+		Name:      "emails_total",
+		Help:      "Emails handed to the SMTP server by result.",
+	}, []string{"result"})
+
+	emailsDeadLetteredTotal = promauto.NewCounter(prometheus.CounterOpts{
+		Namespace: NAMESPACE,
+		Name:      "emails_dead_lettered_total",
+		Help:      "Outbox emails given up on after the last retry.",
+	})
+
+	tripayWebhooksTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: NAMESPACE,
+		Name:      "tripay_webhooks_total",
+		Help:      "Tripay payment callbacks by payment status and outcome.",
+	}, []string{"status", "outcome"})
+)
+
+// TRIPAY_STATUSES bounds the status label, the payload is not trusted until
+// its signature has been checked.
+var TRIPAY_STATUSES = []string{"PAID", "UNPAID", "FAILED", "EXPIRED", "REFUND"}
+
+// Handler serves every registered collector in the Prometheus text format.
+func Handler() http.Handler {
+	return promhttp.Handler()
+}
+
+func HTTPRequestStarted() {
+	httpRequestsInFlight.Inc()
+}
+
+func HTTPRequestFinished() {
+	httpRequestsInFlight.Dec()
+}
+
+func ObserveHTTPRequest(method, route string, status int, duration time.Duration) {
+	httpRequestsTotal.WithLabelValues(method, route, strconv.Itoa(status)).Inc()
+	httpRequestDuration.WithLabelValues(method, route).Observe(duration.Seconds())
+}
+
+func ObserveDBQuery(operation, table string, duration time.Duration, failed bool) {
+	dbQueryDuration.WithLabelValues(operation, table).Observe(duration.Seconds())
+	if failed {
+		dbQueryErrors.WithLabelValues(operation, table).Inc()
+	}
+}
+
+func UserRegistered() {
+	registrationsTotal.Inc()
+}
+
+func Login(success bool) {
+	loginsTotal.WithLabelValues(result(success, "success", "failure")).Inc()
+}
+
+func EmailSent(success bool) {
+	emailsTotal.WithLabelValues(result(success, "sent", "failed")).Inc()
+}
+
+func EmailDeadLettered() {
+	emailsDeadLetteredTotal.Inc()
+}
+
+func TripayWebhook(status, outcome string) {
+	status = strings.ToUpper(status)
+	known := false
+	for _, s := range TRIPAY_STATUSES {
+		if s == status {
+			known = true
+			break
+		}
+	}
+	if !known {
+		status = "OTHER"
+	}
+
+	tripayWebhooksTotal.WithLabelValues(status, outcome).Inc()
+}
+
+func result(ok bool, success, failure string) string {
+	if ok {
+		return success
+	}
+	return failure
+}
@@ -9,6 +9,8 @@ import (
 	"net/http"
 
 	"github.com/Shabrinashsf/go-gin-gorm-boilerplate/dto"
+	"github.com/Shabrinashsf/go-gin-gorm-boilerplate/utils/tracing"
+	"go.opentelemetry.io/otel/attribute"
 )
 
 type Client struct {
@@ -30,7 +32,17 @@ func (c Client) BaseUrl() string {
 	return "https://tripay.co.id/api"
 }
 
-func (c *Client) CreateTransaction(ctx context.Context, req dto.TripayOrderRequest) (dto.TripayResponse, error) {
+// httpClient propagates the W3C trace context to Tripay on every request.
+var httpClient = tracing.NewHTTPClient()
+
+func (c *Client) CreateTransaction(ctx context.Context, req dto.TripayOrderRequest) (res dto.TripayResponse, err error) {
+	ctx, span := tracing.Start(ctx, "tripay.CreateTransaction",
+		attribute.String("tripay.merchant_ref", req.MerchantRef),
+		attribute.String("tripay.method", req.Method),
+		attribute.String("tripay.mode", c.Mode),
+	)
+	defer func() { tracing.End(span, err) }()
+
 	if c.signature.MerchanReff == "" {
 		return dto.TripayResponse{}, errors.New("signature not set")
 	}
@@ -57,7 +69,7 @@ func (c *Client) CreateTransaction(ctx context.Context, req dto.TripayOrderReque
 	httpReq.Header.Set("Content-Type", "application/json")
 	httpReq.Header.Set("Authorization", "Bearer "+c.ApiKey)
 
-	resp, err := http.DefaultClient.Do(httpReq)
+	resp, err := httpClient.Do(httpReq)
 	if err != nil {
 		return dto.TripayResponse{}, err
 	}
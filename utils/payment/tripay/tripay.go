@@ -2,26 +2,26 @@ package tripay
 
 import (
 	"context"
-	"os"
 
+	"github.com/Shabrinashsf/go-gin-gorm-boilerplate/config"
 	"github.com/Shabrinashsf/go-gin-gorm-boilerplate/dto"
 )
 
-func CreateTripayTransaction(ctx context.Context, invoice dto.TripayOrderRequest) (dto.TripayResponse, error) {
+func CreateTripayTransaction(ctx context.Context, cfg config.TripayConfig, invoice dto.TripayOrderRequest) (dto.TripayResponse, error) {
 	// 1. Create Signature
 	sig := Signature{
 		Amount:       int64(invoice.Amount),
-		PrivateKey:   os.Getenv("TRIPAY_PRIVATE_KEY"),
-		MerchantCode: os.Getenv("TRIPAY_MERCHANT_CODE"),
+		PrivateKey:   cfg.PrivateKey,
+		MerchantCode: cfg.MerchantCode,
 		MerchanReff:  invoice.MerchantRef,
 	}
 
 	// 2. Create Client
 	client := Client{
-		MerchantCode: os.Getenv("TRIPAY_MERCHANT_CODE"),
-		ApiKey:       os.Getenv("TRIPAY_API_KEY"),
-		PrivateKey:   os.Getenv("TRIPAY_PRIVATE_KEY"),
-		Mode:         os.Getenv("APP_ENV"),
+		MerchantCode: cfg.MerchantCode,
+		ApiKey:       cfg.APIKey,
+		PrivateKey:   cfg.PrivateKey,
+		Mode:         cfg.Mode,
 	}
 
 	// 3. Set Signature
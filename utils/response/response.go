@@ -1,11 +1,19 @@
 package response
 
+import (
+	"github.com/Shabrinashsf/go-gin-gorm-boilerplate/constants"
+	"github.com/Shabrinashsf/go-gin-gorm-boilerplate/utils/i18n"
+	"github.com/gin-gonic/gin"
+)
+
 type Response struct {
-	Status  bool   `json:"status"`
-	Message string `json:"message"`
-	Error   any    `json:"error,omitempty"`
-	Data    any    `json:"data,omitempty"`
-	Meta    any    `json:"meta,omitempty"`
+	Status    bool   `json:"status"`
+	Message   string `json:"message"`
+	Code      string `json:"code,omitempty"`
+	Error     any    `json:"error,omitempty"`
+	Data      any    `json:"data,omitempty"`
+	Meta      any    `json:"meta,omitempty"`
+	RequestID string `json:"request_id,omitempty"`
 }
 
 type EmptyObj struct{}
@@ -28,3 +36,24 @@ func BuildResponseFailed(message string, err string, data any) Response {
 	}
 	return res
 }
+
+// JSON writes res with its message translated into the request locale.
+func JSON(ctx *gin.Context, status int, res Response) {
+	res.Message = i18n.T(i18n.FromContext(ctx.Request.Context()), res.Message)
+	ctx.JSON(status, res)
+}
+
+// Abort writes a failed response and stops the handler chain. The request ID
+// is echoed in the body so a client can quote it when reporting the error.
+func Abort(ctx *gin.Context, status int, res Response) {
+	res.Message = i18n.T(i18n.FromContext(ctx.Request.Context()), res.Message)
+	res.RequestID = ctx.GetString(constants.CTX_KEY_REQUEST_ID)
+	ctx.AbortWithStatusJSON(status, res)
+}
+
+// Fail hands err to middleware.ErrorHandler, which picks the status, code
+// and client-facing error. message becomes the response message.
+func Fail(ctx *gin.Context, message string, err error) {
+	_ = ctx.Error(err).SetMeta(message)
+	ctx.Abort()
+}
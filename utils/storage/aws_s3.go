@@ -5,30 +5,35 @@ import (
 	"fmt"
 	"io"
 	"mime/multipart"
-	"os"
 	"strings"
 	"sync"
 
+	appconfig "github.com/Shabrinashsf/go-gin-gorm-boilerplate/config"
 	"github.com/Shabrinashsf/go-gin-gorm-boilerplate/utils"
+	"github.com/Shabrinashsf/go-gin-gorm-boilerplate/utils/logger"
+	"github.com/Shabrinashsf/go-gin-gorm-boilerplate/utils/tracing"
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/config"
 	"github.com/aws/aws-sdk-go-v2/credentials"
 	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 )
 
 type (
 	AwsS3 interface {
-		UploadFile(filename string, file *multipart.FileHeader, folderName string, mv ...string) (string, error)
-		UpdateFile(objectKey string, f *multipart.FileHeader, mv ...string) (string, error)
-		DeleteFile(objectKey string) error
+		UploadFile(ctx context.Context, filename string, file *multipart.FileHeader, folderName string, mv ...string) (string, error)
+		UpdateFile(ctx context.Context, objectKey string, f *multipart.FileHeader, mv ...string) (string, error)
+		DeleteFile(ctx context.Context, objectKey string) error
 		GetPublicLink(objectKey string) string
 		GetObjectKeyFromLink(link string) string
-		GetFile(objectKey string) (io.ReadCloser, string, string, error)
+		GetFile(ctx context.Context, objectKey string) (io.ReadCloser, string, string, error)
 		IsOldCloudHostLink(link string) bool
 		ConvertOldLinkToObjectKey(link string) string
+		Ping(ctx context.Context) error
 		Begin() AwsS3
 		Commit()
-		Rollback()
+		Rollback(ctx context.Context)
 	}
 
 	action struct {
@@ -45,15 +50,15 @@ type (
 	}
 )
 
-func NewAwsS3() AwsS3 {
-	bucket := os.Getenv("S3_BUCKET")
-	region := os.Getenv("AWS_REGION")
+func NewAwsS3(awsConfig appconfig.AWSConfig) AwsS3 {
+	bucket := awsConfig.Bucket
+	region := awsConfig.Region
 
 	cfg, err := config.LoadDefaultConfig(context.TODO(),
 		config.WithRegion(region),
 		config.WithCredentialsProvider(credentials.NewStaticCredentialsProvider(
-			os.Getenv("AWS_ACCESS_KEY"),
-			os.Getenv("AWS_SECRET_KEY"),
+			awsConfig.AccessKey,
+			awsConfig.SecretKey,
 			"",
 		)),
 	)
@@ -74,7 +79,7 @@ func NewAwsS3() AwsS3 {
 	}
 }
 
-func (a *awsS3) UploadFile(filename string, f *multipart.FileHeader, folderName string, mv ...string) (string, error) {
+func (a *awsS3) UploadFile(ctx context.Context, filename string, f *multipart.FileHeader, folderName string, mv ...string) (string, error) {
 	file, err := f.Open()
 	if err != nil {
 		return "", err
@@ -102,12 +107,14 @@ func (a *awsS3) UploadFile(filename string, f *multipart.FileHeader, folderName
 
 	objectKey := fmt.Sprintf("%s/%s", folderName, filename)
 
-	_, err = a.client.PutObject(context.TODO(), &s3.PutObjectInput{
+	ctx, span := a.startSpan(ctx, "s3.PutObject", objectKey)
+	_, err = a.client.PutObject(ctx, &s3.PutObjectInput{
 		Bucket:      aws.String(a.bucket),
 		Key:         aws.String(objectKey),
 		Body:        file,
 		ContentType: aws.String(mimetype),
 	})
+	tracing.End(span, err)
 	if err != nil {
 		return "", err
 	}
@@ -117,7 +124,7 @@ func (a *awsS3) UploadFile(filename string, f *multipart.FileHeader, folderName
 	return objectKey, nil
 }
 
-func (a *awsS3) UpdateFile(objectKey string, f *multipart.FileHeader, mv ...string) (string, error) {
+func (a *awsS3) UpdateFile(ctx context.Context, objectKey string, f *multipart.FileHeader, mv ...string) (string, error) {
 	file, err := f.Open()
 	if err != nil {
 		return "", err
@@ -143,12 +150,14 @@ func (a *awsS3) UpdateFile(objectKey string, f *multipart.FileHeader, mv ...stri
 		}
 	}
 
-	_, err = a.client.PutObject(context.TODO(), &s3.PutObjectInput{
+	ctx, span := a.startSpan(ctx, "s3.PutObject", objectKey)
+	_, err = a.client.PutObject(ctx, &s3.PutObjectInput{
 		Bucket:      aws.String(a.bucket),
 		Key:         aws.String(objectKey),
 		Body:        file,
 		ContentType: aws.String(mimetype),
 	})
+	tracing.End(span, err)
 	if err != nil {
 		return "", err
 	}
@@ -157,11 +166,13 @@ func (a *awsS3) UpdateFile(objectKey string, f *multipart.FileHeader, mv ...stri
 	return objectKey, nil
 }
 
-func (a *awsS3) DeleteFile(objectKey string) error {
-	_, err := a.client.DeleteObject(context.TODO(), &s3.DeleteObjectInput{
+func (a *awsS3) DeleteFile(ctx context.Context, objectKey string) error {
+	ctx, span := a.startSpan(ctx, "s3.DeleteObject", objectKey)
+	_, err := a.client.DeleteObject(ctx, &s3.DeleteObjectInput{
 		Bucket: aws.String(a.bucket),
 		Key:    aws.String(objectKey),
 	})
+	tracing.End(span, err)
 	if err != nil {
 		return err
 	}
@@ -203,11 +214,13 @@ func (a *awsS3) GetObjectKeyFromLink(link string) string {
 	return link
 }
 
-func (a *awsS3) GetFile(objectKey string) (io.ReadCloser, string, string, error) {
-	result, err := a.client.GetObject(context.TODO(), &s3.GetObjectInput{
+func (a *awsS3) GetFile(ctx context.Context, objectKey string) (io.ReadCloser, string, string, error) {
+	ctx, span := a.startSpan(ctx, "s3.GetObject", objectKey)
+	result, err := a.client.GetObject(ctx, &s3.GetObjectInput{
 		Bucket: aws.String(a.bucket),
 		Key:    aws.String(objectKey),
 	})
+	tracing.End(span, err)
 	if err != nil {
 		return nil, "", "", fmt.Errorf("failed to get file from S3: %w", err)
 	}
@@ -230,6 +243,26 @@ func (a *awsS3) GetFile(objectKey string) (io.ReadCloser, string, string, error)
 	return result.Body, contentType, filename, nil
 }
 
+// Ping checks that the bucket exists and the credentials can reach it.
+func (a *awsS3) Ping(ctx context.Context) error {
+	ctx, span := a.startSpan(ctx, "s3.HeadBucket", "")
+	_, err := a.client.HeadBucket(ctx, &s3.HeadBucketInput{
+		Bucket: aws.String(a.bucket),
+	})
+	tracing.End(span, err)
+	return err
+}
+
+func (a *awsS3) startSpan(ctx context.Context, name string, objectKey string) (context.Context, trace.Span) {
+	attrs := []attribute.KeyValue{
+		attribute.String("aws.s3.bucket", a.bucket),
+	}
+	if objectKey != "" {
+		attrs = append(attrs, attribute.String("aws.s3.key", objectKey))
+	}
+	return tracing.Start(ctx, name, attrs...)
+}
+
 func (a *awsS3) Begin() AwsS3 {
 	return &awsS3{
 		client:     a.client,
@@ -245,7 +278,7 @@ func (a *awsS3) Commit() {
 	a.isRollback = false
 }
 
-func (a *awsS3) Rollback() {
+func (a *awsS3) Rollback(ctx context.Context) {
 	var wg sync.WaitGroup
 	errCh := make(chan error, len(a.actions))
 
@@ -256,7 +289,7 @@ func (a *awsS3) Rollback() {
 			wg.Add(1)
 			go func(key string) {
 				defer wg.Done()
-				if err := a.DeleteFile(key); err != nil {
+				if err := a.DeleteFile(ctx, key); err != nil {
 					errCh <- fmt.Errorf("failed to delete file %s: %v", key, err)
 				}
 			}(action.key)
@@ -266,15 +299,8 @@ func (a *awsS3) Rollback() {
 	wg.Wait()
 	close(errCh)
 
-	var errors []error
 	for err := range errCh {
-		errors = append(errors, err)
-	}
-
-	if len(errors) > 0 {
-		for _, err := range errors {
-			fmt.Printf("rollback error: %v\n", err)
-		}
+		logger.FromContext(ctx).Errorf("s3 rollback: %v", err)
 	}
 
 	a.Commit()
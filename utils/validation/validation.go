@@ -0,0 +1,194 @@
+package validation
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/Shabrinashsf/go-gin-gorm-boilerplate/dto"
+	"github.com/Shabrinashsf/go-gin-gorm-boilerplate/utils/i18n"
+	"github.com/gin-gonic/gin/binding"
+	"github.com/go-playground/locales/en"
+	"github.com/go-playground/locales/id"
+	ut "github.com/go-playground/universal-translator"
+	"github.com/go-playground/validator/v10"
+	en_translations "github.com/go-playground/validator/v10/translations/en"
+	id_translations "github.com/go-playground/validator/v10/translations/id"
+)
+
+const PASSWORD_MIN_LENGTH = 8
+
+// NO_TELP_PATTERN accepts Indonesian mobile numbers written as 08xx, 628xx
+// or +628xx, after spaces and dashes are stripped.
+var NO_TELP_PATTERN = regexp.MustCompile(`^(\+62|62|0)8[1-9][0-9]{6,11}$`)
+
+// customTranslations holds the messages for the validators registered here,
+// the built-in tags use the translations shipped with the validator.
+var customTranslations = map[string]map[string]string{
+	"no_telp": {
+		i18n.LOCALE_ID: "{0} harus berupa nomor telepon Indonesia yang valid, contoh 081234567890",
+		i18n.LOCALE_EN: "{0} must be a valid Indonesian phone number, e.g. 081234567890",
+	},
+	"strong_password": {
+		i18n.LOCALE_ID: "{0} minimal " + strconv.Itoa(PASSWORD_MIN_LENGTH) + " karakter dan harus mengandung huruf besar, huruf kecil dan angka",
+		i18n.LOCALE_EN: "{0} must be at least " + strconv.Itoa(PASSWORD_MIN_LENGTH) + " characters and contain an upper case letter, a lower case letter and a digit",
+	},
+}
+
+var translators *ut.UniversalTranslator
+
+// Setup configures gin's validator: errors report JSON field names, the
+// no_telp and strong_password tags become available and messages are
+// translated into Indonesian and English.
+func Setup() error {
+	v, ok := binding.Validator.Engine().(*validator.Validate)
+	if !ok {
+		return errors.New("gin validator is not go-playground/validator")
+	}
+
+	v.RegisterTagNameFunc(fieldName)
+
+	if err := v.RegisterValidation("no_telp", validateNoTelp); err != nil {
+		return err
+	}
+	if err := v.RegisterValidation("strong_password", validateStrongPassword); err != nil {
+		return err
+	}
+
+	translators = ut.New(id.New(), id.New(), en.New())
+	idTrans, _ := translators.GetTranslator(i18n.LOCALE_ID)
+	enTrans, _ := translators.GetTranslator(i18n.LOCALE_EN)
+
+	if err := id_translations.RegisterDefaultTranslations(v, idTrans); err != nil {
+		return err
+	}
+	if err := en_translations.RegisterDefaultTranslations(v, enTrans); err != nil {
+		return err
+	}
+
+	for tag, messages := range customTranslations {
+		for locale, message := range messages {
+			trans, _ := translators.GetTranslator(locale)
+			if err := v.RegisterTranslation(tag, trans, registerMessage(tag, message), translateMessage); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// FieldErrors turns a binding error into one entry per failed field, with
+// messages in locale. It returns nil when err did not come from validation
+// or from decoding the JSON body.
+func FieldErrors(err error, locale string) []dto.FieldError {
+	var validationErrors validator.ValidationErrors
+	if errors.As(err, &validationErrors) {
+		trans := translator(locale)
+		fields := make([]dto.FieldError, 0, len(validationErrors))
+		for _, fe := range validationErrors {
+			fields = append(fields, dto.FieldError{
+				Field:   fieldPath(fe.Namespace()),
+				Rule:    fe.Tag(),
+				Message: fe.Translate(trans),
+			})
+		}
+		return fields
+	}
+
+	var typeError *json.UnmarshalTypeError
+	if errors.As(err, &typeError) {
+		return []dto.FieldError{{
+			Field:   typeError.Field,
+			Rule:    "type",
+			Message: i18n.T(locale, "validation.type", typeError.Field, typeError.Type.String()),
+		}}
+	}
+
+	var syntaxError *json.SyntaxError
+	if errors.As(err, &syntaxError) || errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+		return []dto.FieldError{{
+			Field:   "body",
+			Rule:    "json",
+			Message: i18n.T(locale, "validation.invalid_json"),
+		}}
+	}
+
+	return nil
+}
+
+func translator(locale string) ut.Translator {
+	if translators == nil {
+		return nil
+	}
+	trans, _ := translators.GetTranslator(locale)
+	return trans
+}
+
+// fieldName reports the json name of a field, or the form name for query
+// structs, so errors match what the client sent.
+func fieldName(field reflect.StructField) string {
+	for _, key := range []string{"json", "form"} {
+		name := strings.SplitN(field.Tag.Get(key), ",", 2)[0]
+		if name == "-" {
+			return ""
+		}
+		if name != "" {
+			return name
+		}
+	}
+	return field.Name
+}
+
+// fieldPath drops the root struct from a namespace such as
+// "UserRegistrationRequest.no_telp".
+func fieldPath(namespace string) string {
+	if i := strings.Index(namespace, "."); i >= 0 {
+		return namespace[i+1:]
+	}
+	return namespace
+}
+
+func validateNoTelp(fl validator.FieldLevel) bool {
+	number := strings.NewReplacer(" ", "", "-", "").Replace(fl.Field().String())
+	return NO_TELP_PATTERN.MatchString(number)
+}
+
+func validateStrongPassword(fl validator.FieldLevel) bool {
+	password := fl.Field().String()
+	if len(password) < PASSWORD_MIN_LENGTH {
+		return false
+	}
+
+	var upper, lower, digit bool
+	for _, r := range password {
+		switch {
+		case unicode.IsUpper(r):
+			upper = true
+		case unicode.IsLower(r):
+			lower = true
+		case unicode.IsDigit(r):
+			digit = true
+		}
+	}
+	return upper && lower && digit
+}
+
+func registerMessage(tag string, message string) validator.RegisterTranslationsFunc {
+	return func(trans ut.Translator) error {
+		return trans.Add(tag, message, true)
+	}
+}
+
+func translateMessage(trans ut.Translator, fe validator.FieldError) string {
+	message, err := trans.T(fe.Tag(), fe.Field())
+	if err != nil {
+		return fe.Error()
+	}
+	return message
+}